@@ -0,0 +1,32 @@
+package builder
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ListenAddr returns the config's "host:port" address, ready for net.Listen.
+// IPv6 literals are bracketed ("[::1]:8080"); IPv4 addresses and hostnames are not.
+func (c *ServerConfig) ListenAddr() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// normalizeHost strips the brackets people often write around IPv6 literals,
+// so "[::1]" and "::1" are stored the same way.
+func normalizeHost(host string) string {
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		return host[1 : len(host)-1]
+	}
+	return host
+}
+
+// validateHost accepts hostnames, IPv4 and IPv6 literals.
+// A colon can only appear in an IPv6 literal, so anything else with one
+// (like "localhost:8080") is rejected - the port belongs in Port().
+func validateHost(host string) *ValidationError {
+	if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+		return &ValidationError{Field: "Host", Message: "host must be a hostname or IP address without a port"}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package builder
+
+import "testing"
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"127.0.0.1", "127.0.0.1:8080"},
+		{"localhost", "localhost:8080"},
+		{"api.example.com", "api.example.com:8080"},
+		{"::1", "[::1]:8080"},
+		{"[::1]", "[::1]:8080"},
+		{"2001:db8::42", "[2001:db8::42]:8080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			config, err := NewServerConfigBuilder().Host(tt.host).Port(8080).Build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if got := config.ListenAddr(); got != tt.want {
+				t.Errorf("ListenAddr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRejectsHostWithPort(t *testing.T) {
+	for _, host := range []string{"localhost:8080", "127.0.0.1:80", "[::1]:8080", "not:an:ip:zz"} {
+		_, err := NewServerConfigBuilder().Host(host).Build()
+		verr, ok := err.(*ValidationError)
+		if !ok || verr.Field != "Host" {
+			t.Errorf("Host(%q): Build = %v, want a Host ValidationError", host, err)
+		}
+	}
+}
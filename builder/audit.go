@@ -0,0 +1,31 @@
+package builder
+
+// productionPorts are the ports a config serving real traffic usually listens on
+var productionPorts = map[int]bool{80: true, 443: true}
+
+// SecurityAudit returns human-readable findings about insecure settings in a
+// built config, most serious first. A clean config returns an empty list.
+// Unlike Build() validation, findings never reject a config: plain HTTP and
+// debug logging are fine on a laptop, they're just worth a second look before
+// going to production.
+func (c *ServerConfig) SecurityAudit() []string {
+	findings := []string{}
+
+	// A unix socket never leaves the machine, so transport encryption is moot
+	if !c.SSL && c.UnixSocket == "" {
+		findings = append(findings, "SSL disabled")
+	}
+	if c.ReadTimeout <= 0 {
+		findings = append(findings, "no read timeout set (slowloris risk)")
+	}
+	if c.WriteTimeout <= 0 {
+		findings = append(findings, "no write timeout set (slow clients can hold connections open)")
+	}
+	if c.Timeout <= 0 {
+		findings = append(findings, "no request timeout set")
+	}
+	if c.LogLevel == LogDebug && productionPorts[c.Port] && c.UnixSocket == "" {
+		findings = append(findings, "debug logging in production-like port")
+	}
+	return findings
+}
@@ -0,0 +1,46 @@
+package builder
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSecurityAuditHardened(t *testing.T) {
+	cfg := buildOrFatal(t, NewServerConfigBuilder().Host("api.example.com").Port(443).EnableSSL(true))
+	if findings := cfg.SecurityAudit(); len(findings) != 0 {
+		t.Errorf("SecurityAudit = %q, want no findings", findings)
+	}
+}
+
+func TestSecurityAuditInsecure(t *testing.T) {
+	cfg := buildOrFatal(t, NewServerConfigBuilder().
+		Host("api.example.com").
+		Port(80).
+		Level(LogDebug).
+		ReadTimeout(0).
+		Timeout(0))
+	want := []string{
+		"SSL disabled",
+		"no read timeout set (slowloris risk)",
+		"no request timeout set",
+		"debug logging in production-like port",
+	}
+	if got := cfg.SecurityAudit(); !slices.Equal(got, want) {
+		t.Errorf("SecurityAudit =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestSecurityAuditUnixSocket(t *testing.T) {
+	// Neither SSL nor the port matter for a socket that never leaves the machine
+	cfg := buildOrFatal(t, NewServerConfigBuilder().UnixSocket("/run/app.sock").Port(80).Level(LogDebug))
+	if findings := cfg.SecurityAudit(); len(findings) != 0 {
+		t.Errorf("SecurityAudit = %q, want no findings", findings)
+	}
+}
+
+func TestSecurityAuditDebugOffProductionPort(t *testing.T) {
+	cfg := buildOrFatal(t, NewServerConfigBuilder().Host("localhost").Port(8080).Level(LogDebug).EnableSSL(true))
+	if findings := cfg.SecurityAudit(); len(findings) != 0 {
+		t.Errorf("SecurityAudit = %q, want debug logging on 8080 left alone", findings)
+	}
+}
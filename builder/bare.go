@@ -0,0 +1,54 @@
+package builder
+
+import "strings"
+
+// allFields lists every ServerConfig field a bare builder must have set
+var allFields = []string{
+	"Host", "Port", "SSL", "Timeout", "MaxConnections",
+	"ReadTimeout", "WriteTimeout", "DatabaseURL", "CacheEnabled", "LogLevel",
+	"DBMaxOpenConns", "DBMaxIdleConns", "DBConnMaxLifetime", "TLSCertFile", "TLSKeyFile", "UnixSocket",
+}
+
+// NewBareServerConfigBuilder creates a builder in "strict explicit" mode.
+//
+// Compare it with NewServerConfigBuilder: that one fills in sensible defaults
+// so callers only set what they care about. A bare builder starts from the
+// zero value and Build() fails unless every field was set explicitly, even
+// ones whose zero value would be valid (like SSL=false). Nothing about the
+// final config is implied, which some teams prefer for production configs.
+func NewBareServerConfigBuilder() *ServerConfigBuilder {
+	return &ServerConfigBuilder{bare: true}
+}
+
+// markSet records that field was assigned explicitly. Every setter calls it
+// right after assigning, which makes it the place to notify OnSet callbacks.
+func (b *ServerConfigBuilder) markSet(field string) {
+	if b.set == nil {
+		b.set = make(map[string]bool)
+	}
+	b.set[field] = true
+	b.notifySet(field)
+}
+
+// IsSet reports whether field (e.g. "Port") was set through a setter
+// rather than left at its default.
+func (b *ServerConfigBuilder) IsSet(field string) bool {
+	return b.set[field]
+}
+
+// checkAllSet returns an error naming every field that was never set
+func (b *ServerConfigBuilder) checkAllSet() *ValidationError {
+	var missing []string
+	for _, field := range allFields {
+		if !b.set[field] && !(field == "MaxConnections" && b.connsPerCore > 0) && !b.coveredByGroup(field) {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		Field:   missing[0],
+		Message: "bare builder requires every field to be set explicitly; missing: " + strings.Join(missing, ", "),
+	}
+}
@@ -0,0 +1,84 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// fullBare returns a bare builder with every field set
+func fullBare() *ServerConfigBuilder {
+	return NewBareServerConfigBuilder().
+		Host("localhost").
+		Port(8080).
+		EnableSSL(false).
+		Timeout(30 * time.Second).
+		MaxConnections(100).
+		ReadTimeout(10 * time.Second).
+		WriteTimeout(10 * time.Second).
+		DatabaseURL("").
+		EnableCache(false).
+		Level(LogInfo).
+		DBMaxOpenConns(0).
+		DBMaxIdleConns(2).
+		DBConnMaxLifetime(0).
+		TLSCertFile("").
+		TLSKeyFile("").
+		UnixSocket("")
+}
+
+func TestBareBuilderAllSet(t *testing.T) {
+	config, err := fullBare().Build()
+	if err != nil {
+		t.Fatalf("Build with every field set: %v", err)
+	}
+	if config.Port != 8080 || config.SSL {
+		t.Errorf("config = %+v", config)
+	}
+}
+
+func TestBareBuilderRequiresEveryField(t *testing.T) {
+	// Each of these passes with the default builder, which fills in the rest
+	if _, err := NewServerConfigBuilder().Host("localhost").Build(); err != nil {
+		t.Fatalf("default builder: %v", err)
+	}
+
+	_, err := NewBareServerConfigBuilder().Host("localhost").Port(8080).Build()
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Build = %v, want a ValidationError", err)
+	}
+	if verr.Field != "SSL" {
+		t.Errorf("Field = %q, want the first missing field, SSL", verr.Field)
+	}
+	for _, field := range []string{"SSL", "Timeout", "LogLevel", "UnixSocket"} {
+		if !strings.Contains(verr.Message, field) {
+			t.Errorf("message %q doesn't list %s", verr.Message, field)
+		}
+	}
+	if strings.Contains(verr.Message, "Host") || strings.Contains(verr.Message, "Port,") {
+		t.Errorf("message %q lists fields that were set", verr.Message)
+	}
+}
+
+func TestBareBuilderZeroValuesMustBeExplicit(t *testing.T) {
+	for _, field := range []string{"SSL", "DatabaseURL", "CacheEnabled", "UnixSocket"} {
+		t.Run(field, func(t *testing.T) {
+			b := fullBare()
+			delete(b.set, field)
+			if _, err := b.Build(); err == nil || !strings.Contains(err.Error(), field) {
+				t.Errorf("Build without %s = %v, want it reported missing", field, err)
+			}
+		})
+	}
+}
+
+func TestBareBuilderStartsEmpty(t *testing.T) {
+	b := NewBareServerConfigBuilder()
+	if b.config.Port != 0 || b.config.Timeout != 0 || b.config.LogLevel != "" {
+		t.Errorf("bare builder has defaults: %+v", b.config)
+	}
+	if b.IsSet("Port") {
+		t.Error("IsSet(Port) on a fresh bare builder")
+	}
+}
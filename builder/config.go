@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"maps"
+	"time"
+)
+
+// Read-only access to a built ServerConfig
+//
+// Build() hands out a copy, so mutating its fields can't affect the builder.
+// Still, a config is usually shared across an app once built, and changing it
+// in place surprises everyone else holding it. Treat the fields as read-only
+// after Build(): read them through the getters below, and use the With*
+// methods to derive a modified copy instead of editing the original.
+
+func (c *ServerConfig) GetHost() string                     { return c.Host }
+func (c *ServerConfig) GetPort() int                        { return c.Port }
+func (c *ServerConfig) GetSSL() bool                        { return c.SSL }
+func (c *ServerConfig) GetTimeout() time.Duration           { return c.Timeout }
+func (c *ServerConfig) GetMaxConnections() int              { return c.MaxConnections }
+func (c *ServerConfig) GetReadTimeout() time.Duration       { return c.ReadTimeout }
+func (c *ServerConfig) GetWriteTimeout() time.Duration      { return c.WriteTimeout }
+func (c *ServerConfig) GetDatabaseURL() string              { return c.DatabaseURL }
+func (c *ServerConfig) GetCacheEnabled() bool               { return c.CacheEnabled }
+func (c *ServerConfig) GetLogLevel() string                 { return c.LogLevel.String() }
+func (c *ServerConfig) GetTLSCertFile() string              { return c.TLSCertFile }
+func (c *ServerConfig) GetTLSKeyFile() string               { return c.TLSKeyFile }
+func (c *ServerConfig) GetUnixSocket() string               { return c.UnixSocket }
+func (c *ServerConfig) GetDBMaxOpenConns() int              { return c.DBMaxOpenConns }
+func (c *ServerConfig) GetDBMaxIdleConns() int              { return c.DBMaxIdleConns }
+func (c *ServerConfig) GetDBConnMaxLifetime() time.Duration { return c.DBConnMaxLifetime }
+
+// clone returns a copy of the config that shares no mutable state with it
+func (c *ServerConfig) clone() *ServerConfig {
+	copied := *c
+	copied.Extra = cloneExtra(c.Extra)
+	copied.Features = maps.Clone(c.Features)
+	copied.VirtualHosts = cloneVHosts(c.VirtualHosts)
+	copied.set = maps.Clone(c.set)
+	return &copied
+}
+
+// WithPort returns a copy of the config listening on port. The original is unchanged.
+// Like the other With* methods it does not re-run Build() validation.
+func (c *ServerConfig) WithPort(port int) *ServerConfig {
+	copied := c.clone()
+	copied.Port = port
+	copied.markSet("Port")
+	return copied
+}
+
+// WithHost returns a copy of the config bound to host. The original is unchanged.
+func (c *ServerConfig) WithHost(host string) *ServerConfig {
+	copied := c.clone()
+	copied.Host = normalizeHost(host)
+	copied.markSet("Host")
+	return copied
+}
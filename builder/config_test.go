@@ -0,0 +1,75 @@
+package builder
+
+import (
+	"testing"
+	"time"
+)
+
+func buildOrFatal(t *testing.T, b *ServerConfigBuilder) *ServerConfig {
+	t.Helper()
+	config, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return config
+}
+
+func TestWithPortLeavesOriginal(t *testing.T) {
+	original := buildOrFatal(t, NewServerConfigBuilder().Host("localhost").Port(8080))
+
+	changed := original.WithPort(9090)
+	if original.GetPort() != 8080 {
+		t.Errorf("original port = %d after WithPort, want 8080", original.GetPort())
+	}
+	if changed.GetPort() != 9090 || changed.GetHost() != "localhost" {
+		t.Errorf("changed = %s:%d, want localhost:9090", changed.GetHost(), changed.GetPort())
+	}
+	if changed == original {
+		t.Error("WithPort returned the original")
+	}
+}
+
+func TestWithHostSharesNoState(t *testing.T) {
+	original := buildOrFatal(t, NewServerConfigBuilder().
+		Host("localhost").
+		Set("region", "eu").
+		EnableFeature("beta").
+		VirtualHost("api.example.com", func(v *VHostBuilder) { v.EnableSSL(true) }))
+
+	changed := original.WithHost("[::1]")
+	changed.Extra["region"] = "us"
+	changed.Features["beta"] = false
+	*changed.VirtualHosts[0].SSL = false
+
+	if changed.GetHost() != "::1" || original.GetHost() != "localhost" {
+		t.Errorf("hosts = %q and %q, want ::1 and localhost", changed.GetHost(), original.GetHost())
+	}
+	if region, _ := original.GetString("region"); region != "eu" {
+		t.Errorf("original region = %q, want eu", region)
+	}
+	if !original.FeatureEnabled("beta") {
+		t.Error("editing the copy's features changed the original")
+	}
+	if !*original.VirtualHosts[0].SSL {
+		t.Error("editing the copy's virtual host changed the original")
+	}
+}
+
+func TestGetters(t *testing.T) {
+	c := buildOrFatal(t, NewServerConfigBuilder().
+		Host("example.com").
+		Port(443).
+		EnableSSL(true).
+		Timeout(time.Minute).
+		MaxConnections(50).
+		DatabaseURL("postgresql://db/app").
+		EnableCache(true).
+		Level(LogWarn).
+		UnixSocket(""))
+
+	if c.GetHost() != "example.com" || c.GetPort() != 443 || !c.GetSSL() || c.GetTimeout() != time.Minute ||
+		c.GetMaxConnections() != 50 || c.GetDatabaseURL() != "postgresql://db/app" || !c.GetCacheEnabled() ||
+		c.GetLogLevel() != "warn" || c.GetReadTimeout() != 10*time.Second || c.GetWriteTimeout() != 10*time.Second {
+		t.Errorf("getters disagree with the built config: %+v", c)
+	}
+}
@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// CheckConflicts reports configs that would bind the same host:port on one
+// machine. Loopback spellings (localhost, 127.0.0.1, ::1) count as the same
+// host, and a wildcard host (0.0.0.0, ::, or empty) collides with every host
+// on the same port, since it listens on all interfaces.
+// The returned error lists each collision by config index.
+func CheckConflicts(configs ...*ServerConfig) error {
+	var conflicts []string
+	for i := 0; i < len(configs); i++ {
+		for j := i + 1; j < len(configs); j++ {
+			a, b := configs[i], configs[j]
+			if a.UnixSocket != "" || b.UnixSocket != "" {
+				if a.UnixSocket == b.UnixSocket {
+					conflicts = append(conflicts, fmt.Sprintf("configs %d and %d both bind socket %s", i, j, a.UnixSocket))
+				}
+				continue
+			}
+			if a.Port != b.Port || !sameBindHost(a.Host, b.Host) {
+				continue
+			}
+			conflicts = append(conflicts, fmt.Sprintf("configs %d and %d both bind %s",
+				i, j, net.JoinHostPort(a.Host, strconv.Itoa(a.Port))))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("address conflicts: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+func sameBindHost(a, b string) bool {
+	a, b = canonicalHost(a), canonicalHost(b)
+	return a == b || a == "*" || b == "*"
+}
+
+// canonicalHost maps equivalent spellings of a host to one form
+func canonicalHost(host string) string {
+	host = strings.ToLower(normalizeHost(host))
+	if host == "localhost" {
+		return "loopback"
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		switch {
+		case ip.IsUnspecified():
+			return "*"
+		case ip.IsLoopback():
+			return "loopback"
+		}
+		return ip.String()
+	}
+	if host == "" {
+		return "*"
+	}
+	return host
+}
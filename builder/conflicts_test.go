@@ -0,0 +1,60 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func addr(host string, port int) *ServerConfig {
+	return &ServerConfig{Host: host, Port: port}
+}
+
+func TestCheckConflictsClean(t *testing.T) {
+	err := CheckConflicts(
+		addr("localhost", 8080),
+		addr("localhost", 8081),
+		addr("10.0.0.1", 8080),
+		addr("api.example.com", 8080),
+		&ServerConfig{UnixSocket: "/run/a.sock"},
+		&ServerConfig{UnixSocket: "/run/b.sock"},
+	)
+	if err != nil {
+		t.Errorf("CheckConflicts = %v, want nil", err)
+	}
+}
+
+func TestCheckConflictsCollisions(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *ServerConfig
+	}{
+		{"identical", addr("localhost", 8080), addr("localhost", 8080)},
+		{"loopback spellings", addr("localhost", 8080), addr("127.0.0.1", 8080)},
+		{"ipv6 loopback", addr("[::1]", 8080), addr("localhost", 8080)},
+		{"host case", addr("API.example.com", 80), addr("api.example.com", 80)},
+		{"wildcard", addr("0.0.0.0", 8080), addr("10.0.0.1", 8080)},
+		{"empty host", addr("", 8080), addr("api.example.com", 8080)},
+		{"socket", &ServerConfig{UnixSocket: "/run/app.sock"}, &ServerConfig{UnixSocket: "/run/app.sock"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckConflicts(addr("192.0.2.1", 9999), tt.a, tt.b)
+			if err == nil {
+				t.Fatal("CheckConflicts = nil, want a conflict")
+			}
+			if !strings.Contains(err.Error(), "configs 1 and 2") {
+				t.Errorf("error %q doesn't name configs 1 and 2", err)
+			}
+		})
+	}
+}
+
+func TestCheckConflictsListsEveryCollision(t *testing.T) {
+	err := CheckConflicts(addr("localhost", 80), addr("localhost", 80), addr("127.0.0.1", 80))
+	if err == nil {
+		t.Fatal("CheckConflicts = nil, want conflicts")
+	}
+	if got := strings.Count(err.Error(), "both bind"); got != 3 {
+		t.Errorf("error lists %d collisions, want 3: %v", got, err)
+	}
+}
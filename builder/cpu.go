@@ -0,0 +1,14 @@
+package builder
+
+import "runtime"
+
+// numCPU is a variable so the CPU count can be stubbed out
+var numCPU = runtime.NumCPU
+
+// MaxConnectionsFromCPU sizes MaxConnections as perCore × the number of CPUs,
+// computed when Build() runs, so one config adapts to whatever host it's on.
+// An explicit MaxConnections(n) call takes precedence regardless of order.
+func (b *ServerConfigBuilder) MaxConnectionsFromCPU(perCore int) *ServerConfigBuilder {
+	b.connsPerCore = perCore
+	return b
+}
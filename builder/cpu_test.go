@@ -0,0 +1,85 @@
+package builder
+
+import (
+	"testing"
+	"time"
+)
+
+// stubCPUs makes numCPU report n for the rest of the test
+func stubCPUs(t *testing.T, n int) {
+	t.Helper()
+	orig := numCPU
+	numCPU = func() int { return n }
+	t.Cleanup(func() { numCPU = orig })
+}
+
+func TestMaxConnectionsFromCPU(t *testing.T) {
+	stubCPUs(t, 8)
+	tests := []struct {
+		name string
+		b    *ServerConfigBuilder
+		want int
+	}{
+		{"computed", NewServerConfigBuilder().MaxConnectionsFromCPU(25), 200},
+		{"explicit after", NewServerConfigBuilder().MaxConnectionsFromCPU(25).MaxConnections(50), 50},
+		{"explicit before", NewServerConfigBuilder().MaxConnections(50).MaxConnectionsFromCPU(25), 50},
+		{"not used", NewServerConfigBuilder(), 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := tt.b.Host("localhost").Build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if cfg.MaxConnections != tt.want {
+				t.Errorf("MaxConnections = %d, want %d", cfg.MaxConnections, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxConnectionsFromCPUAtBuildTime(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").MaxConnectionsFromCPU(10)
+
+	stubCPUs(t, 2)
+	cfg, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MaxConnections != 20 {
+		t.Errorf("on 2 CPUs: MaxConnections = %d, want 20", cfg.MaxConnections)
+	}
+
+	stubCPUs(t, 4)
+	if cfg, _ = b.Build(); cfg.MaxConnections != 40 {
+		t.Errorf("on 4 CPUs: MaxConnections = %d, want 40", cfg.MaxConnections)
+	}
+}
+
+func TestMaxConnectionsFromCPUSatisfiesBare(t *testing.T) {
+	stubCPUs(t, 4)
+	b := NewBareServerConfigBuilder().
+		Host("localhost").
+		Port(8080).
+		EnableSSL(false).
+		Timeout(30 * time.Second).
+		MaxConnectionsFromCPU(5).
+		ReadTimeout(10 * time.Second).
+		WriteTimeout(10 * time.Second).
+		DatabaseURL("").
+		EnableCache(false).
+		Level(LogInfo).
+		DBMaxOpenConns(0).
+		DBMaxIdleConns(2).
+		DBConnMaxLifetime(0).
+		TLSCertFile("").
+		TLSKeyFile("").
+		UnixSocket("")
+	cfg, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if cfg.MaxConnections != 20 {
+		t.Errorf("MaxConnections = %d, want 20", cfg.MaxConnections)
+	}
+}
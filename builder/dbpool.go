@@ -0,0 +1,61 @@
+package builder
+
+import "time"
+
+// DBPool holds the connection pool settings for the config's database
+type DBPool struct {
+	MaxOpenConns    int           // 0 means unlimited
+	MaxIdleConns    int           // never more than MaxOpenConns when that is set
+	ConnMaxLifetime time.Duration // 0 means connections are reused forever
+}
+
+// DBPoolConfig returns the pool settings, ready to hand to database/sql:
+//
+//	db, _ := sql.Open("postgres", cfg.DatabaseURL)
+//	cfg.DBPoolConfig().Apply(db)
+func (c *ServerConfig) DBPoolConfig() DBPool {
+	return DBPool{
+		MaxOpenConns:    c.DBMaxOpenConns,
+		MaxIdleConns:    c.DBMaxIdleConns,
+		ConnMaxLifetime: c.DBConnMaxLifetime,
+	}
+}
+
+// PoolSetter is the part of *sql.DB that configures its pool.
+// It's an interface so this package doesn't import database/sql.
+type PoolSetter interface {
+	SetMaxOpenConns(n int)
+	SetMaxIdleConns(n int)
+	SetConnMaxLifetime(d time.Duration)
+}
+
+// Apply configures db's pool with these settings
+func (p DBPool) Apply(db PoolSetter) {
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+}
+
+// clampDefaultIdleConns lowers a defaulted DBMaxIdleConns to DBMaxOpenConns,
+// as database/sql itself does, so DBMaxOpenConns(1) alone builds. An idle
+// limit set explicitly is left for validateDBPool to check.
+func (c *ServerConfig) clampDefaultIdleConns() {
+	if !c.isSet("DBMaxIdleConns") && c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		c.DBMaxIdleConns = c.DBMaxOpenConns
+	}
+}
+
+// validateDBPool checks the pool fields against each other
+func validateDBPool(c *ServerConfig) *ValidationError {
+	switch {
+	case c.DBMaxOpenConns < 0:
+		return &ValidationError{Field: "DBMaxOpenConns", Message: "max open connections must not be negative"}
+	case c.DBMaxIdleConns < 0:
+		return &ValidationError{Field: "DBMaxIdleConns", Message: "max idle connections must not be negative"}
+	case c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns:
+		return &ValidationError{Field: "DBMaxIdleConns", Message: "max idle connections must not exceed max open connections"}
+	case c.DBConnMaxLifetime < 0:
+		return &ValidationError{Field: "DBConnMaxLifetime", Message: "connection max lifetime must not be negative"}
+	}
+	return nil
+}
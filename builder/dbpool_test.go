@@ -0,0 +1,86 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDBPoolValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		b         *ServerConfigBuilder
+		wantField string
+	}{
+		{"idle exceeds open", NewServerConfigBuilder().DBMaxOpenConns(5).DBMaxIdleConns(10), "DBMaxIdleConns"},
+		{"negative open", NewServerConfigBuilder().DBMaxOpenConns(-1), "DBMaxOpenConns"},
+		{"negative idle", NewServerConfigBuilder().DBMaxIdleConns(-1), "DBMaxIdleConns"},
+		{"negative lifetime", NewServerConfigBuilder().DBConnMaxLifetime(-time.Second), "DBConnMaxLifetime"},
+		{"idle equals open", NewServerConfigBuilder().DBMaxOpenConns(5).DBMaxIdleConns(5), ""},
+		{"unlimited open", NewServerConfigBuilder().DBMaxOpenConns(0).DBMaxIdleConns(50), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.b.Host("localhost").Build()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Build = %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Field != tt.wantField {
+				t.Errorf("Build = %v, want a %s ValidationError", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestDBPoolDefaultIdleClamped(t *testing.T) {
+	cfg, err := NewServerConfigBuilder().Host("localhost").DBMaxOpenConns(1).Build()
+	if err != nil {
+		t.Fatalf("Build = %v, want the default idle limit lowered to fit", err)
+	}
+	if cfg.DBMaxIdleConns != 1 {
+		t.Errorf("DBMaxIdleConns = %d, want 1", cfg.DBMaxIdleConns)
+	}
+
+	// A layer that only lowers the open limit resolves the same way
+	base := NewServerConfigBuilder().Host("localhost").Layer()
+	override := NewServerConfigBuilder().DBMaxOpenConns(1).Layer()
+	if cfg, err := Resolve(base, override); err != nil || cfg.DBMaxIdleConns != 1 {
+		t.Errorf("Resolve = %v, %v, want DBMaxIdleConns 1", cfg, err)
+	}
+	if err := ValidateJSON([]byte(`{"host": "localhost", "db_max_open_conns": 1}`)); err != nil {
+		t.Errorf("ValidateJSON = %v", err)
+	}
+}
+
+// fakePool records what Apply sets
+type fakePool struct {
+	open, idle int
+	lifetime   time.Duration
+}
+
+func (p *fakePool) SetMaxOpenConns(n int)              { p.open = n }
+func (p *fakePool) SetMaxIdleConns(n int)              { p.idle = n }
+func (p *fakePool) SetConnMaxLifetime(d time.Duration) { p.lifetime = d }
+
+func TestDBPoolConfigApply(t *testing.T) {
+	cfg := buildOrFatal(t, NewServerConfigBuilder().
+		Host("localhost").
+		DBMaxOpenConns(20).
+		DBMaxIdleConns(5).
+		DBConnMaxLifetime(time.Hour))
+
+	var pool fakePool
+	cfg.DBPoolConfig().Apply(&pool)
+	if pool != (fakePool{open: 20, idle: 5, lifetime: time.Hour}) {
+		t.Errorf("applied %+v", pool)
+	}
+
+	// The defaults match database/sql's own
+	if got := buildOrFatal(t, NewServerConfigBuilder().Host("localhost")).DBPoolConfig(); got != (DBPool{MaxIdleConns: 2}) {
+		t.Errorf("default pool = %+v", got)
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func main() {
-	fmt.Println("=== Builder Pattern Demo ===\n")
+	fmt.Print("=== Builder Pattern Demo ===\n\n")
 
 	// Demonstrate building a server config with only required fields
 	fmt.Println("1. Building a minimal server config:")
@@ -21,7 +21,7 @@ func main() {
 		return
 	}
 	fmt.Printf("   Host: %s, Port: %d, SSL: %v\n", minimalConfig.Host, minimalConfig.Port, minimalConfig.SSL)
-	fmt.Println("   ✓ Only set what we need, defaults applied for the rest\n")
+	fmt.Print("   ✓ Only set what we need, defaults applied for the rest\n\n")
 
 	// Demonstrate building a full-featured config
 	fmt.Println("2. Building a full-featured server config:")
@@ -49,7 +49,7 @@ func main() {
 	fmt.Printf("   Database URL: %s\n", fullConfig.DatabaseURL)
 	fmt.Printf("   Cache Enabled: %v\n", fullConfig.CacheEnabled)
 	fmt.Printf("   Log Level: %s\n", fullConfig.LogLevel)
-	fmt.Println("   ✓ Readable, step-by-step construction\n")
+	fmt.Print("   ✓ Readable, step-by-step construction\n\n")
 
 	// Demonstrate partial configuration
 	fmt.Println("3. Building a config with some custom settings:")
@@ -66,7 +66,7 @@ func main() {
 	fmt.Printf("   Host: %s, Port: %d, SSL: %v, Log Level: %s\n",
 		partialConfig.Host, partialConfig.Port, partialConfig.SSL, partialConfig.LogLevel)
 	fmt.Printf("   Timeout (default): %v\n", partialConfig.Timeout)
-	fmt.Println("   ✓ Mix of custom and default values\n")
+	fmt.Print("   ✓ Mix of custom and default values\n\n")
 
 	// Demonstrate validation
 	fmt.Println("4. Demonstrating validation:")
@@ -98,6 +98,21 @@ func main() {
 		fmt.Printf("   ✓ Validation caught invalid log level: %v\n", err)
 	}
 
+	// Socket vs TCP: listen on one or the other, never both
+	socketOrTCP := func() *builder.ServerConfigBuilder {
+		return builder.NewServerConfigBuilder().
+			ExclusiveGroup("UnixSocket", "Host").
+			ExclusiveGroup("UnixSocket", "Port")
+	}
+	socketConfig, err := socketOrTCP().UnixSocket("/var/run/app.sock").Build()
+	if err == nil {
+		fmt.Printf("   ✓ Socket-only config accepted: %s\n", socketConfig.UnixSocket)
+	}
+	_, err = socketOrTCP().UnixSocket("/var/run/app.sock").Host("localhost").Build()
+	if err != nil {
+		fmt.Printf("   ✓ Validation caught socket and TCP together: %v\n", err)
+	}
+
 	fmt.Println("\n5. Builder pattern benefits:")
 	fmt.Println("   ✓ Readable: Each field is clearly labeled")
 	fmt.Println("   ✓ Flexible: Set only what you need")
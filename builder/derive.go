@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns an independent copy of the builder: fields, set-tracking,
+// exclusive groups, allowed features and OnSet callbacks. Changing either
+// one afterwards doesn't affect the other. The undo stack isn't copied: the
+// clone starts with no checkpoints.
+func (b *ServerConfigBuilder) Clone() *ServerConfigBuilder {
+	clone := &ServerConfigBuilder{
+		config:          b.config,
+		set:             maps.Clone(b.set),
+		bare:            b.bare,
+		connsPerCore:    b.connsPerCore,
+		exclusive:       slices.Clone(b.exclusive),
+		allowedFeatures: maps.Clone(b.allowedFeatures),
+	}
+	clone.config.Extra = cloneExtra(b.config.Extra)
+	clone.config.Features = maps.Clone(b.config.Features)
+	clone.config.VirtualHosts = cloneVHosts(b.config.VirtualHosts)
+	if b.onSet != nil {
+		clone.onSet = make(map[string][]func(any), len(b.onSet))
+		for field, callbacks := range b.onSet {
+			clone.onSet[field] = slices.Clone(callbacks)
+		}
+	}
+	return clone
+}
+
+// Apply runs fn on the builder and returns it, so a reusable group of
+// settings can be dropped into a chain of setters
+func (b *ServerConfigBuilder) Apply(fn func(*ServerConfigBuilder)) *ServerConfigBuilder {
+	fn(b)
+	return b
+}
+
+// Derive returns a clone of the builder with fn applied, leaving the original
+// untouched. It's handy for producing variants of one base config:
+//
+//	base := NewServerConfigBuilder().Host("localhost")
+//	for _, port := range []int{8080, 8081, 8082} {
+//		cfg, err := base.Derive(func(b *ServerConfigBuilder) { b.Port(port) }).Build()
+//		...
+//	}
+func (b *ServerConfigBuilder) Derive(fn func(*ServerConfigBuilder)) *ServerConfigBuilder {
+	return b.Clone().Apply(fn)
+}
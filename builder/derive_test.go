@@ -0,0 +1,88 @@
+package builder
+
+import "testing"
+
+func TestDerivePortVariants(t *testing.T) {
+	base := NewServerConfigBuilder().Host("localhost").Port(8080).MaxConnections(50)
+
+	for _, port := range []int{8081, 8082, 8083} {
+		config := buildOrFatal(t, base.Derive(func(b *ServerConfigBuilder) { b.Port(port) }))
+		if config.Port != port || config.Host != "localhost" || config.MaxConnections != 50 {
+			t.Errorf("variant = %+v, want port %d on the base settings", config, port)
+		}
+	}
+
+	config := buildOrFatal(t, base)
+	if config.Port != 8080 {
+		t.Errorf("base port = %d after deriving, want 8080", config.Port)
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	base := NewServerConfigBuilder().
+		Host("localhost").
+		Set("region", "eu").
+		EnableFeature("beta")
+	clone := base.Clone().
+		Host("example.com").
+		Set("region", "us").
+		DisableFeature("beta").
+		EnableFeature("gamma")
+
+	config := buildOrFatal(t, base)
+	if config.Host != "localhost" {
+		t.Errorf("base host = %q, want localhost", config.Host)
+	}
+	if region, _ := config.GetString("region"); region != "eu" {
+		t.Errorf("base region = %q, want eu", region)
+	}
+	if !config.Features["beta"] || config.Features["gamma"] {
+		t.Errorf("base features = %v, want only beta", config.Features)
+	}
+
+	cloned := buildOrFatal(t, clone)
+	if region, _ := cloned.GetString("region"); cloned.Host != "example.com" || region != "us" {
+		t.Errorf("clone = %+v, want its own host and region", cloned)
+	}
+}
+
+func TestCloneCopiesSetTracking(t *testing.T) {
+	base := fullBare()
+	clone := base.Clone()
+	if _, err := clone.Build(); err != nil {
+		t.Fatalf("clone of a complete bare builder: %v", err)
+	}
+
+	delete(clone.set, "Port")
+	if !base.IsSet("Port") {
+		t.Error("clearing a field on the clone cleared it on the base")
+	}
+}
+
+func TestCloneCopiesOnSet(t *testing.T) {
+	var calls []string
+	base := NewServerConfigBuilder().OnSet("Port", func(any) { calls = append(calls, "base") })
+	clone := base.Clone().OnSet("Port", func(any) { calls = append(calls, "clone") })
+
+	base.Port(9000)
+	if len(calls) != 1 || calls[0] != "base" {
+		t.Errorf("callbacks on base = %v, want just the base's", calls)
+	}
+	calls = nil
+	clone.Port(9001)
+	if len(calls) != 2 {
+		t.Errorf("callbacks on clone = %v, want the inherited and its own", calls)
+	}
+}
+
+func TestApplyReturnsBuilder(t *testing.T) {
+	production := func(b *ServerConfigBuilder) { b.EnableSSL(true).Port(443) }
+	b := NewServerConfigBuilder().Host("example.com")
+	if got := b.Apply(production); got != b {
+		t.Error("Apply returned a different builder")
+	}
+	config := buildOrFatal(t, b)
+	if !config.SSL || config.Port != 443 {
+		t.Errorf("config = %+v, want the applied settings", config)
+	}
+}
@@ -0,0 +1,161 @@
+package builder
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// envField links one ServerConfig field to its environment variable.
+// Keeping both directions in a single table means LoadEnv and ToEnv can't drift apart.
+type envField struct {
+	name  string
+	field string
+	get   func(c *ServerConfig) string
+	set   func(b *ServerConfigBuilder, value string) error
+}
+
+var envFields = []envField{
+	{"HOST", "Host",
+		func(c *ServerConfig) string { return c.Host },
+		func(b *ServerConfigBuilder, v string) error { b.Host(v); return nil }},
+	{"PORT", "Port",
+		func(c *ServerConfig) string { return strconv.Itoa(c.Port) },
+		func(b *ServerConfigBuilder, v string) error {
+			port, err := strconv.Atoi(v)
+			b.Port(port)
+			return err
+		}},
+	{"SSL", "SSL",
+		func(c *ServerConfig) string { return strconv.FormatBool(c.SSL) },
+		func(b *ServerConfigBuilder, v string) error {
+			enable, err := strconv.ParseBool(v)
+			b.EnableSSL(enable)
+			return err
+		}},
+	{"TIMEOUT", "Timeout",
+		func(c *ServerConfig) string { return c.Timeout.String() },
+		func(b *ServerConfigBuilder, v string) error {
+			d, err := time.ParseDuration(v)
+			b.Timeout(d)
+			return err
+		}},
+	{"MAX_CONNECTIONS", "MaxConnections",
+		func(c *ServerConfig) string { return strconv.Itoa(c.MaxConnections) },
+		func(b *ServerConfigBuilder, v string) error {
+			max, err := strconv.Atoi(v)
+			b.MaxConnections(max)
+			return err
+		}},
+	{"READ_TIMEOUT", "ReadTimeout",
+		func(c *ServerConfig) string { return c.ReadTimeout.String() },
+		func(b *ServerConfigBuilder, v string) error {
+			d, err := time.ParseDuration(v)
+			b.ReadTimeout(d)
+			return err
+		}},
+	{"WRITE_TIMEOUT", "WriteTimeout",
+		func(c *ServerConfig) string { return c.WriteTimeout.String() },
+		func(b *ServerConfigBuilder, v string) error {
+			d, err := time.ParseDuration(v)
+			b.WriteTimeout(d)
+			return err
+		}},
+	{"DATABASE_URL", "DatabaseURL",
+		func(c *ServerConfig) string { return redactURL(c.DatabaseURL) },
+		func(b *ServerConfigBuilder, v string) error { b.DatabaseURL(v); return nil }},
+	{"CACHE_ENABLED", "CacheEnabled",
+		func(c *ServerConfig) string { return strconv.FormatBool(c.CacheEnabled) },
+		func(b *ServerConfigBuilder, v string) error {
+			enable, err := strconv.ParseBool(v)
+			b.EnableCache(enable)
+			return err
+		}},
+	{"LOG_LEVEL", "LogLevel",
+		func(c *ServerConfig) string { return c.LogLevel.String() },
+		func(b *ServerConfigBuilder, v string) error { b.LogLevel(v); return nil }},
+	{"DB_MAX_OPEN_CONNS", "DBMaxOpenConns",
+		func(c *ServerConfig) string { return strconv.Itoa(c.DBMaxOpenConns) },
+		func(b *ServerConfigBuilder, v string) error {
+			n, err := strconv.Atoi(v)
+			b.DBMaxOpenConns(n)
+			return err
+		}},
+	{"DB_MAX_IDLE_CONNS", "DBMaxIdleConns",
+		func(c *ServerConfig) string { return strconv.Itoa(c.DBMaxIdleConns) },
+		func(b *ServerConfigBuilder, v string) error {
+			n, err := strconv.Atoi(v)
+			b.DBMaxIdleConns(n)
+			return err
+		}},
+	{"DB_CONN_MAX_LIFETIME", "DBConnMaxLifetime",
+		func(c *ServerConfig) string { return c.DBConnMaxLifetime.String() },
+		func(b *ServerConfigBuilder, v string) error {
+			d, err := time.ParseDuration(v)
+			b.DBConnMaxLifetime(d)
+			return err
+		}},
+	{"TLS_CERT_FILE", "TLSCertFile",
+		func(c *ServerConfig) string { return c.TLSCertFile },
+		func(b *ServerConfigBuilder, v string) error { b.TLSCertFile(v); return nil }},
+	{"TLS_KEY_FILE", "TLSKeyFile",
+		func(c *ServerConfig) string { return c.TLSKeyFile },
+		func(b *ServerConfigBuilder, v string) error { b.TLSKeyFile(v); return nil }},
+	{"UNIX_SOCKET", "UnixSocket",
+		func(c *ServerConfig) string { return c.UnixSocket },
+		func(b *ServerConfigBuilder, v string) error { b.UnixSocket(v); return nil }},
+}
+
+// LoadEnv applies any PREFIX_* environment variables that are set, e.g.
+// APP_HOST and APP_PORT for prefix "APP". Unset variables leave the current
+// value alone, so defaults and earlier setters still apply.
+// Durations use Go syntax ("30s", "1m30s").
+func (b *ServerConfigBuilder) LoadEnv(prefix string) (*ServerConfigBuilder, error) {
+	return b.loadEnv(prefix, os.LookupEnv)
+}
+
+func (b *ServerConfigBuilder) loadEnv(prefix string, lookup func(string) (string, bool)) (*ServerConfigBuilder, error) {
+	for _, f := range envFields {
+		name := envName(prefix, f.name)
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := f.set(b, value); err != nil {
+			return b, &ValidationError{Field: f.field, Message: "invalid value in " + name + ": " + err.Error()}
+		}
+	}
+	return b, nil
+}
+
+// ToEnv returns the environment variables that LoadEnv would turn back into
+// this config. The password in DatabaseURL is redacted so the output is safe
+// to print or commit; supply the real URL separately.
+//
+// Only the scalar fields have variables. Extra, Features and VirtualHosts
+// can't be set through LoadEnv, so ToEnv leaves them out rather than emit
+// variables nothing reads; carry them over with the builder setters.
+func (c *ServerConfig) ToEnv(prefix string) map[string]string {
+	env := make(map[string]string, len(envFields))
+	for _, f := range envFields {
+		env[envName(prefix, f.name)] = f.get(c)
+	}
+	return env
+}
+
+func envName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// redactURL masks the password in a URL, leaving everything else intact
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,130 @@
+package builder
+
+import (
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lookupIn turns a map into an environment for loadEnv
+func lookupIn(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestToEnvLoadEnvRoundTrip(t *testing.T) {
+	original, err := NewServerConfigBuilder().
+		Host("api.example.com").
+		Port(9443).
+		EnableSSL(true).
+		Timeout(90 * time.Second).
+		MaxConnections(250).
+		ReadTimeout(1500 * time.Millisecond).
+		WriteTimeout(time.Minute).
+		DatabaseURL("postgresql://db.internal/app").
+		EnableCache(true).
+		Level(LogDebug).
+		DBMaxOpenConns(20).
+		DBMaxIdleConns(5).
+		DBConnMaxLifetime(time.Hour).
+		TLSCertFile("/etc/tls/cert.pem").
+		TLSKeyFile("/etc/tls/key.pem").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := original.ToEnv("APP")
+	b, err := NewServerConfigBuilder().loadEnv("APP", lookupIn(env))
+	if err != nil {
+		t.Fatalf("loadEnv: %v", err)
+	}
+	loaded, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	if got := loaded.ToEnv("APP"); !maps.Equal(got, env) {
+		t.Errorf("round trip changed the config:\n got %v\nwant %v", got, env)
+	}
+	if loaded.Host != original.Host || loaded.Port != original.Port || loaded.ReadTimeout != original.ReadTimeout ||
+		loaded.DBConnMaxLifetime != original.DBConnMaxLifetime || loaded.LogLevel != original.LogLevel || !loaded.SSL {
+		t.Errorf("loaded %+v, want %+v", loaded, original)
+	}
+}
+
+func TestToEnvRedactsDatabasePassword(t *testing.T) {
+	config, err := NewServerConfigBuilder().Host("localhost").DatabaseURL("postgresql://app:s3cret@db/app").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := config.ToEnv("")
+	if got := env["DATABASE_URL"]; got != "postgresql://app:xxxxx@db/app" {
+		t.Errorf("DATABASE_URL = %q, want the password redacted", got)
+	}
+	if got := env["TIMEOUT"]; got != "30s" {
+		t.Errorf("TIMEOUT = %q, want 30s", got)
+	}
+	if _, ok := env["HOST"]; !ok {
+		t.Error("an empty prefix should give unprefixed names")
+	}
+}
+
+func TestToEnvOmitsMapAndSliceFields(t *testing.T) {
+	config := buildOrFatal(t, NewServerConfigBuilder().
+		Host("localhost").
+		Set("region", "eu").
+		EnableFeature("beta").
+		VirtualHost("api.example.com", func(v *VHostBuilder) { v.EnableSSL(true) }))
+
+	env := config.ToEnv("APP")
+	if len(env) != len(envFields) {
+		t.Errorf("ToEnv returned %d variables, want one per env field (%d): %v", len(env), len(envFields), env)
+	}
+	for name, value := range env {
+		if strings.Contains(value, "region") || strings.Contains(value, "beta") || strings.Contains(value, "api.example.com") {
+			t.Errorf("%s = %q carries a field LoadEnv can't read back", name, value)
+		}
+	}
+}
+
+func TestLoadEnvInvalidValue(t *testing.T) {
+	_, err := NewServerConfigBuilder().loadEnv("APP", lookupIn(map[string]string{"APP_PORT": "eighty"}))
+	verr, ok := err.(*ValidationError)
+	if !ok || verr.Field != "Port" {
+		t.Errorf("loadEnv = %v, want a Port ValidationError", err)
+	}
+}
+
+func TestLoadEnvLeavesUnsetFields(t *testing.T) {
+	b, err := NewServerConfigBuilder().Port(7000).loadEnv("APP", lookupIn(map[string]string{"APP_HOST": "example.com"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "example.com" || config.Port != 7000 || config.Timeout != 30*time.Second {
+		t.Errorf("config = %+v, want host from env and the rest untouched", config)
+	}
+}
+
+func TestLoadEnvFromEnvironment(t *testing.T) {
+	t.Setenv("ENVTEST_HOST", "from-env.example.com")
+	t.Setenv("ENVTEST_TIMEOUT", "45s")
+	b, err := NewServerConfigBuilder().LoadEnv("ENVTEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "from-env.example.com" || config.Timeout != 45*time.Second {
+		t.Errorf("config = %+v, want values from the environment", config)
+	}
+}
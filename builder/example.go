@@ -17,9 +17,33 @@ type ServerConfig struct {
 	MaxConnections int
 	ReadTimeout    time.Duration
 	WriteTimeout   time.Duration
-	DatabaseURL    string
+	DatabaseURL    string `secret:"url"`
 	CacheEnabled   bool
-	LogLevel       string
+	LogLevel       LogLevel
+
+	// Database pool settings, see DBPoolConfig
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+
+	// TLS certificate and key files, used by HTTPServer when SSL is enabled
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// UnixSocket, when set, listens on a socket path instead of Host:Port
+	UnixSocket string
+
+	// VirtualHosts are extra hostnames served with per-host overrides
+	VirtualHosts []VHost
+
+	// Extra holds app-specific settings; see Set and the typed getters
+	Extra map[string]any
+
+	// Features holds named on/off toggles; see EnableFeature and FeatureEnabled
+	Features map[string]bool
+
+	// set records the fields assigned explicitly in the builder; see Resolve
+	set map[string]bool
 }
 
 // Step 2: Create the Builder Struct
@@ -27,7 +51,23 @@ type ServerConfig struct {
 // It mirrors the fields of ServerConfig, but we can set defaults here.
 
 type ServerConfigBuilder struct {
-	config ServerConfig
+	config   ServerConfig
+	warnings []*ValidationError
+
+	// set records which fields were assigned explicitly through a setter
+	set map[string]bool
+	// bare builders apply no defaults and require every field to be set
+	bare bool
+	// connsPerCore, when > 0, derives MaxConnections from the CPU count at Build()
+	connsPerCore int
+	// exclusive lists groups of fields of which at most one may be set
+	exclusive [][]string
+	// onSet holds the OnSet callbacks by field name
+	onSet map[string][]func(value any)
+	// allowedFeatures, when non-nil, is the only feature names Build() accepts
+	allowedFeatures map[string]bool
+	// checkpoints is the undo stack; see Checkpoint
+	checkpoints []*ServerConfigBuilder
 }
 
 // NewServerConfigBuilder creates a new builder with sensible defaults
@@ -42,7 +82,8 @@ func NewServerConfigBuilder() *ServerConfigBuilder {
 			ReadTimeout:    10 * time.Second,
 			WriteTimeout:   10 * time.Second,
 			CacheEnabled:   false,
-			LogLevel:       "info",
+			LogLevel:       LogInfo,
+			DBMaxIdleConns: 2, // database/sql's own default
 		},
 	}
 }
@@ -52,52 +93,96 @@ func NewServerConfigBuilder() *ServerConfigBuilder {
 // This is what makes the builder "fluent" - you can chain calls together.
 
 func (b *ServerConfigBuilder) Host(host string) *ServerConfigBuilder {
-	b.config.Host = host
+	b.config.Host = normalizeHost(host)
+	b.markSet("Host")
 	return b
 }
 
 func (b *ServerConfigBuilder) Port(port int) *ServerConfigBuilder {
 	b.config.Port = port
+	b.markSet("Port")
 	return b
 }
 
 func (b *ServerConfigBuilder) EnableSSL(enable bool) *ServerConfigBuilder {
 	b.config.SSL = enable
+	b.markSet("SSL")
 	return b
 }
 
 func (b *ServerConfigBuilder) Timeout(timeout time.Duration) *ServerConfigBuilder {
 	b.config.Timeout = timeout
+	b.markSet("Timeout")
 	return b
 }
 
 func (b *ServerConfigBuilder) MaxConnections(max int) *ServerConfigBuilder {
 	b.config.MaxConnections = max
+	b.markSet("MaxConnections")
 	return b
 }
 
 func (b *ServerConfigBuilder) ReadTimeout(timeout time.Duration) *ServerConfigBuilder {
 	b.config.ReadTimeout = timeout
+	b.markSet("ReadTimeout")
 	return b
 }
 
 func (b *ServerConfigBuilder) WriteTimeout(timeout time.Duration) *ServerConfigBuilder {
 	b.config.WriteTimeout = timeout
+	b.markSet("WriteTimeout")
 	return b
 }
 
 func (b *ServerConfigBuilder) DatabaseURL(url string) *ServerConfigBuilder {
 	b.config.DatabaseURL = url
+	b.markSet("DatabaseURL")
 	return b
 }
 
 func (b *ServerConfigBuilder) EnableCache(enable bool) *ServerConfigBuilder {
 	b.config.CacheEnabled = enable
+	b.markSet("CacheEnabled")
 	return b
 }
 
 func (b *ServerConfigBuilder) LogLevel(level string) *ServerConfigBuilder {
-	b.config.LogLevel = level
+	return b.Level(LogLevel(level))
+}
+
+func (b *ServerConfigBuilder) DBMaxOpenConns(n int) *ServerConfigBuilder {
+	b.config.DBMaxOpenConns = n
+	b.markSet("DBMaxOpenConns")
+	return b
+}
+
+func (b *ServerConfigBuilder) DBMaxIdleConns(n int) *ServerConfigBuilder {
+	b.config.DBMaxIdleConns = n
+	b.markSet("DBMaxIdleConns")
+	return b
+}
+
+func (b *ServerConfigBuilder) DBConnMaxLifetime(d time.Duration) *ServerConfigBuilder {
+	b.config.DBConnMaxLifetime = d
+	b.markSet("DBConnMaxLifetime")
+	return b
+}
+
+func (b *ServerConfigBuilder) TLSCertFile(path string) *ServerConfigBuilder {
+	b.config.TLSCertFile = path
+	b.markSet("TLSCertFile")
+	return b
+}
+
+func (b *ServerConfigBuilder) TLSKeyFile(path string) *ServerConfigBuilder {
+	b.config.TLSKeyFile = path
+	b.markSet("TLSKeyFile")
+	return b
+}
+
+func (b *ServerConfigBuilder) UnixSocket(path string) *ServerConfigBuilder {
+	b.config.UnixSocket = path
+	b.markSet("UnixSocket")
 	return b
 }
 
@@ -106,37 +191,104 @@ func (b *ServerConfigBuilder) LogLevel(level string) *ServerConfigBuilder {
 // This is where you can enforce required fields and validate the configuration.
 
 func (b *ServerConfigBuilder) Build() (*ServerConfig, error) {
-	// Validate required fields
-	if b.config.Host == "" {
-		return nil, &ValidationError{Field: "Host", Message: "host is required"}
+	// Errors stop the build; warnings are kept so the caller can inspect them.
+	// Report does the work; see it for the full list of issues.
+	b.warnings = nil
+	report := b.Report()
+	if len(report.Errors) > 0 {
+		return nil, report.Errors[0]
+	}
+	b.warnings = report.Warnings
+	for _, w := range b.warnings {
+		logger.Printf("config warning: %v\n", w)
 	}
+	return report.Config, nil
+}
+
+// Warnings returns the warning-level issues found by the last successful Build()
+func (b *ServerConfigBuilder) Warnings() []*ValidationError {
+	return append([]*ValidationError(nil), b.warnings...)
+}
 
-	if b.config.Port <= 0 || b.config.Port > 65535 {
-		return nil, &ValidationError{Field: "Port", Message: "port must be between 1 and 65535"}
+// validate checks a config and returns every issue it finds, errors and
+// warnings alike, in field order. It is shared by Build() and anything else
+// that needs to check a config without building it.
+func validate(c *ServerConfig) []*ValidationError {
+	var issues []*ValidationError
+
+	// Validate required fields; a socket listener has no host or port
+	if c.UnixSocket == "" {
+		if c.Host == "" {
+			issues = append(issues, &ValidationError{Field: "Host", Message: "host is required"})
+		} else if err := validateHost(c.Host); err != nil {
+			issues = append(issues, err)
+		}
+
+		if c.Port <= 0 || c.Port > 65535 {
+			issues = append(issues, &ValidationError{Field: "Port", Message: "port must be between 1 and 65535"})
+		}
 	}
 
 	// Validate optional fields if needed
-	validLogLevels := map[string]bool{
-		"debug": true,
-		"info":  true,
-		"warn":  true,
-		"error": true,
+	if c.MaxConnections < 10 {
+		issues = append(issues, &ValidationError{
+			Field:    "MaxConnections",
+			Message:  "max connections is unusually low and may reject traffic under load",
+			Severity: SeverityWarning,
+		})
+	}
+
+	if !c.LogLevel.Valid() {
+		issues = append(issues, invalidLogLevel("LogLevel", "", string(c.LogLevel)))
 	}
-	if !validLogLevels[b.config.LogLevel] {
-		return nil, &ValidationError{Field: "LogLevel", Message: "log level must be one of: debug, info, warn, error"}
+
+	if err := validateVHosts(c.VirtualHosts); err != nil {
+		issues = append(issues, err)
 	}
 
-	// Return a copy of the config (immutable)
-	config := b.config
-	return &config, nil
+	if err := validateDBPool(c); err != nil {
+		issues = append(issues, err)
+	}
+
+	if _, ok := c.Extra[""]; ok {
+		issues = append(issues, &ValidationError{Field: "Extra", Message: "extra setting keys must not be empty"})
+	}
+
+	if _, ok := c.Features[""]; ok {
+		issues = append(issues, &ValidationError{Field: "Features", Message: "feature names must not be empty"})
+	}
+
+	return issues
+}
+
+// Severity says whether a validation issue stops the build
+type Severity int
+
+const (
+	SeverityError   Severity = iota // Build() fails
+	SeverityWarning                 // Build() succeeds; see Warnings()
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
 }
 
 // ValidationError represents a validation error during build
+// Suggestion, when set, is the closest valid value to what was given.
+// Severity defaults to SeverityError.
 type ValidationError struct {
-	Field   string
-	Message string
+	Field      string
+	Message    string
+	Suggestion string
+	Severity   Severity
 }
 
 func (e *ValidationError) Error() string {
+	if e.Suggestion != "" {
+		return e.Field + ": " + e.Message + " (did you mean \"" + e.Suggestion + "\"?)"
+	}
 	return e.Field + ": " + e.Message
 }
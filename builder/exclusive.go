@@ -0,0 +1,65 @@
+package builder
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ExclusiveGroup declares fields (by name, e.g. "UnixSocket", "Host") that
+// must not be used together: Build() fails if more than one of them was set
+// to a non-zero value. Groups can overlap, so listening on either a socket
+// or a TCP address reads as
+//
+//	b.ExclusiveGroup("UnixSocket", "Host").ExclusiveGroup("UnixSocket", "Port")
+//
+// On a bare builder, setting one member of a group also satisfies the
+// "every field must be set" rule for the others.
+func (b *ServerConfigBuilder) ExclusiveGroup(fields ...string) *ServerConfigBuilder {
+	if len(fields) > 1 {
+		b.exclusive = append(b.exclusive, append([]string(nil), fields...))
+	}
+	return b
+}
+
+// checkExclusive returns an error for the first group with more than one member in use
+func (b *ServerConfigBuilder) checkExclusive(c *ServerConfig) *ValidationError {
+	for _, group := range b.exclusive {
+		var used []string
+		for _, field := range group {
+			if b.IsSet(field) && !fieldIsZero(c, field) {
+				used = append(used, field)
+			}
+		}
+		if len(used) > 1 {
+			return &ValidationError{
+				Field:   used[0],
+				Message: strings.Join(used, " and ") + " are mutually exclusive; set only one",
+			}
+		}
+	}
+	return nil
+}
+
+// coveredByGroup reports whether another member of one of field's exclusive
+// groups was set, which excuses field from being set on a bare builder.
+func (b *ServerConfigBuilder) coveredByGroup(field string) bool {
+	for _, group := range b.exclusive {
+		if !slices.Contains(group, field) {
+			continue
+		}
+		for _, other := range group {
+			if other != field && b.IsSet(other) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldIsZero reports whether the named ServerConfig field holds its zero
+// value. Unknown names count as zero, so they never conflict.
+func fieldIsZero(c *ServerConfig, field string) bool {
+	v := reflect.ValueOf(c).Elem().FieldByName(field)
+	return !v.IsValid() || v.IsZero()
+}
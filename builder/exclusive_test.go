@@ -0,0 +1,75 @@
+package builder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listener returns a builder where a socket and a TCP address are exclusive
+func listener() *ServerConfigBuilder {
+	return NewServerConfigBuilder().
+		ExclusiveGroup("UnixSocket", "Host").
+		ExclusiveGroup("UnixSocket", "Port")
+}
+
+func TestExclusiveGroupConflict(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *ServerConfigBuilder
+		want string
+	}{
+		{"socket and host", listener().UnixSocket("/run/app.sock").Host("localhost"), "UnixSocket and Host"},
+		{"socket and port", listener().UnixSocket("/run/app.sock").Port(9000), "UnixSocket and Port"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.b.Build()
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Build = %v, want a ValidationError", err)
+			}
+			if verr.Field != "UnixSocket" || !strings.Contains(verr.Message, tt.want) {
+				t.Errorf("error = %v, want it to name %s", verr, tt.want)
+			}
+		})
+	}
+}
+
+func TestExclusiveGroupSingleChoice(t *testing.T) {
+	tests := map[string]*ServerConfigBuilder{
+		"socket only": listener().UnixSocket("/run/app.sock"),
+		"tcp only":    listener().Host("localhost").Port(9000),
+		// A member set back to its zero value isn't in use
+		"host cleared": listener().Host("").UnixSocket("/run/app.sock"),
+	}
+	for name, b := range tests {
+		if _, err := b.Build(); err != nil {
+			t.Errorf("%s: Build = %v", name, err)
+		}
+	}
+}
+
+func TestExclusiveGroupCoversBareFields(t *testing.T) {
+	b := NewBareServerConfigBuilder().
+		ExclusiveGroup("UnixSocket", "Host").
+		ExclusiveGroup("UnixSocket", "Port").
+		UnixSocket("/run/app.sock").
+		EnableSSL(false).
+		Timeout(30 * time.Second).
+		MaxConnections(100).
+		ReadTimeout(10 * time.Second).
+		WriteTimeout(10 * time.Second).
+		DatabaseURL("").
+		EnableCache(false).
+		Level(LogInfo).
+		DBMaxOpenConns(0).
+		DBMaxIdleConns(2).
+		DBConnMaxLifetime(0).
+		TLSCertFile("").
+		TLSKeyFile("")
+	if _, err := b.Build(); err != nil {
+		t.Errorf("Build = %v, want Host and Port excused by the socket", err)
+	}
+}
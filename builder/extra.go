@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+var (
+	// ErrExtraNotFound is returned by the typed getters when a key isn't set
+	ErrExtraNotFound = errors.New("extra setting not found")
+	// ErrExtraWrongType is returned by the typed getters when a key holds another type
+	ErrExtraWrongType = errors.New("extra setting has the wrong type")
+)
+
+// Set stores an app-specific setting in the config's Extra map, for keys
+// that don't deserve a ServerConfig field of their own. Read it back with
+// GetString, GetInt or GetBool. Keys must be non-empty; Build() checks.
+func (b *ServerConfigBuilder) Set(key string, value any) *ServerConfigBuilder {
+	if b.config.Extra == nil {
+		b.config.Extra = make(map[string]any)
+	}
+	b.config.Extra[key] = value
+	return b
+}
+
+// GetString returns the string stored under key in Extra
+func (c *ServerConfig) GetString(key string) (string, error) {
+	return getExtra[string](c, key)
+}
+
+// GetInt returns the int stored under key in Extra
+func (c *ServerConfig) GetInt(key string) (int, error) {
+	return getExtra[int](c, key)
+}
+
+// GetBool returns the bool stored under key in Extra
+func (c *ServerConfig) GetBool(key string) (bool, error) {
+	return getExtra[bool](c, key)
+}
+
+func getExtra[T any](c *ServerConfig, key string) (T, error) {
+	var zero T
+	raw, ok := c.Extra[key]
+	if !ok {
+		return zero, fmt.Errorf("%w: %q", ErrExtraNotFound, key)
+	}
+	value, ok := raw.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %q is %T, not %T", ErrExtraWrongType, key, raw, zero)
+	}
+	return value, nil
+}
+
+// cloneExtra copies the Extra map so configs never share it
+func cloneExtra(extra map[string]any) map[string]any {
+	return maps.Clone(extra)
+}
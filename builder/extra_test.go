@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtraTypedGetters(t *testing.T) {
+	cfg := buildOrFatal(t, NewServerConfigBuilder().
+		Host("localhost").
+		Set("region", "eu-west-1").
+		Set("workers", 8).
+		Set("beta", true))
+
+	if got, err := cfg.GetString("region"); err != nil || got != "eu-west-1" {
+		t.Errorf("GetString(region) = %q, %v", got, err)
+	}
+	if got, err := cfg.GetInt("workers"); err != nil || got != 8 {
+		t.Errorf("GetInt(workers) = %d, %v", got, err)
+	}
+	if got, err := cfg.GetBool("beta"); err != nil || !got {
+		t.Errorf("GetBool(beta) = %v, %v", got, err)
+	}
+}
+
+func TestExtraGetterErrors(t *testing.T) {
+	cfg := buildOrFatal(t, NewServerConfigBuilder().Host("localhost").Set("workers", "8"))
+
+	if got, err := cfg.GetInt("workers"); !errors.Is(err, ErrExtraWrongType) || got != 0 {
+		t.Errorf("GetInt of a string = %d, %v, want ErrExtraWrongType", got, err)
+	}
+	if _, err := cfg.GetBool("missing"); !errors.Is(err, ErrExtraNotFound) {
+		t.Errorf("GetBool(missing) = %v, want ErrExtraNotFound", err)
+	}
+	// A config without any extras has a nil map; lookups still just miss
+	if _, err := (&ServerConfig{}).GetString("region"); !errors.Is(err, ErrExtraNotFound) {
+		t.Errorf("GetString on an empty config = %v, want ErrExtraNotFound", err)
+	}
+}
+
+func TestExtraEmptyKeyRejected(t *testing.T) {
+	_, err := NewServerConfigBuilder().Host("localhost").Set("", 1).Build()
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "Extra" {
+		t.Errorf("Build = %v, want an Extra ValidationError", err)
+	}
+}
+
+func TestExtraNotShared(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").Set("region", "eu")
+	cfg := buildOrFatal(t, b)
+	b.Set("region", "us")
+	if got, _ := cfg.GetString("region"); got != "eu" {
+		t.Errorf("built config changed with the builder: region = %q", got)
+	}
+}
@@ -0,0 +1,64 @@
+package builder
+
+import (
+	"maps"
+	"slices"
+)
+
+// EnableFeature turns on a named feature flag in the config's Features map
+func (b *ServerConfigBuilder) EnableFeature(name string) *ServerConfigBuilder {
+	return b.setFeature(name, true)
+}
+
+// DisableFeature turns off a named feature flag. Unlike leaving it out, an
+// explicitly disabled flag overrides an enabled one from an earlier layer in
+// Resolve.
+func (b *ServerConfigBuilder) DisableFeature(name string) *ServerConfigBuilder {
+	return b.setFeature(name, false)
+}
+
+func (b *ServerConfigBuilder) setFeature(name string, enabled bool) *ServerConfigBuilder {
+	if b.config.Features == nil {
+		b.config.Features = make(map[string]bool)
+	}
+	b.config.Features[name] = enabled
+	return b
+}
+
+// AllowFeatures restricts feature flags to the given names: Build() fails
+// if any other feature is enabled or disabled. Calling it again adds to the
+// allowed set. Without it any non-empty name is accepted.
+func (b *ServerConfigBuilder) AllowFeatures(names ...string) *ServerConfigBuilder {
+	if b.allowedFeatures == nil {
+		b.allowedFeatures = make(map[string]bool)
+	}
+	for _, name := range names {
+		b.allowedFeatures[name] = true
+	}
+	return b
+}
+
+// FeatureEnabled reports whether the named feature is turned on.
+// Features that were never set are off.
+func (c *ServerConfig) FeatureEnabled(name string) bool {
+	return c.Features[name]
+}
+
+// checkFeatures returns an error for the first feature, in name order, that
+// isn't in the builder's allowed set
+func (b *ServerConfigBuilder) checkFeatures(c *ServerConfig) *ValidationError {
+	if b.allowedFeatures == nil {
+		return nil
+	}
+	allowed := slices.Sorted(maps.Keys(b.allowedFeatures))
+	for _, name := range slices.Sorted(maps.Keys(c.Features)) {
+		if !b.allowedFeatures[name] {
+			return &ValidationError{
+				Field:      "Features",
+				Message:    "unknown feature \"" + name + "\"",
+				Suggestion: suggest(name, allowed),
+			}
+		}
+	}
+	return nil
+}
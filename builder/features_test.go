@@ -0,0 +1,96 @@
+package builder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	config := buildOrFatal(t, NewServerConfigBuilder().
+		Host("localhost").
+		EnableFeature("beta").
+		EnableFeature("search").
+		DisableFeature("search").
+		DisableFeature("legacy"))
+
+	tests := map[string]bool{"beta": true, "search": false, "legacy": false, "never-set": false}
+	for name, want := range tests {
+		if got := config.FeatureEnabled(name); got != want {
+			t.Errorf("FeatureEnabled(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if enabled, ok := config.Features["legacy"]; !ok || enabled {
+		t.Errorf("Features[legacy] = %v, %v, want an explicit false", enabled, ok)
+	}
+	if _, ok := config.Features["never-set"]; ok {
+		t.Error("FeatureEnabled added an entry to Features")
+	}
+}
+
+func TestFeatureFlagsWithoutAny(t *testing.T) {
+	config := buildOrFatal(t, NewServerConfigBuilder().Host("localhost"))
+	if config.FeatureEnabled("beta") {
+		t.Error("FeatureEnabled on a config without features")
+	}
+}
+
+func TestAllowFeatures(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       *ServerConfigBuilder
+		wantErr string
+		suggest string
+	}{
+		{
+			name: "allowed",
+			b:    NewServerConfigBuilder().Host("localhost").AllowFeatures("beta", "search").EnableFeature("beta").DisableFeature("search"),
+		},
+		{
+			name: "allowed across calls",
+			b:    NewServerConfigBuilder().Host("localhost").AllowFeatures("beta").AllowFeatures("search").EnableFeature("search"),
+		},
+		{
+			name:    "unknown enabled",
+			b:       NewServerConfigBuilder().Host("localhost").AllowFeatures("beta", "search").EnableFeature("serach"),
+			wantErr: `unknown feature "serach"`,
+			suggest: "search",
+		},
+		{
+			name:    "unknown disabled",
+			b:       NewServerConfigBuilder().Host("localhost").AllowFeatures("beta").DisableFeature("gamma"),
+			wantErr: `unknown feature "gamma"`,
+		},
+		{
+			name:    "first in name order",
+			b:       NewServerConfigBuilder().Host("localhost").AllowFeatures("beta").EnableFeature("zeta").EnableFeature("alpha"),
+			wantErr: `unknown feature "alpha"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.b.Build()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Build: %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Field != "Features" || !strings.Contains(verr.Message, tt.wantErr) {
+				t.Fatalf("Build = %v, want a Features error containing %q", err, tt.wantErr)
+			}
+			if verr.Suggestion != tt.suggest {
+				t.Errorf("Suggestion = %q, want %q", verr.Suggestion, tt.suggest)
+			}
+		})
+	}
+}
+
+func TestEmptyFeatureName(t *testing.T) {
+	_, err := NewServerConfigBuilder().Host("localhost").EnableFeature("").Build()
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "Features" {
+		t.Errorf("Build with an empty feature name = %v, want a Features ValidationError", err)
+	}
+}
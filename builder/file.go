@@ -0,0 +1,131 @@
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoadDefaultsFile applies settings from a JSON (.json) or YAML (.yaml, .yml)
+// file. It is meant to be called right after NewServerConfigBuilder so that
+// organization-wide defaults come from the file and the fluent setters that
+// follow override them per call site:
+//
+//	b, err := NewServerConfigBuilder().LoadDefaultsFile("defaults.yaml")
+//	cfg, err := b.Port(9090).Build() // file values, except the port
+//
+// Keys are the lower-case names of the environment variables LoadEnv reads
+// (host, port, ssl, timeout, max_connections, log_level, ...). Durations are
+// written Go-style ("30s"). An empty file changes nothing; a missing file,
+// an unsupported extension or an unknown key is an error.
+func (b *ServerConfigBuilder) LoadDefaultsFile(path string) (*ServerConfigBuilder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b, fmt.Errorf("load defaults: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return b, nil
+	}
+
+	var values map[string]string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		values, err = parseJSONValues(data)
+	case ".yaml", ".yml":
+		values, err = parseYAMLValues(data)
+	default:
+		return b, fmt.Errorf("load defaults: unsupported file extension %q (want .json, .yaml or .yml)", ext)
+	}
+	if err != nil {
+		return b, fmt.Errorf("load defaults from %s: %w", path, err)
+	}
+	return b.applyValues(values)
+}
+
+// applyValues sets fields from a key → raw string map using the env field table
+// and stops at the first bad setting
+func (b *ServerConfigBuilder) applyValues(values map[string]string) (*ServerConfigBuilder, error) {
+	if errs := b.setValues(values); len(errs) > 0 {
+		return b, errs[0]
+	}
+	return b, nil
+}
+
+// setValues sets every valid setting in values and returns an error for each
+// one that is unknown or fails to parse, in key order
+func (b *ServerConfigBuilder) setValues(values map[string]string) []error {
+	byKey := make(map[string]envField, len(envFields))
+	for _, f := range envFields {
+		byKey[strings.ToLower(f.name)] = f
+	}
+
+	// Sorted so the first error reported is deterministic
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		f, ok := byKey[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown setting %q", key))
+			continue
+		}
+		if err := f.set(b, values[key]); err != nil {
+			errs = append(errs, &ValidationError{Field: f.field, Message: "invalid value for " + key + ": " + err.Error()})
+		}
+	}
+	return errs
+}
+
+// parseJSONValues flattens a JSON object's scalar values to strings
+func parseJSONValues(data []byte) (map[string]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch v.(type) {
+		case string, float64, bool:
+			values[k] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("setting %q must be a string, number or boolean", k)
+		}
+	}
+	return values, nil
+}
+
+// parseYAMLValues reads flat "key: value" YAML, which is all a ServerConfig needs.
+// Comments and blank lines are skipped and surrounding quotes are removed.
+// Nested mappings and lists are not supported.
+func parseYAMLValues(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+		value = strings.TrimSpace(value)
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values, scanner.Err()
+}
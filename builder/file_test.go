@@ -0,0 +1,86 @@
+package builder
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFile writes content to name in a fresh temp dir and returns the path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaultsFileFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"defaults.json", `{"host": "files.example.com", "port": 9000, "ssl": true, "timeout": "45s"}`},
+		{"defaults.yaml", "# org defaults\nhost: files.example.com\nport: 9000\nssl: true # always\ntimeout: \"45s\"\n"},
+		{"defaults.YML", "---\nhost: 'files.example.com'\nport: 9000\nssl: true\ntimeout: 45s\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewServerConfigBuilder().LoadDefaultsFile(writeFile(t, tt.name, tt.content))
+			if err != nil {
+				t.Fatalf("LoadDefaultsFile: %v", err)
+			}
+			config := buildOrFatal(t, b)
+			if config.Host != "files.example.com" || config.Port != 9000 || !config.SSL || config.Timeout != 45*time.Second {
+				t.Errorf("config = %+v, want the file's values", config)
+			}
+		})
+	}
+}
+
+func TestLoadDefaultsFileSettersOverride(t *testing.T) {
+	path := writeFile(t, "defaults.json", `{"host": "files.example.com", "port": 9000, "max_connections": 500}`)
+	b, err := NewServerConfigBuilder().LoadDefaultsFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := buildOrFatal(t, b.Port(9090))
+	if config.Port != 9090 || config.Host != "files.example.com" || config.MaxConnections != 500 {
+		t.Errorf("config = %+v, want the setter's port and the file's other values", config)
+	}
+}
+
+func TestLoadDefaultsFileEmpty(t *testing.T) {
+	b, err := NewServerConfigBuilder().LoadDefaultsFile(writeFile(t, "empty.yaml", "  \n"))
+	if err != nil {
+		t.Fatalf("LoadDefaultsFile on an empty file: %v", err)
+	}
+	if config := buildOrFatal(t, b.Host("localhost")); config.Port != 8080 {
+		t.Errorf("port = %d, want the builder default", config.Port)
+	}
+}
+
+func TestLoadDefaultsFileErrors(t *testing.T) {
+	_, err := NewServerConfigBuilder().LoadDefaultsFile(filepath.Join(t.TempDir(), "missing.json"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: %v, want fs.ErrNotExist", err)
+	}
+
+	tests := map[string]string{
+		"defaults.toml":  `host = "x"`,
+		"unknown.json":   `{"hots": "x"}`,
+		"nested.json":    `{"host": {"name": "x"}}`,
+		"badport.yaml":   "port: eighty",
+		"malformed.yaml": "just some words",
+		"broken.json":    `{"host": `,
+	}
+	for name, content := range tests {
+		if _, err := NewServerConfigBuilder().LoadDefaultsFile(writeFile(t, name, content)); err == nil {
+			t.Errorf("%s: LoadDefaultsFile succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package builder
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+)
+
+// BindFlags registers a flag on fs for every field the environment loader
+// knows, named after its variable in lower case with hyphens: -host, -port,
+// -ssl, -timeout, -max-connections and so on. Parsing fs applies the flags
+// that were given through the builder's setters, so after fs.Parse they
+// count as set and Build() validates them as usual. Flags that aren't given
+// leave the builder alone.
+//
+// Durations use Go syntax ("30s"), as with flag.Duration, and -ssl can be
+// given on its own to mean -ssl=true.
+func (b *ServerConfigBuilder) BindFlags(fs *flag.FlagSet) *ServerConfigBuilder {
+	configType := reflect.TypeOf(ServerConfig{})
+	for _, f := range envFields {
+		field, _ := configType.FieldByName(f.field)
+		value := &flagValue{b: b, field: f, isBool: field.Type.Kind() == reflect.Bool}
+		fs.Var(value, flagName(f.name), "sets "+f.field)
+	}
+	return b
+}
+
+// flagName turns an environment variable name like MAX_CONNECTIONS into max-connections
+func flagName(envName string) string {
+	return strings.ReplaceAll(strings.ToLower(envName), "_", "-")
+}
+
+// flagValue adapts one envField to flag.Value
+type flagValue struct {
+	b      *ServerConfigBuilder
+	field  envField
+	isBool bool
+}
+
+// String returns the builder's current value, which flag shows as the default.
+// flag calls it on a zero flagValue too, so b may be nil.
+func (v *flagValue) String() string {
+	if v.b == nil {
+		return ""
+	}
+	return v.field.get(&v.b.config)
+}
+
+func (v *flagValue) Set(value string) error {
+	return v.field.set(v.b, value)
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
@@ -0,0 +1,113 @@
+package builder
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseFlags binds a fresh flag set to b and parses args with it
+func parseFlags(b *ServerConfigBuilder, args ...string) error {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	b.BindFlags(fs)
+	return fs.Parse(args)
+}
+
+func TestBindFlags(t *testing.T) {
+	b := NewServerConfigBuilder()
+	err := parseFlags(b,
+		"-host", "example.com",
+		"-port=9443",
+		"-ssl",
+		"-timeout", "45s",
+		"-max-connections", "250",
+		"-read-timeout=2s",
+		"-write-timeout=3m",
+		"-cache-enabled=false",
+		"-log-level", "debug",
+		"-tls-cert-file", "cert.pem",
+		"-tls-key-file", "key.pem",
+	)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	config := buildOrFatal(t, b)
+
+	if config.Host != "example.com" || config.Port != 9443 || !config.SSL {
+		t.Errorf("host/port/ssl = %s/%d/%v", config.Host, config.Port, config.SSL)
+	}
+	if config.Timeout != 45*time.Second || config.ReadTimeout != 2*time.Second || config.WriteTimeout != 3*time.Minute {
+		t.Errorf("timeouts = %v/%v/%v", config.Timeout, config.ReadTimeout, config.WriteTimeout)
+	}
+	if config.MaxConnections != 250 || config.CacheEnabled || config.LogLevel != "debug" {
+		t.Errorf("config = %+v", config)
+	}
+	if config.TLSCertFile != "cert.pem" || config.TLSKeyFile != "key.pem" {
+		t.Errorf("TLS files = %q, %q", config.TLSCertFile, config.TLSKeyFile)
+	}
+	for _, field := range []string{"Host", "Port", "SSL", "Timeout", "LogLevel"} {
+		if !b.IsSet(field) {
+			t.Errorf("IsSet(%s) = false after its flag was parsed", field)
+		}
+	}
+}
+
+func TestBindFlagsLeavesUnsetFieldsAlone(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").Port(8080)
+	if err := parseFlags(b, "-port", "9090", "serve"); err != nil {
+		t.Fatal(err)
+	}
+	config := buildOrFatal(t, b)
+	if config.Host != "localhost" || config.Port != 9090 {
+		t.Errorf("host/port = %s/%d, want localhost/9090", config.Host, config.Port)
+	}
+	if b.IsSet("Timeout") {
+		t.Error("IsSet(Timeout) without a -timeout flag")
+	}
+}
+
+func TestBindFlagsDefaultsShowBuilderValues(t *testing.T) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	NewServerConfigBuilder().Port(7000).BindFlags(fs)
+
+	if got := fs.Lookup("port").DefValue; got != "7000" {
+		t.Errorf("-port default = %q, want 7000", got)
+	}
+	if fs.Lookup("db-conn-max-lifetime") == nil || fs.Lookup("unix-socket") == nil {
+		t.Error("multi-word fields aren't registered as hyphenated flags")
+	}
+
+	var usage strings.Builder
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+	if !strings.Contains(usage.String(), "sets MaxConnections") {
+		t.Errorf("usage = %q, want each flag to name its field", usage.String())
+	}
+}
+
+func TestBindFlagsErrors(t *testing.T) {
+	tests := [][]string{
+		{"-port", "eighty"},
+		{"-timeout", "30"},
+		{"-ssl=maybe"},
+		{"-no-such-flag"},
+	}
+	for _, args := range tests {
+		if err := parseFlags(NewServerConfigBuilder(), args...); err == nil {
+			t.Errorf("Parse(%v) succeeded, want an error", args)
+		}
+	}
+}
+
+func TestBindFlagsBuildStillValidates(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost")
+	if err := parseFlags(b, "-port", "70000"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Build(); err == nil {
+		t.Error("Build with -port 70000 succeeded, want a validation error")
+	}
+}
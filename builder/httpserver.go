@@ -0,0 +1,37 @@
+package builder
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// HTTPServer returns a *http.Server configured from c: Addr from ListenAddr,
+// and the read and write timeouts. With SSL enabled, the certificate and key
+// are loaded into TLSConfig, so serve it with ListenAndServeTLS("", "").
+// SSL without both TLSCertFile and TLSKeyFile, or with files that don't load,
+// is an error.
+//
+// A config with a UnixSocket gets no Addr; serve it on a listener from
+// net.Listen("unix", c.UnixSocket) instead.
+func (c *ServerConfig) HTTPServer(handler http.Handler) (*http.Server, error) {
+	server := &http.Server{
+		Handler:      handler,
+		ReadTimeout:  c.ReadTimeout,
+		WriteTimeout: c.WriteTimeout,
+	}
+	if c.UnixSocket == "" {
+		server.Addr = c.ListenAddr()
+	}
+
+	if c.SSL {
+		if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+			return nil, &ValidationError{Field: "SSL", Message: "SSL requires both TLSCertFile and TLSKeyFile"}
+		}
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, &ValidationError{Field: "TLSCertFile", Message: "load TLS key pair: " + err.Error()}
+		}
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	}
+	return server, nil
+}
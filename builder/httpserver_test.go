@@ -0,0 +1,141 @@
+package builder
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeKeyPair writes a throwaway self-signed certificate and key into a
+// temporary directory and returns their paths
+func writeKeyPair(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestHTTPServerMapsConfig(t *testing.T) {
+	config := buildOrFatal(t, NewServerConfigBuilder().
+		Host("127.0.0.1").
+		Port(9090).
+		ReadTimeout(3*time.Second).
+		WriteTimeout(7*time.Second))
+	handler := http.NotFoundHandler()
+
+	server, err := config.HTTPServer(handler)
+	if err != nil {
+		t.Fatalf("HTTPServer: %v", err)
+	}
+	if server.Addr != "127.0.0.1:9090" {
+		t.Errorf("Addr = %q, want 127.0.0.1:9090", server.Addr)
+	}
+	if server.ReadTimeout != 3*time.Second || server.WriteTimeout != 7*time.Second {
+		t.Errorf("timeouts = %v/%v, want 3s/7s", server.ReadTimeout, server.WriteTimeout)
+	}
+	if server.Handler == nil {
+		t.Error("Handler not set")
+	}
+	if server.TLSConfig != nil {
+		t.Error("TLSConfig set without SSL")
+	}
+}
+
+func TestHTTPServerIPv6Addr(t *testing.T) {
+	config := &ServerConfig{Host: "::1", Port: 8443}
+	server, err := config.HTTPServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Addr != "[::1]:8443" {
+		t.Errorf("Addr = %q, want [::1]:8443", server.Addr)
+	}
+}
+
+func TestHTTPServerUnixSocket(t *testing.T) {
+	config := &ServerConfig{Host: "localhost", Port: 8080, UnixSocket: "/tmp/app.sock"}
+	server, err := config.HTTPServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Addr != "" {
+		t.Errorf("Addr = %q for a unix socket config, want empty", server.Addr)
+	}
+}
+
+func TestHTTPServerTLS(t *testing.T) {
+	certFile, keyFile := writeKeyPair(t)
+	config := &ServerConfig{Host: "localhost", Port: 443, SSL: true, TLSCertFile: certFile, TLSKeyFile: keyFile}
+
+	server, err := config.HTTPServer(nil)
+	if err != nil {
+		t.Fatalf("HTTPServer: %v", err)
+	}
+	if server.TLSConfig == nil || len(server.TLSConfig.Certificates) != 1 {
+		t.Fatalf("TLSConfig = %+v, want the loaded certificate", server.TLSConfig)
+	}
+}
+
+func TestHTTPServerTLSErrors(t *testing.T) {
+	certFile, _ := writeKeyPair(t)
+	tests := []struct {
+		name      string
+		cert, key string
+		field     string
+	}{
+		{"no files", "", "", "SSL"},
+		{"no key", certFile, "", "SSL"},
+		{"no cert", "", certFile, "SSL"},
+		{"missing files", filepath.Join(t.TempDir(), "nope.pem"), filepath.Join(t.TempDir(), "nope.key"), "TLSCertFile"},
+		{"cert as key", certFile, certFile, "TLSCertFile"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ServerConfig{Host: "localhost", Port: 443, SSL: true, TLSCertFile: tt.cert, TLSKeyFile: tt.key}
+			server, err := config.HTTPServer(nil)
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Field != tt.field {
+				t.Fatalf("HTTPServer = %v, want a %s ValidationError", err, tt.field)
+			}
+			if server != nil {
+				t.Error("HTTPServer returned a server alongside the error")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// fieldConstraints holds the validation rules from validate() in JSON Schema
+// form. Keep it in step with validate() when rules change.
+var fieldConstraints = map[string]map[string]any{
+	"Port":           {"minimum": 1, "maximum": 65535},
+	"LogLevel":       {"enum": logLevelNames()},
+	"DBMaxOpenConns": {"minimum": 0},
+	"DBMaxIdleConns": {"minimum": 0},
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ServerConfigJSONSchema describes the JSON config files that
+// LoadDefaultsFile and ValidateJSON read, as a JSON Schema (draft 2020-12)
+// for config editors. Properties come from the same field table the loaders
+// use, types from the ServerConfig struct via reflection, and defaults from
+// NewServerConfigBuilder, so the schema can't drift from the struct.
+// Unknown keys are rejected, like the loaders do.
+//
+// Extra, Features and VirtualHosts have no properties: the loaders only read
+// the scalar settings, so a file carrying them would be rejected. Set them
+// with the builder setters instead.
+func ServerConfigJSONSchema() ([]byte, error) {
+	defaults := NewServerConfigBuilder().config
+	dv := reflect.ValueOf(defaults)
+	configType := dv.Type()
+
+	properties := make(map[string]any, len(envFields))
+	for _, f := range envFields {
+		field, ok := configType.FieldByName(f.field)
+		if !ok {
+			return nil, fmt.Errorf("field table names unknown field %q", f.field)
+		}
+
+		prop := map[string]any{}
+		switch {
+		case field.Type == durationType:
+			prop["type"] = "string"
+			prop["pattern"] = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+			prop["description"] = "Go duration, e.g. \"30s\" or \"1m30s\""
+		case field.Type.Kind() == reflect.String:
+			prop["type"] = "string"
+		case field.Type.Kind() == reflect.Int:
+			prop["type"] = "integer"
+		case field.Type.Kind() == reflect.Bool:
+			prop["type"] = "boolean"
+		default:
+			return nil, fmt.Errorf("field %s has unsupported type %s", f.field, field.Type)
+		}
+
+		// Durations default to their string form; an empty string has no default
+		switch value := dv.FieldByIndex(field.Index); {
+		case field.Type == durationType:
+			prop["default"] = f.get(&defaults)
+		case field.Type.Kind() != reflect.String || !value.IsZero():
+			prop["default"] = value.Interface()
+		}
+		for k, v := range fieldConstraints[f.field] {
+			prop[k] = v
+		}
+		properties[strings.ToLower(f.name)] = prop
+	}
+
+	schema := map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "ServerConfig",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
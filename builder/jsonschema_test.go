@@ -0,0 +1,81 @@
+package builder
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// schemaProperties decodes the generated schema and returns its properties
+func schemaProperties(t *testing.T) map[string]map[string]any {
+	t.Helper()
+	data, err := ServerConfigJSONSchema()
+	if err != nil {
+		t.Fatalf("ServerConfigJSONSchema: %v", err)
+	}
+	var schema struct {
+		Type                 string                    `json:"type"`
+		AdditionalProperties bool                      `json:"additionalProperties"`
+		Properties           map[string]map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema isn't valid JSON: %v", err)
+	}
+	if schema.Type != "object" || schema.AdditionalProperties {
+		t.Errorf("schema type %q, additionalProperties %v", schema.Type, schema.AdditionalProperties)
+	}
+	return schema.Properties
+}
+
+func TestJSONSchemaConstraints(t *testing.T) {
+	props := schemaProperties(t)
+
+	port := props["port"]
+	if port["type"] != "integer" || port["minimum"] != 1.0 || port["maximum"] != 65535.0 || port["default"] != 8080.0 {
+		t.Errorf("port = %v, want an integer in 1-65535 defaulting to 8080", port)
+	}
+	// A low connection limit is only a warning, so the schema doesn't bound it
+	if _, ok := props["max_connections"]["minimum"]; ok {
+		t.Errorf("max_connections = %v, want no minimum", props["max_connections"])
+	}
+	level := props["log_level"]
+	if want := []any{"debug", "info", "warn", "error"}; !reflect.DeepEqual(level["enum"], want) || level["default"] != "info" {
+		t.Errorf("log_level = %v, want enum %v defaulting to info", level, want)
+	}
+	if timeout := props["timeout"]; timeout["type"] != "string" || timeout["default"] != "30s" {
+		t.Errorf("timeout = %v, want a duration string defaulting to 30s", timeout)
+	}
+	if ssl := props["ssl"]; ssl["type"] != "boolean" || ssl["default"] != false {
+		t.Errorf("ssl = %v", ssl)
+	}
+	// An empty string has no meaningful default
+	if _, ok := props["database_url"]["default"]; ok {
+		t.Error("database_url has a default")
+	}
+}
+
+func TestJSONSchemaCoversEveryLoaderKey(t *testing.T) {
+	props := schemaProperties(t)
+	if len(props) != len(envFields) {
+		t.Errorf("schema has %d properties, the loaders know %d settings", len(props), len(envFields))
+	}
+	for _, f := range envFields {
+		if _, ok := props[strings.ToLower(f.name)]; !ok {
+			t.Errorf("schema is missing %s", strings.ToLower(f.name))
+		}
+	}
+}
+
+func TestJSONSchemaOmitsMapAndSliceFields(t *testing.T) {
+	props := schemaProperties(t)
+	for _, key := range []string{"extra", "features", "virtual_hosts", "virtualhosts"} {
+		if _, ok := props[key]; ok {
+			t.Errorf("schema has a %s property, which no loader reads", key)
+		}
+		// The loaders agree: such a key is unknown
+		if err := ValidateJSON([]byte(`{"host": "localhost", "` + key + `": {}}`)); err == nil {
+			t.Errorf("ValidateJSON accepted a %s key", key)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidateJSON checks a JSON config (the format LoadDefaultsFile reads)
+// against every Build() rule without building anything, so CI can lint
+// config files. Settings missing from the JSON take the builder defaults.
+//
+// Unlike Build(), it doesn't stop at the first problem: every unknown key,
+// unparsable value and validation error is reported, joined with errors.Join.
+// Warnings are not errors and are left out.
+func ValidateJSON(data []byte) error {
+	values, err := parseJSONValues(data)
+	if err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+
+	b := NewServerConfigBuilder()
+	errs := b.setValues(values)
+
+	// A value that failed to parse leaves its field zeroed; don't report
+	// that field a second time from validate
+	unparsed := make(map[string]bool)
+	for _, err := range errs {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			unparsed[verr.Field] = true
+		}
+	}
+	config := b.copyConfig()
+	config.clampDefaultIdleConns()
+	for _, issue := range validate(&config) {
+		if issue.Severity == SeverityError && !unparsed[issue.Field] {
+			errs = append(errs, issue)
+		}
+	}
+	return errors.Join(errs...)
+}
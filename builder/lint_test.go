@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// issueFields returns the Field of every ValidationError joined into err
+func issueFields(err error) []string {
+	var fields []string
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			var verr *ValidationError
+			if errors.As(e, &verr) {
+				fields = append(fields, verr.Field)
+			}
+		}
+	}
+	return fields
+}
+
+func TestValidateJSONReportsEverything(t *testing.T) {
+	err := ValidateJSON([]byte(`{
+		"host": "",
+		"port": 70000,
+		"max_connections": 0,
+		"log_level": "verbose",
+		"timeout": "soon",
+		"colour": "blue"
+	}`))
+	if err == nil {
+		t.Fatal("ValidateJSON accepted an invalid config")
+	}
+	got := strings.Join(issueFields(err), ",")
+	if got != "Timeout,Host,Port,LogLevel" {
+		t.Errorf("fields reported = %s", got)
+	}
+	if !strings.Contains(err.Error(), `unknown setting "colour"`) {
+		t.Errorf("error = %v, want the unknown key reported", err)
+	}
+}
+
+func TestValidateJSONValid(t *testing.T) {
+	// A low connection limit is only a warning
+	if err := ValidateJSON([]byte(`{"host": "api.example.com", "port": 443, "ssl": true, "max_connections": 5}`)); err != nil {
+		t.Errorf("ValidateJSON = %v", err)
+	}
+}
+
+func TestValidateJSONMalformed(t *testing.T) {
+	for _, data := range []string{`{"host": `, `{"host": {"name": "x"}}`, `[]`} {
+		if err := ValidateJSON([]byte(data)); err == nil || !strings.HasPrefix(err.Error(), "parse config") {
+			t.Errorf("ValidateJSON(%s) = %v, want a parse error", data, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// bufferLogger is a Logger that keeps everything it is given
+type bufferLogger struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *bufferLogger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(&l.buf, format, args...)
+}
+
+func (l *bufferLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
+
+// captureLog sends the package's output to a fresh bufferLogger for the
+// rest of the test
+func captureLog(t *testing.T) *bufferLogger {
+	t.Helper()
+	l := &bufferLogger{}
+	SetLogger(l)
+	t.Cleanup(func() { SetLogger(nil) })
+	return l
+}
+
+func TestSetLoggerCapturesWarnings(t *testing.T) {
+	log := captureLog(t)
+	if _, err := NewServerConfigBuilder().Host("localhost").MaxConnections(5).Build(); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if got := log.String(); !strings.Contains(got, "config warning: MaxConnections") {
+		t.Errorf("log = %q, want the MaxConnections warning", got)
+	}
+}
+
+func TestSetLoggerNilSilences(t *testing.T) {
+	log := captureLog(t)
+	SetLogger(nil)
+	if _, err := NewServerConfigBuilder().Host("localhost").MaxConnections(5).Build(); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if got := log.String(); got != "" {
+		t.Errorf("silenced logger still got %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package builder
+
+import (
+	"slices"
+	"strings"
+)
+
+// LogLevel is how verbose the server's logging is
+type LogLevel string
+
+const (
+	LogDebug LogLevel = "debug"
+	LogInfo  LogLevel = "info"
+	LogWarn  LogLevel = "warn"
+	LogError LogLevel = "error"
+)
+
+// logLevels lists the valid levels, most verbose first
+var logLevels = []LogLevel{LogDebug, LogInfo, LogWarn, LogError}
+
+func (l LogLevel) String() string {
+	return string(l)
+}
+
+// Valid reports whether l is one of the defined levels
+func (l LogLevel) Valid() bool {
+	return slices.Contains(logLevels, l)
+}
+
+// ParseLogLevel converts a level name to a LogLevel, ignoring case and
+// surrounding spaces. Unknown names return a ValidationError that suggests
+// the closest valid level.
+func ParseLogLevel(s string) (LogLevel, error) {
+	level := LogLevel(strings.ToLower(strings.TrimSpace(s)))
+	if !level.Valid() {
+		return "", invalidLogLevel("LogLevel", "", s)
+	}
+	return level, nil
+}
+
+// invalidLogLevel builds the error for a bad level. context, when set,
+// says where the level came from (e.g. a virtual host).
+func invalidLogLevel(field, context, given string) *ValidationError {
+	names := logLevelNames()
+	return &ValidationError{
+		Field:      field,
+		Message:    context + "log level must be one of: " + strings.Join(names, ", "),
+		Suggestion: suggest(given, names),
+	}
+}
+
+func logLevelNames() []string {
+	names := make([]string, len(logLevels))
+	for i, level := range logLevels {
+		names[i] = level.String()
+	}
+	return names
+}
+
+// Level sets the log level from the LogLevel constants.
+// LogLevel(string) does the same for callers that have a plain string.
+func (b *ServerConfigBuilder) Level(level LogLevel) *ServerConfigBuilder {
+	b.config.LogLevel = level
+	b.markSet("LogLevel")
+	return b
+}
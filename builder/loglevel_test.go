@@ -0,0 +1,54 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := map[string]LogLevel{
+		"debug":     LogDebug,
+		"INFO":      LogInfo,
+		"  Warn\t ": LogWarn,
+		"error":     LogError,
+	}
+	for in, want := range tests {
+		got, err := ParseLogLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLogLevel(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+}
+
+func TestParseLogLevelInvalid(t *testing.T) {
+	for _, in := range []string{"", "verbose", "warning", "info!"} {
+		got, err := ParseLogLevel(in)
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Field != "LogLevel" || got != "" {
+			t.Errorf("ParseLogLevel(%q) = %q, %v, want a LogLevel ValidationError", in, got, err)
+		}
+	}
+}
+
+func TestLogLevelString(t *testing.T) {
+	for _, level := range logLevels {
+		parsed, err := ParseLogLevel(level.String())
+		if err != nil || parsed != level {
+			t.Errorf("%q doesn't round-trip: %q, %v", level, parsed, err)
+		}
+	}
+	if LogLevel("loud").Valid() {
+		t.Error("an unknown level is valid")
+	}
+}
+
+func TestLevelAndLogLevelSetters(t *testing.T) {
+	typed := buildOrFatal(t, NewServerConfigBuilder().Host("localhost").Level(LogWarn))
+	plain := buildOrFatal(t, NewServerConfigBuilder().Host("localhost").LogLevel("warn"))
+	if typed.LogLevel != LogWarn || plain.LogLevel != LogWarn {
+		t.Errorf("Level gave %q, LogLevel gave %q, want warn", typed.LogLevel, plain.LogLevel)
+	}
+	if _, err := NewServerConfigBuilder().Host("localhost").Level("loud").Build(); err == nil {
+		t.Error("Build accepted an invalid level")
+	}
+}
@@ -0,0 +1,11 @@
+package builder
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	SetLogger(nil)
+	os.Exit(m.Run())
+}
@@ -0,0 +1,30 @@
+package builder
+
+import "reflect"
+
+// OnSet registers fn to be called whenever the setter for field (e.g.
+// "Host") runs, with the value just assigned. Interactive tools such as
+// setup wizards can use it to react as the user fills in each field.
+// Callbacks for a field run in registration order.
+func (b *ServerConfigBuilder) OnSet(field string, fn func(value any)) *ServerConfigBuilder {
+	if b.onSet == nil {
+		b.onSet = make(map[string][]func(any))
+	}
+	b.onSet[field] = append(b.onSet[field], fn)
+	return b
+}
+
+// notifySet calls the OnSet callbacks for field with its current value
+func (b *ServerConfigBuilder) notifySet(field string) {
+	callbacks := b.onSet[field]
+	if len(callbacks) == 0 {
+		return
+	}
+	value := reflect.ValueOf(&b.config).Elem().FieldByName(field)
+	if !value.IsValid() {
+		return
+	}
+	for _, fn := range callbacks {
+		fn(value.Interface())
+	}
+}
@@ -0,0 +1,54 @@
+package builder
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOnSetFiresWithValues(t *testing.T) {
+	var got []string
+	record := func(field string) func(any) {
+		return func(v any) { got = append(got, fmt.Sprintf("%s=%v", field, v)) }
+	}
+
+	NewServerConfigBuilder().
+		OnSet("Host", record("Host")).
+		OnSet("Port", record("Port")).
+		Host("[::1]").
+		Timeout(time.Minute). // no callback registered
+		Port(9000).
+		Port(9001)
+
+	// Host is reported as stored, with the IPv6 brackets removed
+	want := []string{"Host=::1", "Port=9000", "Port=9001"}
+	if !slices.Equal(got, want) {
+		t.Errorf("callbacks saw %v, want %v", got, want)
+	}
+}
+
+func TestOnSetOrderAndTypes(t *testing.T) {
+	var order []string
+	var level any
+	NewServerConfigBuilder().
+		OnSet("LogLevel", func(v any) { order = append(order, "first"); level = v }).
+		OnSet("LogLevel", func(any) { order = append(order, "second") }).
+		LogLevel("warn")
+
+	if !slices.Equal(order, []string{"first", "second"}) {
+		t.Errorf("callbacks ran %v, want registration order", order)
+	}
+	if level != LogWarn {
+		t.Errorf("value = %#v, want the typed LogWarn", level)
+	}
+}
+
+func TestOnSetUnknownField(t *testing.T) {
+	called := false
+	b := NewServerConfigBuilder().OnSet("Nope", func(any) { called = true }).Host("localhost")
+	b.markSet("Nope")
+	if called {
+		t.Error("callback for a field ServerConfig doesn't have was called")
+	}
+}
@@ -0,0 +1,63 @@
+package builder
+
+// BuildReport is everything Build() finds out about a builder, for tooling
+// that wants more than (config, error)
+type BuildReport struct {
+	// Config is the built config, or nil if there are errors
+	Config *ServerConfig
+	// Errors are the issues that make Build() fail, in the order it checks them;
+	// Build() returns the first
+	Errors []*ValidationError
+	// Warnings are the issues Build() lets through; see Warnings()
+	Warnings []*ValidationError
+	// Explicit lists the fields set through a setter, and Defaulted the ones
+	// left at the builder's defaults, both in field order
+	Explicit  []string
+	Defaulted []string
+}
+
+// Report runs the same checks as Build() but collects every issue instead of
+// stopping at the first error. Unlike Build() it logs nothing and doesn't
+// change what Warnings() returns.
+func (b *ServerConfigBuilder) Report() BuildReport {
+	var report BuildReport
+	for _, field := range allFields {
+		if b.IsSet(field) {
+			report.Explicit = append(report.Explicit, field)
+		} else {
+			report.Defaulted = append(report.Defaulted, field)
+		}
+	}
+
+	if b.bare {
+		if err := b.checkAllSet(); err != nil {
+			report.Errors = append(report.Errors, err)
+		}
+	}
+
+	// Work on a copy of the config (immutable)
+	config := b.copyConfig()
+	if b.connsPerCore > 0 && !b.IsSet("MaxConnections") {
+		config.MaxConnections = b.connsPerCore * numCPU()
+	}
+	config.clampDefaultIdleConns()
+
+	if err := b.checkExclusive(&config); err != nil {
+		report.Errors = append(report.Errors, err)
+	}
+	if err := b.checkFeatures(&config); err != nil {
+		report.Errors = append(report.Errors, err)
+	}
+	for _, issue := range validate(&config) {
+		if issue.Severity == SeverityError {
+			report.Errors = append(report.Errors, issue)
+		} else {
+			report.Warnings = append(report.Warnings, issue)
+		}
+	}
+
+	if len(report.Errors) == 0 {
+		report.Config = &config
+	}
+	return report
+}
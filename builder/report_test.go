@@ -0,0 +1,99 @@
+package builder
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestReportExplicitAndDefaulted(t *testing.T) {
+	report := NewServerConfigBuilder().Host("example.com").Port(9000).EnableSSL(false).Report()
+
+	if want := []string{"Host", "Port", "SSL"}; !slices.Equal(report.Explicit, want) {
+		t.Errorf("Explicit = %v, want %v", report.Explicit, want)
+	}
+	if len(report.Explicit)+len(report.Defaulted) != len(allFields) {
+		t.Errorf("Explicit and Defaulted cover %d fields, want all %d", len(report.Explicit)+len(report.Defaulted), len(allFields))
+	}
+	for _, field := range []string{"Timeout", "MaxConnections", "LogLevel", "UnixSocket"} {
+		if !slices.Contains(report.Defaulted, field) {
+			t.Errorf("Defaulted = %v, missing %s", report.Defaulted, field)
+		}
+	}
+	// SSL was set to its default value, but explicitly
+	if slices.Contains(report.Defaulted, "SSL") {
+		t.Error("SSL counted as defaulted after EnableSSL(false)")
+	}
+	if report.Config == nil || report.Config.Host != "example.com" {
+		t.Errorf("Config = %+v, want the built config", report.Config)
+	}
+}
+
+func TestReportCollectsEveryIssue(t *testing.T) {
+	report := NewServerConfigBuilder().
+		Host("").
+		Port(0).
+		MaxConnections(0).
+		LogLevel("loud").
+		Report()
+
+	if report.Config != nil {
+		t.Error("Config set despite errors")
+	}
+	var fields []string
+	for _, err := range report.Errors {
+		fields = append(fields, err.Field)
+	}
+	if want := []string{"Host", "Port", "LogLevel"}; !slices.Equal(fields, want) {
+		t.Errorf("error fields = %v, want %v", fields, want)
+	}
+}
+
+func TestReportWarnings(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").MaxConnections(5)
+	report := b.Report()
+
+	if report.Config == nil || len(report.Errors) != 0 {
+		t.Fatalf("Report = %+v, want a config with no errors", report)
+	}
+	if len(report.Warnings) != 1 || report.Warnings[0].Field != "MaxConnections" {
+		t.Errorf("Warnings = %v, want the low MaxConnections warning", report.Warnings)
+	}
+	if len(b.Warnings()) != 0 {
+		t.Error("Report changed what Warnings() returns")
+	}
+}
+
+func TestReportMatchesBuild(t *testing.T) {
+	builders := map[string]*ServerConfigBuilder{
+		"valid":   NewServerConfigBuilder().Host("localhost").Port(8443),
+		"invalid": NewServerConfigBuilder().Host("localhost").Port(-1).MaxConnections(0),
+		"bare":    NewBareServerConfigBuilder().Host("localhost"),
+	}
+	for name, b := range builders {
+		report := b.Report()
+		config, err := b.Build()
+		if (err == nil) != (report.Config != nil) {
+			t.Errorf("%s: Build error %v, Report config %v", name, err, report.Config)
+			continue
+		}
+		if err != nil && err.Error() != report.Errors[0].Error() {
+			t.Errorf("%s: Build = %v, want the report's first error %v", name, err, report.Errors[0])
+		}
+		if config != nil && !reflect.DeepEqual(config, report.Config) {
+			t.Errorf("%s: Build = %+v, Report = %+v", name, config, report.Config)
+		}
+	}
+}
+
+func TestReportBareBuilder(t *testing.T) {
+	report := fullBare().Report()
+	if len(report.Defaulted) != 0 || len(report.Errors) != 0 {
+		t.Errorf("fully set bare builder: Defaulted = %v, Errors = %v", report.Defaulted, report.Errors)
+	}
+
+	report = NewBareServerConfigBuilder().Host("localhost").Port(8080).Report()
+	if len(report.Errors) == 0 || report.Errors[0].Field != "SSL" {
+		t.Errorf("Errors = %v, want the missing fields first", report.Errors)
+	}
+}
@@ -0,0 +1,111 @@
+package builder
+
+import (
+	"errors"
+	"maps"
+	"reflect"
+)
+
+// Layered configs
+//
+// Apps often keep a base config, override part of it per environment, and
+// override a bit more on a developer's machine. Resolve stacks such layers.
+//
+// The tricky part is telling "this layer sets Port to 8080" apart from "this
+// layer doesn't mention Port and 8080 is just the default". Comparing against
+// zero values gets it wrong (SSL=false is a real choice) and so does
+// comparing against defaults. Instead every config remembers which fields
+// were set through a builder setter (the same tracking behind IsSet), and a
+// layer only overrides the fields it set. Layers usually aren't valid on
+// their own, so build them with Layer(), which skips validation.
+
+// Layer returns the builder's config as a layer for Resolve, without
+// validating it
+func (b *ServerConfigBuilder) Layer() *ServerConfig {
+	config := b.copyConfig()
+	return &config
+}
+
+// copyConfig returns the builder's config sharing no maps or slices with
+// the builder, so later setter calls can't reach into it
+func (b *ServerConfigBuilder) copyConfig() ServerConfig {
+	config := b.config
+	config.Extra = cloneExtra(b.config.Extra)
+	config.Features = maps.Clone(b.config.Features)
+	config.VirtualHosts = cloneVHosts(b.config.VirtualHosts)
+	config.set = b.setRecord()
+	return config
+}
+
+// setRecord copies the builder's set-tracking for a config. It is never nil,
+// so Resolve can tell "nothing was set" from "not made by a builder".
+func (b *ServerConfigBuilder) setRecord() map[string]bool {
+	set := maps.Clone(b.set)
+	if set == nil {
+		set = make(map[string]bool)
+	}
+	return set
+}
+
+// Resolve merges layers in order, later layers overriding earlier ones, and
+// validates the result with the Build() rules. The first layer supplies every
+// field, defaults included; each later layer only the fields it set
+// explicitly. A layer's virtual hosts replace the earlier list as a whole;
+// Extra settings and feature flags are merged key by key.
+//
+// Configs that weren't made by a builder carry no set-tracking; for those,
+// every non-zero field counts as set.
+func Resolve(layers ...*ServerConfig) (*ServerConfig, error) {
+	if len(layers) == 0 {
+		return nil, errors.New("resolve needs at least one config layer")
+	}
+
+	result := layers[0].clone()
+	for _, layer := range layers[1:] {
+		dst := reflect.ValueOf(result).Elem()
+		src := reflect.ValueOf(layer).Elem()
+		for _, field := range allFields {
+			value := src.FieldByName(field)
+			if !layer.isSet(field) && (layer.set != nil || value.IsZero()) {
+				continue
+			}
+			dst.FieldByName(field).Set(value)
+			result.markSet(field)
+		}
+		if layer.isSet("VirtualHosts") || (layer.set == nil && len(layer.VirtualHosts) > 0) {
+			result.VirtualHosts = cloneVHosts(layer.VirtualHosts)
+			result.markSet("VirtualHosts")
+		}
+		for name, enabled := range layer.Features {
+			if result.Features == nil {
+				result.Features = make(map[string]bool)
+			}
+			result.Features[name] = enabled
+		}
+		for key, value := range layer.Extra {
+			if result.Extra == nil {
+				result.Extra = make(map[string]any)
+			}
+			result.Extra[key] = value
+		}
+	}
+
+	result.clampDefaultIdleConns()
+	for _, issue := range validate(result) {
+		if issue.Severity == SeverityError {
+			return nil, issue
+		}
+	}
+	return result, nil
+}
+
+func (c *ServerConfig) isSet(field string) bool {
+	return c.set[field]
+}
+
+func (c *ServerConfig) markSet(field string) {
+	if c.set == nil {
+		c.set = make(map[string]bool)
+	}
+	c.set[field] = true
+}
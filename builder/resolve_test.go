@@ -0,0 +1,96 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResolveThreeLayers(t *testing.T) {
+	base := NewServerConfigBuilder().
+		Host("app.internal").
+		Port(8080).
+		Timeout(time.Minute).
+		EnableFeature("search").
+		Set("region", "eu").
+		Layer()
+	staging := NewServerConfigBuilder().
+		Host("staging.example.com").
+		EnableSSL(true).
+		EnableFeature("beta").
+		Layer()
+	local := NewServerConfigBuilder().
+		Port(9000).
+		EnableSSL(false). // an explicit false still overrides staging
+		Level(LogDebug).
+		DisableFeature("search").
+		Set("region", "local").
+		Layer()
+
+	cfg, err := Resolve(base, staging, local)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if cfg.Host != "staging.example.com" || cfg.Port != 9000 || cfg.SSL || cfg.Timeout != time.Minute || cfg.LogLevel != LogDebug {
+		t.Errorf("resolved %+v", cfg)
+	}
+	if cfg.FeatureEnabled("search") || !cfg.FeatureEnabled("beta") {
+		t.Errorf("features = %v, want beta on and search off", cfg.Features)
+	}
+	if region, _ := cfg.GetString("region"); region != "local" {
+		t.Errorf("region = %q, want local", region)
+	}
+	// Defaults from later layers don't clobber the base
+	if cfg.MaxConnections != 100 || cfg.ReadTimeout != 10*time.Second {
+		t.Errorf("unset fields changed: %+v", cfg)
+	}
+}
+
+func TestResolveLeavesLayersAlone(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").EnableFeature("search").Set("region", "eu")
+	base := b.Layer()
+
+	// Changing the builder afterwards doesn't reach the layer
+	b.DisableFeature("search").Set("region", "us")
+	if !base.FeatureEnabled("search") {
+		t.Error("DisableFeature on the builder changed an earlier layer")
+	}
+	if region, _ := base.GetString("region"); region != "eu" {
+		t.Errorf("Set on the builder changed an earlier layer: region = %q", region)
+	}
+
+	override := NewServerConfigBuilder().EnableFeature("beta").Set("tier", 2).Layer()
+	cfg, err := Resolve(base, override)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Features["search"] = false
+	if _, ok := base.Features["beta"]; ok || !base.FeatureEnabled("search") {
+		t.Errorf("Resolve shared maps with its first layer: %v", base.Features)
+	}
+}
+
+func TestResolvePlainConfigs(t *testing.T) {
+	// Configs not made by a builder override with their non-zero fields
+	cfg, err := Resolve(
+		buildOrFatal(t, NewServerConfigBuilder().Host("localhost").Port(8080)),
+		&ServerConfig{Port: 9090},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "localhost" || cfg.Port != 9090 || cfg.MaxConnections != 100 {
+		t.Errorf("resolved %+v", cfg)
+	}
+}
+
+func TestResolveValidates(t *testing.T) {
+	_, err := Resolve(NewServerConfigBuilder().Layer())
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "Host" {
+		t.Errorf("Resolve = %v, want a Host ValidationError", err)
+	}
+	if _, err := Resolve(); err == nil {
+		t.Error("Resolve with no layers succeeded")
+	}
+}
@@ -0,0 +1,62 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildWarningSucceeds(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").MaxConnections(5)
+	config, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build = %v, want success with a warning", err)
+	}
+	if config.MaxConnections != 5 {
+		t.Errorf("MaxConnections = %d, want 5", config.MaxConnections)
+	}
+
+	warnings := b.Warnings()
+	if len(warnings) != 1 || warnings[0].Field != "MaxConnections" || warnings[0].Severity != SeverityWarning {
+		t.Fatalf("Warnings() = %v, want one MaxConnections warning", warnings)
+	}
+
+	// Warnings() is a copy, and a clean build clears it
+	warnings[0] = nil
+	if b.Warnings()[0] == nil {
+		t.Error("Warnings() returned the builder's own slice")
+	}
+	if _, err := b.MaxConnections(50).Build(); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Warnings(); len(got) != 0 {
+		t.Errorf("Warnings() after a clean build = %v, want none", got)
+	}
+}
+
+func TestBuildErrorFails(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").Port(0)
+	config, err := b.Build()
+	if config != nil {
+		t.Error("Build returned a config despite an error")
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "Port" || verr.Severity != SeverityError {
+		t.Errorf("Build = %v, want an error-level Port issue", err)
+	}
+}
+
+func TestBuildZeroMaxConnectionsWarns(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").MaxConnections(0)
+	if _, err := b.Build(); err != nil {
+		t.Fatalf("Build = %v, want success with a warning", err)
+	}
+	if warnings := b.Warnings(); len(warnings) != 1 || warnings[0].Field != "MaxConnections" {
+		t.Errorf("Warnings() = %v, want one MaxConnections warning", warnings)
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	if SeverityError.String() != "error" || SeverityWarning.String() != "warning" {
+		t.Errorf("String() = %q and %q", SeverityError, SeverityWarning)
+	}
+}
@@ -0,0 +1,45 @@
+package builder
+
+import "strings"
+
+// maxSuggestionDistance is how many edits away a value may be and still
+// be treated as a typo of a valid option rather than something unrelated.
+const maxSuggestionDistance = 2
+
+// suggest returns the option closest to input, or "" if none is close enough
+func suggest(input string, options []string) string {
+	input = strings.ToLower(input)
+	best, bestDist := "", maxSuggestionDistance+1
+	for _, option := range options {
+		if d := levenshtein(input, option); d < bestDist {
+			best, bestDist = option, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the minimum number of single-character insertions,
+// deletions and substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// prev holds the distances for the previous row of the DP table
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
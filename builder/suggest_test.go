@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuildSuggestsLogLevel(t *testing.T) {
+	tests := []struct {
+		level      string
+		suggestion string
+	}{
+		{"infi", "info"},
+		{"debgu", "debug"},
+		{"WARNN", "warn"},
+		{"eror", "error"},
+		{"verbose", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			_, err := NewServerConfigBuilder().Host("localhost").LogLevel(tt.level).Build()
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Field != "LogLevel" {
+				t.Fatalf("Build = %v, want a LogLevel ValidationError", err)
+			}
+			if verr.Suggestion != tt.suggestion {
+				t.Errorf("Suggestion = %q, want %q", verr.Suggestion, tt.suggestion)
+			}
+			if !strings.Contains(verr.Message, "debug, info, warn, error") {
+				t.Errorf("Message %q doesn't list the allowed levels", verr.Message)
+			}
+			if hasHint := strings.Contains(err.Error(), "did you mean"); hasHint != (tt.suggestion != "") {
+				t.Errorf("Error() = %q, hint present = %v", err.Error(), hasHint)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"info", "info", 0},
+		{"infi", "info", 1},
+		{"inf", "info", 1},
+		{"infoo", "info", 1},
+		{"kitten", "sitting", 3},
+		{"", "warn", 4},
+		{"ünï", "uni", 2},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := levenshtein(tt.b, tt.a); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package builder
+
+import "errors"
+
+// ErrNothingToUndo is returned by Undo when there is no checkpoint left
+var ErrNothingToUndo = errors.New("no checkpoint to undo to")
+
+// Checkpoint saves the builder's current state on an undo stack, so a later
+// Undo can return to it. Config wizards call it before each step to support
+// going "back" any number of times.
+func (b *ServerConfigBuilder) Checkpoint() *ServerConfigBuilder {
+	b.checkpoints = append(b.checkpoints, b.Clone())
+	return b
+}
+
+// Undo restores the state saved by the most recent Checkpoint and removes
+// it from the stack. Fields, set-tracking, exclusive groups, allowed
+// features and OnSet callbacks all go back to how they were; OnSet
+// callbacks aren't called for the restored values.
+func (b *ServerConfigBuilder) Undo() error {
+	n := len(b.checkpoints)
+	if n == 0 {
+		return ErrNothingToUndo
+	}
+	saved, rest := b.checkpoints[n-1], b.checkpoints[:n-1]
+	*b = *saved
+	b.checkpoints = rest
+	return nil
+}
@@ -0,0 +1,93 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUndoSteps(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost")
+	b.Checkpoint().Port(9000)
+	b.Checkpoint().EnableSSL(true).Port(443)
+	b.Checkpoint().Timeout(5*time.Second).Set("region", "eu")
+
+	config := buildOrFatal(t, b)
+	if config.Port != 443 || !config.SSL || config.Timeout != 5*time.Second {
+		t.Fatalf("config before undo = %+v", config)
+	}
+
+	if err := b.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	config = buildOrFatal(t, b)
+	if config.Port != 443 || !config.SSL || config.Timeout != 30*time.Second {
+		t.Errorf("after one undo = %+v, want the timeout step gone", config)
+	}
+	if _, err := config.GetString("region"); !errors.Is(err, ErrExtraNotFound) {
+		t.Errorf("region after undo: %v, want ErrExtraNotFound", err)
+	}
+
+	if err := b.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	config = buildOrFatal(t, b)
+	if config.Host != "localhost" || config.Port != 8080 || config.SSL {
+		t.Errorf("after undoing everything = %+v, want the starting state", config)
+	}
+	if b.IsSet("Port") {
+		t.Error("IsSet(Port) after undoing the step that set it")
+	}
+
+	if err := b.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo with an empty stack = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestUndoWithoutCheckpoint(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").Port(9000)
+	if err := b.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("Undo = %v, want ErrNothingToUndo", err)
+	}
+	if config := buildOrFatal(t, b); config.Port != 9000 {
+		t.Errorf("failed Undo changed the port to %d", config.Port)
+	}
+}
+
+func TestUndoRestoresMaps(t *testing.T) {
+	b := NewServerConfigBuilder().Host("localhost").EnableFeature("beta").Set("tier", 1)
+	b.Checkpoint()
+	b.DisableFeature("beta").EnableFeature("gamma").Set("tier", 2)
+
+	if err := b.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	config := buildOrFatal(t, b)
+	if !config.FeatureEnabled("beta") || config.FeatureEnabled("gamma") {
+		t.Errorf("features after undo = %v, want only beta", config.Features)
+	}
+	if tier, _ := config.GetInt("tier"); tier != 1 {
+		t.Errorf("tier after undo = %d, want 1", tier)
+	}
+}
+
+func TestUndoDoesNotNotifyOnSet(t *testing.T) {
+	var calls int
+	b := NewServerConfigBuilder().OnSet("Port", func(any) { calls++ })
+	b.Checkpoint().Port(9000)
+	if err := b.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("OnSet ran %d times, want only for the Port call", calls)
+	}
+
+	// The callback survives the undo
+	b.Port(9001)
+	if calls != 2 {
+		t.Errorf("OnSet ran %d times after undo, want 2", calls)
+	}
+}
@@ -0,0 +1,143 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VHost is a virtual host served by the same server. Pattern is an exact
+// hostname ("api.example.com") or a wildcard covering one level of
+// subdomains ("*.example.com"). The overrides are optional: a nil SSL or an
+// empty LogLevel inherits the server-wide setting.
+type VHost struct {
+	Pattern  string
+	SSL      *bool
+	LogLevel LogLevel
+}
+
+// VHostBuilder sets the overrides for one virtual host
+type VHostBuilder struct {
+	vhost VHost
+}
+
+func (v *VHostBuilder) EnableSSL(enable bool) *VHostBuilder {
+	v.vhost.SSL = &enable
+	return v
+}
+
+func (v *VHostBuilder) LogLevel(level string) *VHostBuilder {
+	return v.Level(LogLevel(level))
+}
+
+func (v *VHostBuilder) Level(level LogLevel) *VHostBuilder {
+	v.vhost.LogLevel = level
+	return v
+}
+
+// VirtualHost adds a virtual host matching pattern. fn, which may be nil,
+// sets its overrides:
+//
+//	b.VirtualHost("*.example.com", func(v *VHostBuilder) {
+//		v.EnableSSL(true).LogLevel("warn")
+//	})
+//
+// Patterns are checked by Build().
+func (b *ServerConfigBuilder) VirtualHost(pattern string, fn func(*VHostBuilder)) *ServerConfigBuilder {
+	v := &VHostBuilder{vhost: VHost{Pattern: strings.ToLower(pattern)}}
+	if fn != nil {
+		fn(v)
+	}
+	b.config.VirtualHosts = append(b.config.VirtualHosts, v.vhost)
+	b.markSet("VirtualHosts")
+	return b
+}
+
+// SSLFor returns whether SSL is on for the virtual host, falling back to the server setting
+func (c *ServerConfig) SSLFor(v VHost) bool {
+	if v.SSL != nil {
+		return *v.SSL
+	}
+	return c.SSL
+}
+
+// LogLevelFor returns the virtual host's log level, falling back to the server setting
+func (c *ServerConfig) LogLevelFor(v VHost) LogLevel {
+	if v.LogLevel != "" {
+		return v.LogLevel
+	}
+	return c.LogLevel
+}
+
+// cloneVHosts deep-copies virtual hosts so configs never share SSL overrides
+func cloneVHosts(vhosts []VHost) []VHost {
+	if vhosts == nil {
+		return nil
+	}
+	out := make([]VHost, len(vhosts))
+	for i, v := range vhosts {
+		out[i] = v
+		if v.SSL != nil {
+			ssl := *v.SSL
+			out[i].SSL = &ssl
+		}
+	}
+	return out
+}
+
+// validateVHosts checks patterns and overrides, reporting the first problem
+func validateVHosts(vhosts []VHost) *ValidationError {
+	seen := make(map[string]bool, len(vhosts))
+	for _, v := range vhosts {
+		if err := validatePattern(v.Pattern); err != "" {
+			return &ValidationError{Field: "VirtualHosts", Message: fmt.Sprintf("virtual host %q: %s", v.Pattern, err)}
+		}
+		if seen[v.Pattern] {
+			return &ValidationError{Field: "VirtualHosts", Message: fmt.Sprintf("virtual host %q is defined more than once", v.Pattern)}
+		}
+		seen[v.Pattern] = true
+
+		if v.LogLevel != "" && !v.LogLevel.Valid() {
+			return invalidLogLevel("VirtualHosts", fmt.Sprintf("virtual host %q: ", v.Pattern), string(v.LogLevel))
+		}
+	}
+	return nil
+}
+
+// validatePattern returns what's wrong with a virtual host pattern, or "" if
+// it's fine. A wildcard may only stand for the whole leftmost label and must
+// be followed by at least two labels, so "*.com" is rejected.
+func validatePattern(pattern string) string {
+	if pattern == "" {
+		return "pattern must not be empty"
+	}
+	host := pattern
+	if rest, ok := strings.CutPrefix(pattern, "*."); ok {
+		if strings.Count(rest, ".") < 1 {
+			return "wildcard must be followed by a domain with at least two labels, like *.example.com"
+		}
+		host = rest
+	}
+	if strings.Contains(host, "*") {
+		return "wildcard is only allowed as the whole first label, like *.example.com"
+	}
+	for _, label := range strings.Split(host, ".") {
+		if !validLabel(label) {
+			return fmt.Sprintf("invalid hostname label %q", label)
+		}
+	}
+	return ""
+}
+
+// validLabel checks one DNS label: 1-63 letters, digits or hyphens, not
+// starting or ending with a hyphen
+func validLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
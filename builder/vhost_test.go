@@ -0,0 +1,75 @@
+package builder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVirtualHostPatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr string
+	}{
+		{"api.example.com", ""},
+		{"*.example.com", ""},
+		{"*.Example.COM", ""}, // lowercased by the setter
+		{"localhost", ""},
+		{"", "must not be empty"},
+		{"*.com", "at least two labels"},
+		{"api.*.example.com", "whole first label"},
+		{"*api.example.com", "whole first label"},
+		{"-api.example.com", "invalid hostname label"},
+		{"api..example.com", "invalid hostname label"},
+		{"api_v2.example.com", "invalid hostname label"},
+		{strings.Repeat("a", 64) + ".example.com", "invalid hostname label"},
+	}
+	for _, tt := range tests {
+		_, err := NewServerConfigBuilder().Host("localhost").VirtualHost(tt.pattern, nil).Build()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%q: Build = %v", tt.pattern, err)
+			}
+			continue
+		}
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Field != "VirtualHosts" || !strings.Contains(verr.Message, tt.wantErr) {
+			t.Errorf("%q: Build = %v, want a VirtualHosts error containing %q", tt.pattern, err, tt.wantErr)
+		}
+	}
+}
+
+func TestVirtualHostDuplicates(t *testing.T) {
+	_, err := NewServerConfigBuilder().
+		Host("localhost").
+		VirtualHost("*.example.com", nil).
+		VirtualHost("api.example.com", nil).
+		VirtualHost("API.example.com", nil).
+		Build()
+	if err == nil || !strings.Contains(err.Error(), `"api.example.com" is defined more than once`) {
+		t.Errorf("Build = %v, want the duplicate reported", err)
+	}
+}
+
+func TestVirtualHostOverrides(t *testing.T) {
+	cfg := buildOrFatal(t, NewServerConfigBuilder().
+		Host("localhost").
+		Level(LogInfo).
+		VirtualHost("secure.example.com", func(v *VHostBuilder) { v.EnableSSL(true).LogLevel("warn") }).
+		VirtualHost("plain.example.com", nil))
+
+	secure, plain := cfg.VirtualHosts[0], cfg.VirtualHosts[1]
+	if !cfg.SSLFor(secure) || cfg.LogLevelFor(secure) != LogWarn {
+		t.Errorf("secure host: SSL %v, level %s", cfg.SSLFor(secure), cfg.LogLevelFor(secure))
+	}
+	if cfg.SSLFor(plain) || cfg.LogLevelFor(plain) != LogInfo {
+		t.Errorf("plain host should inherit: SSL %v, level %s", cfg.SSLFor(plain), cfg.LogLevelFor(plain))
+	}
+
+	_, err := NewServerConfigBuilder().Host("localhost").
+		VirtualHost("api.example.com", func(v *VHostBuilder) { v.LogLevel("loud") }).
+		Build()
+	if err == nil {
+		t.Error("Build accepted an invalid virtual host log level")
+	}
+}
@@ -0,0 +1,75 @@
+package builder
+
+import (
+	"errors"
+	"sync"
+)
+
+// ConfigSubscriber is notified when the watched config changes.
+// Comparing old and next lets it reconfigure only what actually changed.
+type ConfigSubscriber func(old, next *ServerConfig)
+
+// ConfigWatcher holds the live config and tells subscribers when it is
+// replaced - the Observer pattern applied to the builder's output, e.g. for
+// hot-reloading a config file without restarting the server.
+type ConfigWatcher struct {
+	// notifyMu is held by Update from the swap until every subscriber has
+	// returned, so concurrent updates reach subscribers one at a time and in
+	// the order they took effect
+	notifyMu sync.Mutex
+
+	mu          sync.Mutex
+	current     *ServerConfig
+	subscribers []ConfigSubscriber
+}
+
+// NewConfigWatcher starts watching from an initial, already built config
+func NewConfigWatcher(initial *ServerConfig) *ConfigWatcher {
+	return &ConfigWatcher{current: initial}
+}
+
+// Current returns the config in effect
+func (w *ConfigWatcher) Current() *ServerConfig {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Subscribe registers fn to be called on every successful Update
+func (w *ConfigWatcher) Subscribe(fn ConfigSubscriber) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Update validates next with the same rules as Build() and, if it passes,
+// makes it current and notifies subscribers. An invalid config is rejected
+// and nobody is notified.
+//
+// Subscribers run one update at a time. They may call Current or Subscribe,
+// but calling Update from a subscriber deadlocks.
+func (w *ConfigWatcher) Update(next *ServerConfig) error {
+	if next == nil {
+		return errors.New("config must not be nil")
+	}
+	for _, issue := range validate(next) {
+		if issue.Severity == SeverityError {
+			return issue
+		}
+	}
+
+	w.notifyMu.Lock()
+	defer w.notifyMu.Unlock()
+
+	w.mu.Lock()
+	old := w.current
+	w.current = next
+	subscribers := append([]ConfigSubscriber(nil), w.subscribers...)
+	w.mu.Unlock()
+
+	// Notify outside the lock so subscribers may call Current or Subscribe
+	for _, fn := range subscribers {
+		fn(old, next)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package builder
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// mustBuild builds a config on localhost with the given port
+func mustBuild(t *testing.T, port int) *ServerConfig {
+	t.Helper()
+	cfg, err := NewServerConfigBuilder().Host("localhost").Port(port).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return cfg
+}
+
+func TestConfigWatcherNotifies(t *testing.T) {
+	initial := mustBuild(t, 8080)
+	next := mustBuild(t, 9090)
+	w := NewConfigWatcher(initial)
+
+	var calls int
+	for range 2 {
+		w.Subscribe(func(old, cur *ServerConfig) {
+			calls++
+			if old != initial || cur != next {
+				t.Errorf("subscriber got ports %d -> %d, want 8080 -> 9090", old.Port, cur.Port)
+			}
+			// Subscribers run outside the lock and see the new config
+			if w.Current() != next {
+				t.Error("Current inside subscriber is not the new config")
+			}
+		})
+	}
+
+	if err := w.Update(next); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("subscribers called %d times, want 2", calls)
+	}
+	if w.Current() != next {
+		t.Error("Current is not the updated config")
+	}
+}
+
+func TestConfigWatcherRejectsInvalid(t *testing.T) {
+	initial := mustBuild(t, 8080)
+	w := NewConfigWatcher(initial)
+	w.Subscribe(func(old, cur *ServerConfig) {
+		t.Error("subscriber notified of a rejected update")
+	})
+
+	invalid := *initial
+	invalid.Port = 0
+	if err := w.Update(&invalid); err == nil {
+		t.Error("Update accepted port 0")
+	}
+	if err := w.Update(nil); err == nil {
+		t.Error("Update accepted nil")
+	}
+	if w.Current() != initial {
+		t.Error("rejected update replaced the current config")
+	}
+}
+
+func TestConfigWatcherAcceptsWarnings(t *testing.T) {
+	w := NewConfigWatcher(mustBuild(t, 8080))
+	lowConns := mustBuild(t, 8080)
+	lowConns.MaxConnections = 5 // a warning, not an error
+	if err := w.Update(lowConns); err != nil {
+		t.Errorf("Update with only warnings: %v", err)
+	}
+}
+
+func TestConfigWatcherSerialisesNotifications(t *testing.T) {
+	initial := mustBuild(t, 8000)
+	w := NewConfigWatcher(initial)
+
+	// Each notification must start from the config the previous one ended
+	// on; an update overtaking another would break the chain
+	last := initial
+	var inFlight int
+	var mu sync.Mutex
+	w.Subscribe(func(old, next *ServerConfig) {
+		mu.Lock()
+		inFlight++
+		if inFlight > 1 {
+			t.Error("subscriber called for two updates at once")
+		}
+		if old != last {
+			t.Errorf("notified %d -> %d, but the last config seen was %d", old.Port, next.Port, last.Port)
+		}
+		last = next
+		mu.Unlock()
+
+		time.Sleep(100 * time.Microsecond) // give an overlapping update a chance to show
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	})
+
+	configs := make([]*ServerConfig, 50)
+	for i := range configs {
+		configs[i] = mustBuild(t, 8001+i)
+	}
+	var wg sync.WaitGroup
+	for _, cfg := range configs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.Update(cfg); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if w.Current() != last {
+		t.Errorf("Current is port %d, but subscribers last saw %d", w.Current().Port, last.Port)
+	}
+}
@@ -0,0 +1,28 @@
+package factory
+
+import "errors"
+
+// ErrInsufficientFunds is returned when a balance pre-check shows the
+// account cannot cover the requested amount.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// BalanceChecker is implemented by processors that can report the
+// available balance of the account they charge.
+type BalanceChecker interface {
+	Balance() (float64, error)
+}
+
+// BalanceSource looks up the balance for an account number.
+// In production this would call the bank; in tests it can return fixed values.
+type BalanceSource func(accountNumber string) (float64, error)
+
+// FixedBalances is a BalanceSource backed by a static account → balance table
+func FixedBalances(balances map[string]float64) BalanceSource {
+	return func(accountNumber string) (float64, error) {
+		balance, ok := balances[accountNumber]
+		if !ok {
+			return 0, errors.New("unknown account: " + accountNumber)
+		}
+		return balance, nil
+	}
+}
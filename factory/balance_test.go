@@ -0,0 +1,65 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBankTransferBalanceCheck(t *testing.T) {
+	balances := FixedBalances(map[string]float64{"12345678": 100})
+	tests := []struct {
+		name         string
+		checkBalance bool
+		amount       float64
+		wantErr      error
+	}{
+		{"covered", true, 60, nil},
+		{"exact balance", true, 100, nil},
+		{"insufficient", true, 100.01, ErrInsufficientFunds},
+		{"check disabled", false, 500, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewBankTransferProcessor("12345678", "021000021", balances, tt.checkBalance)
+			if err != nil {
+				t.Fatalf("NewBankTransferProcessor: %v", err)
+			}
+			if err := p.Process(tt.amount); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Process(%v) = %v, want %v", tt.amount, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBankTransferBalance(t *testing.T) {
+	var checker BalanceChecker
+	p, err := NewBankTransferProcessor("12345678", "021000021", FixedBalances(map[string]float64{"12345678": 42.5}), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker = p
+	if got, err := checker.Balance(); err != nil || got != 42.5 {
+		t.Errorf("Balance() = %v, %v; want 42.5, nil", got, err)
+	}
+
+	unknown, err := NewBankTransferProcessor("87654321", "021000021", FixedBalances(nil), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unknown.Process(1); err == nil {
+		t.Error("Process with an unknown account succeeded")
+	}
+}
+
+func TestBankTransferBalanceCheckNeedsSource(t *testing.T) {
+	if _, err := NewBankTransferProcessor("12345678", "021000021", nil, true); err == nil {
+		t.Error("NewBankTransferProcessor with checkBalance and no source succeeded")
+	}
+	p, err := NewBankTransferProcessor("12345678", "021000021", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Balance(); err == nil {
+		t.Error("Balance without a source succeeded")
+	}
+}
@@ -0,0 +1,81 @@
+package factory
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult is the outcome of one payment in a batch
+type BatchResult struct {
+	Index   int // position in the amounts slice
+	Amount  float64
+	Receipt *Receipt
+	Err     error
+}
+
+// BatchProcessor charges many payments through one processor with a bounded
+// number running at once
+type BatchProcessor struct {
+	processor PaymentProcessor
+	workers   int
+}
+
+// NewBatchProcessor returns a batch processor running up to workers charges
+// concurrently. Fewer than one worker is treated as one.
+func NewBatchProcessor(p PaymentProcessor, workers int) *BatchProcessor {
+	if workers < 1 {
+		workers = 1
+	}
+	return &BatchProcessor{processor: p, workers: workers}
+}
+
+// ProcessBatchCtx charges every amount and returns one result per amount, in
+// input order. Each finished charge is also sent to notifier (which may be nil).
+//
+// Once ctx is cancelled no new charges are started: charges already running
+// finish (or see the cancelled context), and every amount not yet started
+// gets ctx's error as its result without being reported to the notifier.
+func (b *BatchProcessor) ProcessBatchCtx(ctx context.Context, amounts []float64, notifier *PaymentNotifier) []BatchResult {
+	results := make([]BatchResult, len(amounts))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < b.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				receipt, err := ProcessCtx(ctx, b.processor, amounts[i])
+				results[i] = BatchResult{Index: i, Amount: amounts[i], Receipt: receipt, Err: err}
+				notifier.Notify(PaymentEvent{
+					Processor: b.processor.GetName(),
+					Amount:    amounts[i],
+					Receipt:   receipt,
+					Err:       err,
+				})
+			}
+		}()
+	}
+
+	next := 0
+dispatch:
+	for ; next < len(amounts); next++ {
+		// Check first: when a worker is free and ctx is done, select would
+		// pick between the two cases at random
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := next; i < len(amounts); i++ {
+		results[i] = BatchResult{Index: i, Amount: amounts[i], Err: ctx.Err()}
+	}
+	return results
+}
@@ -0,0 +1,120 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cancelAfter is a processor that cancels a context once it has charged n times
+type cancelAfter struct {
+	recordingProcessor
+	n      int
+	cancel context.CancelFunc
+}
+
+func (c *cancelAfter) Process(amount float64) error {
+	err := c.recordingProcessor.Process(amount)
+	if len(c.charged()) == c.n {
+		c.cancel()
+	}
+	return err
+}
+
+func TestProcessBatchCtxCancelMidBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := &cancelAfter{n: 3, cancel: cancel}
+
+	var mu sync.Mutex
+	var events []PaymentEvent
+	notifier := NewPaymentNotifier()
+	notifier.Subscribe(func(e PaymentEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+
+	amounts := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	results := NewBatchProcessor(p, 1).ProcessBatchCtx(ctx, amounts, notifier)
+
+	if len(results) != len(amounts) {
+		t.Fatalf("got %d results, want %d", len(results), len(amounts))
+	}
+	for i, r := range results {
+		if r.Index != i || r.Amount != amounts[i] {
+			t.Errorf("result %d = %+v, out of order", i, r)
+		}
+		if i < 3 {
+			if r.Err != nil || r.Receipt == nil {
+				t.Errorf("item %d: %v, want charged before the cancel", i, r.Err)
+			}
+		} else if !errors.Is(r.Err, context.Canceled) || r.Receipt != nil {
+			t.Errorf("item %d: %v, want context.Canceled", i, r.Err)
+		}
+	}
+	if charged := p.charged(); len(charged) != 3 {
+		t.Errorf("charged %v, want only the first three", charged)
+	}
+	// Items never started aren't reported to observers
+	if len(events) != 3 {
+		t.Errorf("observers saw %d events, want 3", len(events))
+	}
+}
+
+// concurrencyProbe records the most charges it ever saw running at once
+type concurrencyProbe struct {
+	running, peak atomic.Int32
+}
+
+func (c *concurrencyProbe) Process(amount float64) error {
+	n := c.running.Add(1)
+	defer c.running.Add(-1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+func (c *concurrencyProbe) GetName() string { return "Probe" }
+
+func TestProcessBatchCtxBoundsConcurrency(t *testing.T) {
+	probe := &concurrencyProbe{}
+	amounts := make([]float64, 30)
+	for i := range amounts {
+		amounts[i] = float64(i + 1)
+	}
+
+	results := NewBatchProcessor(probe, 3).ProcessBatchCtx(context.Background(), amounts, nil)
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("item %d: %v", r.Index, r.Err)
+		}
+	}
+	if peak := probe.peak.Load(); peak > 3 {
+		t.Errorf("%d charges ran at once, want at most 3", peak)
+	}
+}
+
+func TestProcessBatchCtxAlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	inner := &recordingProcessor{}
+
+	results := NewBatchProcessor(inner, 0).ProcessBatchCtx(ctx, []float64{1, 2}, nil)
+	for _, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("item %d: %v, want context.Canceled", r.Index, r.Err)
+		}
+	}
+	if len(inner.charged()) != 0 {
+		t.Error("charged after the context was cancelled")
+	}
+}
@@ -0,0 +1,77 @@
+package factory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+)
+
+// CachingFactory memoizes processors so identical requests share one instance.
+// Creation can be expensive once validation runs (Luhn, ABA checksums),
+// so repeating it for the same type and details is wasteful.
+//
+// Only use this for stateless processors: every caller with the same inputs
+// gets the same shared instance, so any state it holds is shared too.
+type CachingFactory struct {
+	create func(PaymentType, map[string]string) (PaymentProcessor, error)
+
+	mu    sync.Mutex
+	cache map[string]PaymentProcessor
+}
+
+// NewCachingFactory returns a CachingFactory backed by CreatePaymentProcessor
+func NewCachingFactory() *CachingFactory {
+	return &CachingFactory{
+		create: CreatePaymentProcessor,
+		cache:  make(map[string]PaymentProcessor),
+	}
+}
+
+// Create returns the cached processor for these inputs, building it on first use.
+// Failed creations are not cached, so a later call can retry.
+func (f *CachingFactory) Create(paymentType PaymentType, details map[string]string) (PaymentProcessor, error) {
+	key := cacheKey(paymentType, details)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if processor, ok := f.cache[key]; ok {
+		return processor, nil
+	}
+
+	processor, err := f.create(paymentType, details)
+	if err != nil {
+		return nil, err
+	}
+	f.cache[key] = processor
+	return processor, nil
+}
+
+// Len returns the number of cached processors
+func (f *CachingFactory) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.cache)
+}
+
+// cacheKey hashes the type and details into a stable key.
+// Details are sorted so map iteration order doesn't matter, and
+// hashing keeps raw card numbers out of the map keys.
+func cacheKey(paymentType PaymentType, details map[string]string) string {
+	keys := make([]string, 0, len(details))
+	for k := range details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	h.Write([]byte(paymentType))
+	for _, k := range keys {
+		h.Write([]byte{0})
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(details[k]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,98 @@
+package factory
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingFactory returns a CachingFactory whose creations are counted
+func countingFactory(calls *atomic.Int32) *CachingFactory {
+	f := NewCachingFactory()
+	f.create = func(paymentType PaymentType, details map[string]string) (PaymentProcessor, error) {
+		calls.Add(1)
+		return CreatePaymentProcessor(paymentType, details)
+	}
+	return f
+}
+
+// Run with -race: every goroutine asks for the same processor at once
+func TestCachingFactoryConcurrentCreate(t *testing.T) {
+	var calls atomic.Int32
+	f := countingFactory(&calls)
+	details := map[string]string{"email": "user@example.com"}
+
+	const goroutines = 64
+	results := make([]PaymentProcessor, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p, err := f.Create(PayPal, details)
+			if err != nil {
+				t.Errorf("Create: %v", err)
+			}
+			results[i] = p
+		}()
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("processor constructed %d times, want 1", got)
+	}
+	for i, p := range results {
+		if p != results[0] {
+			t.Fatalf("goroutine %d got a different instance", i)
+		}
+	}
+}
+
+func TestCachingFactoryKeys(t *testing.T) {
+	var calls atomic.Int32
+	f := countingFactory(&calls)
+
+	a, _ := f.Create(PayPal, map[string]string{"email": "a@example.com"})
+	b, _ := f.Create(PayPal, map[string]string{"email": "b@example.com"})
+	again, _ := f.Create(PayPal, map[string]string{"email": "a@example.com"})
+	if a == b {
+		t.Error("different details shared an instance")
+	}
+	if a != again {
+		t.Error("identical details built a new instance")
+	}
+	if f.Len() != 2 || calls.Load() != 2 {
+		t.Errorf("Len() = %d after %d creations, want 2 and 2", f.Len(), calls.Load())
+	}
+}
+
+func TestCachingFactoryDoesNotCacheFailures(t *testing.T) {
+	var calls atomic.Int32
+	f := countingFactory(&calls)
+	details := map[string]string{"email": "bogus"}
+
+	for range 2 {
+		var verr *ValidationError
+		if _, err := f.Create(PayPal, details); !errors.As(err, &verr) {
+			t.Fatalf("Create = %v, want a ValidationError", err)
+		}
+	}
+	if f.Len() != 0 || calls.Load() != 2 {
+		t.Errorf("Len() = %d after %d creations, want 0 and 2", f.Len(), calls.Load())
+	}
+}
+
+func TestCacheKeyIgnoresMapOrderButNotBoundaries(t *testing.T) {
+	a := cacheKey(PayPal, map[string]string{"x": "1", "y": "2"})
+	b := cacheKey(PayPal, map[string]string{"y": "2", "x": "1"})
+	if a != b {
+		t.Error("cacheKey depends on map order")
+	}
+	if cacheKey(PayPal, map[string]string{"ab": "c"}) == cacheKey(PayPal, map[string]string{"a": "bc"}) {
+		t.Error("cacheKey doesn't separate keys from values")
+	}
+	if cacheKey(PayPal, nil) == cacheKey(CreditCard, nil) {
+		t.Error("cacheKey ignores the payment type")
+	}
+}
@@ -0,0 +1,146 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ProcessorCapabilities describes what a processor supports, so callers can
+// pick one by feature instead of by name
+type ProcessorCapabilities struct {
+	Refunds        bool     // captured payments can be refunded
+	PartialRefunds bool     // refunds may be for less than the captured amount
+	Recurring      bool     // the payment method can be charged again without the customer
+	Currencies     []string // ISO codes the processor can charge in
+}
+
+// SupportsCurrency reports whether currency is one of the supported currencies
+func (c ProcessorCapabilities) SupportsCurrency(currency string) bool {
+	return slices.Contains(c.Currencies, strings.ToUpper(currency))
+}
+
+// CapabilityReporter is implemented by processors that describe their capabilities
+type CapabilityReporter interface {
+	Capabilities() ProcessorCapabilities
+}
+
+// capabilitiesOf returns p's capabilities, or the conservative defaults for
+// processors that don't report any: no refunds, no recurring, DefaultCurrency only
+func capabilitiesOf(p PaymentProcessor) ProcessorCapabilities {
+	if r, ok := p.(CapabilityReporter); ok {
+		return r.Capabilities()
+	}
+	return ProcessorCapabilities{Currencies: []string{DefaultCurrency}}
+}
+
+func (c *CreditCardProcessor) Capabilities() ProcessorCapabilities {
+	return ProcessorCapabilities{Refunds: true, PartialRefunds: true, Recurring: true, Currencies: []string{"USD", "EUR", "GBP", "JPY"}}
+}
+
+func (p *PayPalProcessor) Capabilities() ProcessorCapabilities {
+	return ProcessorCapabilities{Refunds: true, PartialRefunds: true, Recurring: true, Currencies: []string{"USD", "EUR", "GBP"}}
+}
+
+// Bank transfers are pushed by the payer, so there's nothing to refund
+// against; money has to be sent back as a new transfer
+func (b *BankTransferProcessor) Capabilities() ProcessorCapabilities {
+	return ProcessorCapabilities{Recurring: true, Currencies: []string{"USD"}}
+}
+
+func (s *SandboxProcessor) Capabilities() ProcessorCapabilities {
+	return ProcessorCapabilities{Refunds: true, PartialRefunds: true, Recurring: true, Currencies: []string{"USD", "EUR", "GBP", "JPY", "BHD"}}
+}
+
+// CapabilityMatrix returns the capabilities of every payment type the
+// factory can create, built-in and registered, for rendering a comparison
+// table. Built-in types are inspected without going through validation, so
+// no card numbers or accounts are needed. Registered types are created with
+// placeholder values for their required fields; if that fails, the type is
+// listed with the default capabilities.
+func CapabilityMatrix() map[PaymentType]ProcessorCapabilities {
+	matrix := map[PaymentType]ProcessorCapabilities{
+		CreditCard:   (&CreditCardProcessor{}).Capabilities(),
+		PayPal:       (&PayPalProcessor{}).Capabilities(),
+		BankTransfer: (&BankTransferProcessor{}).Capabilities(),
+	}
+
+	registryMu.RLock()
+	registered := maps.Clone(registry)
+	registryMu.RUnlock()
+
+	// Registrations override built-ins, here as in CreatePaymentProcessor
+	for t, reg := range registered {
+		processor, err := reg.create(placeholderDetails(reg.fields))
+		if err != nil {
+			matrix[t] = capabilitiesOf(nil)
+			continue
+		}
+		matrix[t] = capabilitiesOf(processor)
+	}
+	return matrix
+}
+
+// placeholderDetails fills in every required field with a dummy value of the right kind
+func placeholderDetails(fields []FormField) map[string]string {
+	details := make(map[string]string)
+	for _, f := range fields {
+		if !f.Required {
+			continue
+		}
+		switch f.Type {
+		case FieldEmail:
+			details[f.Name] = "placeholder@example.com"
+		case FieldNumber:
+			details[f.Name] = "0"
+		default:
+			details[f.Name] = "placeholder"
+		}
+	}
+	return details
+}
+
+// ErrNoCapableProcessor is matched (via errors.Is) by SelectProcessor's error
+// when no candidate has every required capability
+var ErrNoCapableProcessor = errors.New("no processor has the required capabilities")
+
+// SelectProcessor returns the first candidate whose capabilities cover
+// required: every capability flag set in required, and every currency it
+// lists. Candidate order is the caller's preference order. If none match,
+// the error says what each candidate was missing.
+func SelectProcessor(required ProcessorCapabilities, candidates []PaymentProcessor) (PaymentProcessor, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: no candidates given", ErrNoCapableProcessor)
+	}
+	var reasons []string
+	for _, candidate := range candidates {
+		unmet := capabilitiesOf(candidate).unmet(required)
+		if len(unmet) == 0 {
+			return candidate, nil
+		}
+		reasons = append(reasons, candidate.GetName()+" lacks "+strings.Join(unmet, ", "))
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoCapableProcessor, strings.Join(reasons, "; "))
+}
+
+// unmet lists the parts of required that c doesn't provide
+func (c ProcessorCapabilities) unmet(required ProcessorCapabilities) []string {
+	var unmet []string
+	if required.Refunds && !c.Refunds {
+		unmet = append(unmet, "refunds")
+	}
+	if required.PartialRefunds && !c.PartialRefunds {
+		unmet = append(unmet, "partial refunds")
+	}
+	if required.Recurring && !c.Recurring {
+		unmet = append(unmet, "recurring")
+	}
+	for _, currency := range required.Currencies {
+		if !c.SupportsCurrency(currency) {
+			unmet = append(unmet, "currency "+strings.ToUpper(currency))
+		}
+	}
+	return unmet
+}
@@ -0,0 +1,126 @@
+package factory
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// capableProcessor reports whatever capabilities it's given
+type capableProcessor struct {
+	recordingProcessor
+	caps ProcessorCapabilities
+}
+
+func (c *capableProcessor) Capabilities() ProcessorCapabilities { return c.caps }
+
+func TestCapabilityMatrixBuiltins(t *testing.T) {
+	matrix := CapabilityMatrix()
+	tests := []struct {
+		pt           PaymentType
+		refunds      bool
+		currency     string
+		wantCurrency bool
+	}{
+		{CreditCard, true, "JPY", true},
+		{PayPal, true, "JPY", false},
+		{BankTransfer, false, "EUR", false},
+		{Sandbox, true, "BHD", true},
+	}
+	for _, tt := range tests {
+		caps, ok := matrix[tt.pt]
+		if !ok {
+			t.Errorf("matrix is missing %s", tt.pt)
+			continue
+		}
+		if caps.Refunds != tt.refunds {
+			t.Errorf("%s: Refunds = %v, want %v", tt.pt, caps.Refunds, tt.refunds)
+		}
+		if got := caps.SupportsCurrency(tt.currency); got != tt.wantCurrency {
+			t.Errorf("%s: SupportsCurrency(%s) = %v, want %v", tt.pt, tt.currency, got, tt.wantCurrency)
+		}
+	}
+}
+
+func TestCapabilityMatrixRegistered(t *testing.T) {
+	var gotDetails map[string]string
+	register(t, "matrix-wallet", func(details map[string]string) (PaymentProcessor, error) {
+		gotDetails = details
+		if ValidateEmail(details["email"]) != nil {
+			return nil, errors.New("bad email")
+		}
+		return &capableProcessor{caps: ProcessorCapabilities{Refunds: true, Currencies: []string{"CHF"}}}, nil
+	}, []FormField{
+		{Name: "email", Type: FieldEmail, Required: true},
+		{Name: "nickname", Type: FieldText},
+	})
+	register(t, "matrix-broken", func(map[string]string) (PaymentProcessor, error) {
+		return nil, errors.New("needs real credentials")
+	}, nil)
+
+	matrix := CapabilityMatrix()
+	if caps := matrix["matrix-wallet"]; !caps.Refunds || !caps.SupportsCurrency("chf") {
+		t.Errorf("matrix-wallet = %+v, want its own capabilities", caps)
+	}
+	if _, ok := gotDetails["nickname"]; ok {
+		t.Error("optional fields were given placeholders")
+	}
+	if caps := matrix["matrix-broken"]; caps.Refunds || !slices.Equal(caps.Currencies, []string{DefaultCurrency}) {
+		t.Errorf("matrix-broken = %+v, want the defaults", caps)
+	}
+}
+
+func TestSelectProcessor(t *testing.T) {
+	card := &capableProcessor{recordingProcessor{name: "Card"}, ProcessorCapabilities{Refunds: true, Currencies: []string{"USD", "EUR"}}}
+	bank := &capableProcessor{recordingProcessor{name: "Bank"}, ProcessorCapabilities{Currencies: []string{"USD"}}}
+
+	if p, err := SelectProcessor(ProcessorCapabilities{Currencies: []string{"usd"}}, []PaymentProcessor{bank, card}); err != nil || p != bank {
+		t.Errorf("SelectProcessor = %v, %v, want the first match", p, err)
+	}
+	if p, err := SelectProcessor(ProcessorCapabilities{Refunds: true, Currencies: []string{"EUR"}}, []PaymentProcessor{bank, card}); err != nil || p != card {
+		t.Errorf("SelectProcessor = %v, %v, want Card", p, err)
+	}
+	_, err := SelectProcessor(ProcessorCapabilities{Recurring: true}, []PaymentProcessor{bank, card})
+	if !errors.Is(err, ErrNoCapableProcessor) {
+		t.Errorf("SelectProcessor = %v, want ErrNoCapableProcessor", err)
+	}
+}
+
+func TestSelectProcessorFirstMatchWins(t *testing.T) {
+	a := &capableProcessor{recordingProcessor{name: "A"}, ProcessorCapabilities{Refunds: true, Recurring: true, Currencies: []string{"USD"}}}
+	b := &capableProcessor{recordingProcessor{name: "B"}, ProcessorCapabilities{Refunds: true, Recurring: true, Currencies: []string{"USD"}}}
+	required := ProcessorCapabilities{Refunds: true, Recurring: true}
+
+	for _, order := range [][]PaymentProcessor{{a, b}, {b, a}} {
+		if p, err := SelectProcessor(required, order); err != nil || p != order[0] {
+			t.Errorf("SelectProcessor(%s, %s) = %v, %v, want %s", order[0].GetName(), order[1].GetName(), p, err, order[0].GetName())
+		}
+	}
+}
+
+func TestSelectProcessorNoMatchExplains(t *testing.T) {
+	card := &capableProcessor{recordingProcessor{name: "Card"}, ProcessorCapabilities{Refunds: true, Currencies: []string{"USD"}}}
+	bank := &capableProcessor{recordingProcessor{name: "Bank"}, ProcessorCapabilities{Currencies: []string{"EUR"}}}
+	required := ProcessorCapabilities{Refunds: true, PartialRefunds: true, Currencies: []string{"eur"}}
+
+	p, err := SelectProcessor(required, []PaymentProcessor{card, bank})
+	if p != nil || !errors.Is(err, ErrNoCapableProcessor) {
+		t.Fatalf("SelectProcessor = %v, %v, want ErrNoCapableProcessor", p, err)
+	}
+	want := "Card lacks partial refunds, currency EUR; Bank lacks refunds, partial refunds"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err, want)
+	}
+
+	if _, err := SelectProcessor(required, nil); !errors.Is(err, ErrNoCapableProcessor) || !strings.Contains(err.Error(), "no candidates") {
+		t.Errorf("SelectProcessor with no candidates = %v", err)
+	}
+}
+
+func TestSelectProcessorNothingRequired(t *testing.T) {
+	plain := &recordingProcessor{name: "Plain"}
+	if p, err := SelectProcessor(ProcessorCapabilities{}, []PaymentProcessor{plain}); err != nil || p != plain {
+		t.Errorf("SelectProcessor with no requirements = %v, %v, want the first candidate", p, err)
+	}
+}
@@ -0,0 +1,47 @@
+package factory
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the package what time it is.
+// Swap it with SetClock to make expiry checks and daily limits deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the default Clock backed by time.Now
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// installedClock boxes the Clock set by SetClock so it fits in an
+// atomic.Pointer
+type installedClock struct{ Clock }
+
+// currentClock is nil until SetClock is first called, which means the real clock
+var currentClock atomic.Pointer[installedClock]
+
+// packageClock forwards to whatever SetClock installed last. Reading the
+// clock through an atomic lets SetClock run while payments are processed.
+type packageClock struct{}
+
+func (packageClock) Now() time.Time {
+	if c := currentClock.Load(); c != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+var clock Clock = packageClock{}
+
+// SetClock replaces the clock used by the factory.
+// Passing nil restores the real clock. It is safe to call concurrently with
+// processing.
+func SetClock(c Clock) {
+	if c == nil {
+		c = realClock{}
+	}
+	currentClock.Store(&installedClock{c})
+}
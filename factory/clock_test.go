@@ -0,0 +1,48 @@
+package factory
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetClockNilRestoresRealClock(t *testing.T) {
+	fixed := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	SetClock(newFakeClock(fixed))
+	t.Cleanup(func() { SetClock(nil) })
+	if got := clock.Now(); !got.Equal(fixed) {
+		t.Fatalf("clock.Now() = %v, want the fake %v", got, fixed)
+	}
+
+	SetClock(nil)
+	if got := clock.Now(); time.Since(got) > time.Minute {
+		t.Errorf("clock.Now() = %v after SetClock(nil), want the real time", got)
+	}
+}
+
+// Run with -race: swapping the clock must not race with card validation
+func TestSetClockConcurrentWithProcessing(t *testing.T) {
+	t.Cleanup(func() { SetClock(nil) })
+	details := map[string]string{"cardNumber": "4111111111111111", "cvv": "123", "expMonth": "12", "expYear": "2099"}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				SetClock(newFakeClock(time.Now()))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if _, err := CreatePaymentProcessor(CreditCard, details); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,43 @@
+package factory
+
+import "context"
+
+// ContextProcessor is implemented by processors that take a context, so
+// deadlines, cancellation and request IDs flow through a chain of decorators.
+type ContextProcessor interface {
+	PaymentProcessor
+	ProcessCtx(ctx context.Context, amount float64) (*Receipt, error)
+}
+
+// ProcessCtx charges amount through p with ctx.
+// Context-aware processors get the context; for the rest, ProcessCtx checks
+// the context up front, charges, and stamps the request ID on the receipt.
+func ProcessCtx(ctx context.Context, p PaymentProcessor, amount float64) (*Receipt, error) {
+	if cp, ok := p.(ContextProcessor); ok {
+		return cp.ProcessCtx(ctx, amount)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	receipt, err := Charge(p, amount)
+	if err != nil {
+		return nil, err
+	}
+	if id, ok := RequestIDFromContext(ctx); ok && receipt.RequestID == "" {
+		receipt.RequestID = id
+	}
+	return receipt, nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying a request ID for tracing
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,65 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestIDFlowsThroughChain(t *testing.T) {
+	log := &bufferLogger{}
+	p := Chain(&recordingProcessor{name: "Stub"},
+		Logging(log),
+		Retry(3, time.Millisecond),
+		Timeout(time.Second),
+	)
+	ctx := WithRequestID(context.Background(), "req-42")
+
+	receipt, err := ProcessCtx(ctx, p, 12.5)
+	if err != nil {
+		t.Fatalf("ProcessCtx: %v", err)
+	}
+	if receipt.RequestID != "req-42" {
+		t.Errorf("receipt.RequestID = %q, want req-42", receipt.RequestID)
+	}
+	out := log.String()
+	for _, want := range []string{"[req-42] charging $12.50 via Stub", "[req-42] charge via Stub succeeded: " + receipt.TransactionID} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestProcessCtxPlainProcessor(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-7")
+	receipt, err := ProcessCtx(ctx, &recordingProcessor{}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.RequestID != "req-7" {
+		t.Errorf("receipt.RequestID = %q, want req-7", receipt.RequestID)
+	}
+}
+
+func TestProcessCtxCancelledBeforeCharging(t *testing.T) {
+	inner := &recordingProcessor{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ProcessCtx(ctx, inner, 5); !errors.Is(err, context.Canceled) {
+		t.Errorf("ProcessCtx = %v, want context.Canceled", err)
+	}
+	if len(inner.charged()) != 0 {
+		t.Error("a cancelled context was still charged")
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if _, ok := RequestIDFromContext(context.Background()); ok {
+		t.Error("empty context has a request ID")
+	}
+	if _, ok := RequestIDFromContext(WithRequestID(context.Background(), "")); ok {
+		t.Error("an empty request ID counts as present")
+	}
+}
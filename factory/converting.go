@@ -0,0 +1,109 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RateProvider looks up exchange rates between two currencies.
+// It is an interface so callers can plug in a live rates service,
+// while tests and demos use a fixed table.
+type RateProvider interface {
+	// Rate returns how many units of `to` one unit of `from` is worth.
+	Rate(from, to string) (float64, error)
+}
+
+// FixedRates is a RateProvider backed by a static table.
+// Keys are currency pairs in the form "FROM/TO", e.g. "EUR/USD".
+type FixedRates map[string]float64
+
+// Rate returns the rate for the from/to pair, or an error if the pair is unknown
+func (r FixedRates) Rate(from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, nil
+	}
+	rate, ok := r[from+"/"+to]
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %s/%s", from, to)
+	}
+	return rate, nil
+}
+
+// ConvertingProcessor wraps another PaymentProcessor and converts amounts
+// into the inner processor's settlement currency before delegating.
+// This is a decorator: it implements PaymentProcessor itself, so callers
+// can use it anywhere a regular processor is expected.
+type ConvertingProcessor struct {
+	inner  PaymentProcessor
+	rates  RateProvider
+	target string
+}
+
+// NewConvertingProcessor wraps inner so that charges in foreign currencies
+// are converted to the target (settlement) currency first.
+func NewConvertingProcessor(inner PaymentProcessor, rates RateProvider, target string) *ConvertingProcessor {
+	return &ConvertingProcessor{
+		inner:  inner,
+		rates:  rates,
+		target: strings.ToUpper(target),
+	}
+}
+
+// Process charges an amount given in DefaultCurrency, like every other
+// processor's Process, converting it to the settlement currency first
+func (c *ConvertingProcessor) Process(amount float64) error {
+	return c.ProcessIn(amount, DefaultCurrency)
+}
+
+// ProcessCtx is Process with a context, returning the inner processor's receipt
+func (c *ConvertingProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	converted, err := c.Convert(amount, DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+	return ProcessCtx(ctx, c.inner, converted)
+}
+
+// ProcessIn converts amount from the given currency to the settlement
+// currency and then charges it through the inner processor.
+func (c *ConvertingProcessor) ProcessIn(amount float64, currency string) error {
+	converted, err := c.Convert(amount, currency)
+	if err != nil {
+		return err
+	}
+	return c.inner.Process(converted)
+}
+
+// Convert returns amount expressed in the settlement currency, rounded to
+// its smallest unit (cents for USD), since that is all the inner processor
+// can charge
+func (c *ConvertingProcessor) Convert(amount float64, currency string) (float64, error) {
+	rate, err := c.rates.Rate(currency, c.target)
+	if err != nil {
+		return 0, err
+	}
+	return fromMinor(toMinor(amount*rate, c.target), c.target), nil
+}
+
+// SettlementCurrency returns the currency the inner processor is charged in
+func (c *ConvertingProcessor) SettlementCurrency() string {
+	return c.target
+}
+
+func (c *ConvertingProcessor) GetName() string {
+	return c.inner.GetName() + " (" + c.target + ")"
+}
+
+func (c *ConvertingProcessor) Details() map[string]string {
+	return detailsOf(c.inner)
+}
+
+func (c *ConvertingProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(c.inner)
+}
+
+func (c *ConvertingProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, c.inner)
+}
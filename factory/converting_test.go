@@ -0,0 +1,92 @@
+package factory
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestConvertingProcessorConvertsBeforeCharging(t *testing.T) {
+	inner := &recordingProcessor{}
+	p := NewConvertingProcessor(inner, FixedRates{"EUR/USD": 1.10}, "usd")
+
+	if err := p.ProcessIn(100, "eur"); err != nil {
+		t.Fatalf("ProcessIn: %v", err)
+	}
+	if got, want := inner.charged(), []float64{110}; !slices.Equal(got, want) {
+		t.Errorf("inner charged %v, want %v", got, want)
+	}
+	if got := p.SettlementCurrency(); got != "USD" {
+		t.Errorf("SettlementCurrency() = %q, want USD", got)
+	}
+}
+
+func TestConvertingProcessorProcessConvertsDefaultCurrency(t *testing.T) {
+	inner := &recordingProcessor{}
+	p := NewConvertingProcessor(inner, FixedRates{"USD/EUR": 0.9}, "EUR")
+
+	if err := p.Process(20); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if got, want := inner.charged(), []float64{18}; !slices.Equal(got, want) {
+		t.Errorf("inner charged %v, want %v in EUR", got, want)
+	}
+
+	// Without a rate from DefaultCurrency, Process can't charge either
+	missing := NewConvertingProcessor(inner, FixedRates{"EUR/USD": 1.10}, "GBP")
+	if err := missing.Process(20); err == nil {
+		t.Error("Process with no USD/GBP rate succeeded")
+	}
+}
+
+func TestConvertingProcessorRoundsToMinorUnit(t *testing.T) {
+	tests := []struct {
+		name     string
+		rates    FixedRates
+		target   string
+		amount   float64
+		currency string
+		want     float64
+	}{
+		{"cents", FixedRates{"EUR/USD": 1.0873}, "USD", 19.99, "EUR", 21.74},
+		{"same currency", FixedRates{}, "USD", 12.34, "usd", 12.34},
+		{"zero decimals", FixedRates{"USD/JPY": 149.37}, "JPY", 10.05, "USD", 1501},
+		{"three decimals", FixedRates{"USD/BHD": 0.376}, "BHD", 10.01, "USD", 3.764},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewConvertingProcessor(&recordingProcessor{}, tt.rates, tt.target)
+			got, err := p.Convert(tt.amount, tt.currency)
+			if err != nil {
+				t.Fatalf("Convert: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%v, %s) = %v, want %v", tt.amount, tt.currency, got, tt.want)
+			}
+			if err := ValidatePrecision(got, tt.target); err != nil {
+				t.Errorf("converted amount fails the precision check: %v", err)
+			}
+		})
+	}
+}
+
+func TestConvertingProcessorMissingRate(t *testing.T) {
+	inner := &recordingProcessor{}
+	p := NewConvertingProcessor(inner, FixedRates{"EUR/USD": 1.10}, "USD")
+
+	if err := p.ProcessIn(100, "GBP"); err == nil {
+		t.Fatal("ProcessIn with an unknown pair succeeded")
+	}
+	if got := inner.charged(); len(got) != 0 {
+		t.Errorf("inner was charged %v despite the missing rate", got)
+	}
+}
+
+func TestConvertingProcessorPassesThroughErrors(t *testing.T) {
+	errDeclined := errors.New("declined")
+	p := NewConvertingProcessor(&recordingProcessor{err: errDeclined}, FixedRates{"EUR/USD": 2}, "USD")
+
+	if err := p.ProcessIn(5, "EUR"); !errors.Is(err, errDeclined) {
+		t.Errorf("ProcessIn error = %v, want %v", err, errDeclined)
+	}
+}
@@ -0,0 +1,110 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDailyLimitExceeded is returned when a charge would push the day's total over the limit
+var ErrDailyLimitExceeded = errors.New("daily spend limit exceeded")
+
+// DailyLimitProcessor caps the total charged per calendar day.
+// The day rolls over at midnight in the clock's time zone.
+type DailyLimitProcessor struct {
+	inner PaymentProcessor
+	limit float64
+	clock Clock
+
+	mu    sync.Mutex
+	day   time.Time // midnight of the day being tracked
+	spent float64
+}
+
+// NewDailyLimitProcessor wraps inner with a daily spend limit.
+// A nil clock means the real one.
+func NewDailyLimitProcessor(inner PaymentProcessor, limit float64, clock Clock) *DailyLimitProcessor {
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &DailyLimitProcessor{inner: inner, limit: limit, clock: clock}
+}
+
+// Process reserves the amount against today's budget before charging, so
+// concurrent charges can't jointly overshoot the limit. If the charge fails
+// the reservation is released.
+func (d *DailyLimitProcessor) Process(amount float64) error {
+	_, err := d.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+// ProcessCtx is Process with a context, returning the inner processor's receipt
+func (d *DailyLimitProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	day, err := d.reserve(amount)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := ProcessCtx(ctx, d.inner, amount)
+	if err != nil {
+		d.release(day, amount)
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// Spent returns how much has been charged so far today
+func (d *DailyLimitProcessor) Spent() float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.rollOverLocked()
+	return d.spent
+}
+
+func (d *DailyLimitProcessor) reserve(amount float64) (time.Time, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.rollOverLocked()
+	if d.spent+amount > d.limit {
+		return time.Time{}, ErrDailyLimitExceeded
+	}
+	d.spent += amount
+	return d.day, nil
+}
+
+// release gives back a reservation, unless the day has rolled over since
+func (d *DailyLimitProcessor) release(day time.Time, amount float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.day.Equal(day) {
+		d.spent -= amount
+	}
+}
+
+// rollOverLocked resets the running total when a new day has started.
+// The caller must hold d.mu.
+func (d *DailyLimitProcessor) rollOverLocked() {
+	now := d.clock.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if !today.Equal(d.day) {
+		d.day = today
+		d.spent = 0
+	}
+}
+
+func (d *DailyLimitProcessor) GetName() string {
+	return d.inner.GetName()
+}
+
+func (d *DailyLimitProcessor) Details() map[string]string {
+	return detailsOf(d.inner)
+}
+
+func (d *DailyLimitProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(d.inner)
+}
+
+func (d *DailyLimitProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, d.inner)
+}
@@ -0,0 +1,80 @@
+package factory
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDailyLimitProcessor(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC))
+	inner := &recordingProcessor{}
+	p := NewDailyLimitProcessor(inner, 100, clock)
+
+	for _, amount := range []float64{40, 60} {
+		if err := p.Process(amount); err != nil {
+			t.Fatalf("Process(%v) within the limit: %v", amount, err)
+		}
+	}
+	if err := p.Process(0.01); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("Process over the limit = %v, want ErrDailyLimitExceeded", err)
+	}
+	if got := p.Spent(); got != 100 {
+		t.Errorf("Spent() = %v, want 100", got)
+	}
+
+	// Still the same day just before midnight
+	clock.Advance(14*time.Hour + 59*time.Minute)
+	if err := p.Process(1); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Errorf("Process at 23:59 = %v, want ErrDailyLimitExceeded", err)
+	}
+
+	clock.Advance(time.Minute)
+	if got := p.Spent(); got != 0 {
+		t.Errorf("Spent() after midnight = %v, want 0", got)
+	}
+	if err := p.Process(100); err != nil {
+		t.Errorf("Process after midnight: %v", err)
+	}
+	if got := len(inner.charged()); got != 3 {
+		t.Errorf("inner charged %d times, want 3", got)
+	}
+}
+
+func TestDailyLimitReleasesFailedCharges(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC))
+	errDeclined := errors.New("declined")
+	p := NewDailyLimitProcessor(&recordingProcessor{err: errDeclined}, 100, clock)
+
+	if err := p.Process(80); !errors.Is(err, errDeclined) {
+		t.Fatalf("Process = %v, want %v", err, errDeclined)
+	}
+	if got := p.Spent(); got != 0 {
+		t.Errorf("Spent() after a failed charge = %v, want 0", got)
+	}
+}
+
+// Run with -race: concurrent charges must never jointly overshoot the limit
+func TestDailyLimitConcurrent(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC))
+	inner := &recordingProcessor{}
+	p := NewDailyLimitProcessor(inner, 50, clock)
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Process(1)
+		}()
+	}
+	wg.Wait()
+
+	if got := len(inner.charged()); got != 50 {
+		t.Errorf("inner charged %d times, want exactly 50", got)
+	}
+	if got := p.Spent(); got != 50 {
+		t.Errorf("Spent() = %v, want 50", got)
+	}
+}
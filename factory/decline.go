@@ -0,0 +1,63 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DeclineFallbackProcessor tries payment methods in order, moving to the next
+// one only when a method declines. A decline (ErrDeclined) is the customer's
+// payment method saying no, so another method may well succeed; any other
+// error means something is broken, and retrying elsewhere could charge the
+// customer twice, so it aborts.
+//
+// The receipt records the method that succeeded under "payment_method" and
+// the ones that declined before it under "declined_by".
+type DeclineFallbackProcessor struct {
+	methods []PaymentProcessor
+}
+
+// NewDeclineFallbackProcessor tries primary first, then each fallback in order
+func NewDeclineFallbackProcessor(primary PaymentProcessor, fallbacks ...PaymentProcessor) *DeclineFallbackProcessor {
+	return &DeclineFallbackProcessor{methods: append([]PaymentProcessor{primary}, fallbacks...)}
+}
+
+func (d *DeclineFallbackProcessor) Process(amount float64) error {
+	_, err := d.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+func (d *DeclineFallbackProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	var declinedBy []string
+	var lastDecline error
+	for _, method := range d.methods {
+		receipt, err := ProcessCtx(ctx, method, amount)
+		switch {
+		case err == nil:
+			if receipt.Metadata == nil {
+				receipt.Metadata = make(map[string]string)
+			}
+			receipt.Metadata["payment_method"] = method.GetName()
+			if len(declinedBy) > 0 {
+				receipt.Metadata["declined_by"] = strings.Join(declinedBy, ",")
+			}
+			return receipt, nil
+		case errors.Is(err, ErrDeclined):
+			declinedBy = append(declinedBy, method.GetName())
+			lastDecline = err
+		default:
+			return nil, fmt.Errorf("%s: %w", method.GetName(), err)
+		}
+	}
+	return nil, fmt.Errorf("every payment method declined (%s): %w", strings.Join(declinedBy, ", "), lastDecline)
+}
+
+func (d *DeclineFallbackProcessor) GetName() string {
+	return d.methods[0].GetName()
+}
+
+func (d *DeclineFallbackProcessor) Details() map[string]string {
+	return detailsOf(d.methods[0])
+}
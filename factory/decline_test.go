@@ -0,0 +1,64 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDeclineFallbackSucceeds(t *testing.T) {
+	card := &recordingProcessor{name: "Card", err: fmt.Errorf("issuer: %w", ErrDeclined)}
+	wallet := &recordingProcessor{name: "Wallet", err: ErrDeclined}
+	bank := &recordingProcessor{name: "Bank"}
+
+	receipt, err := NewDeclineFallbackProcessor(card, wallet, bank).ProcessCtx(t.Context(), 30)
+	if err != nil {
+		t.Fatalf("ProcessCtx: %v", err)
+	}
+	if receipt.Metadata["payment_method"] != "Bank" || receipt.Metadata["declined_by"] != "Card,Wallet" {
+		t.Errorf("metadata = %v, want Bank after Card and Wallet declined", receipt.Metadata)
+	}
+	for _, p := range []*recordingProcessor{card, wallet, bank} {
+		if len(p.charged()) != 1 {
+			t.Errorf("%s was tried %d times, want once", p.name, len(p.charged()))
+		}
+	}
+}
+
+func TestDeclineFallbackHardErrorAborts(t *testing.T) {
+	outage := errors.New("gateway timeout")
+	card := &recordingProcessor{name: "Card", err: outage}
+	bank := &recordingProcessor{name: "Bank"}
+
+	err := NewDeclineFallbackProcessor(card, bank).Process(30)
+	if !errors.Is(err, outage) || errors.Is(err, ErrDeclined) {
+		t.Errorf("Process = %v, want the hard error", err)
+	}
+	if len(bank.charged()) != 0 {
+		t.Error("a hard error fell back to another method, risking a double charge")
+	}
+}
+
+func TestDeclineFallbackAllDecline(t *testing.T) {
+	p := NewDeclineFallbackProcessor(
+		&recordingProcessor{name: "Card", err: ErrDeclined},
+		&recordingProcessor{name: "Wallet", err: ErrDeclined},
+	)
+	err := p.Process(30)
+	if !errors.Is(err, ErrDeclined) {
+		t.Fatalf("Process = %v, want ErrDeclined", err)
+	}
+	if want := "every payment method declined (Card, Wallet): payment declined"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestDeclineFallbackPrimaryOnly(t *testing.T) {
+	receipt, err := NewDeclineFallbackProcessor(&recordingProcessor{name: "Card"}).ProcessCtx(t.Context(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := receipt.Metadata["declined_by"]; ok || receipt.Metadata["payment_method"] != "Card" {
+		t.Errorf("metadata = %v", receipt.Metadata)
+	}
+}
@@ -7,7 +7,7 @@ import (
 )
 
 func main() {
-	fmt.Println("=== Factory Pattern Demo ===\n")
+	fmt.Print("=== Factory Pattern Demo ===\n\n")
 
 	// Demonstrate creating different payment processors using the factory
 	fmt.Println("1. Creating payment processors via factory:")
@@ -16,8 +16,10 @@ func main() {
 	ccProcessor, err := factory.CreatePaymentProcessor(
 		factory.CreditCard,
 		map[string]string{
-			"cardNumber": "1234567890123456",
+			"cardNumber": "4242424242424242",
 			"cvv":        "123",
+			"expMonth":   "12",
+			"expYear":    "2099",
 		},
 	)
 	if err != nil {
@@ -44,7 +46,7 @@ func main() {
 		factory.BankTransfer,
 		map[string]string{
 			"accountNumber": "987654321",
-			"routingNumber": "123456789",
+			"routingNumber": "021000021",
 		},
 	)
 	if err != nil {
@@ -0,0 +1,27 @@
+package factory
+
+// Detailer is implemented by processors that can describe the account they charge.
+// Details never include secrets such as the full card number or CVV.
+type Detailer interface {
+	Details() map[string]string
+}
+
+// detailsOf returns p's details, or nil if p doesn't expose any
+func detailsOf(p PaymentProcessor) map[string]string {
+	if d, ok := p.(Detailer); ok {
+		return d.Details()
+	}
+	return nil
+}
+
+func (c *CreditCardProcessor) Details() map[string]string {
+	return map[string]string{"cardLast4": c.cardNumber[len(c.cardNumber)-4:]}
+}
+
+func (p *PayPalProcessor) Details() map[string]string {
+	return map[string]string{"email": p.email}
+}
+
+func (b *BankTransferProcessor) Details() map[string]string {
+	return map[string]string{"accountNumber": b.accountNumber, "routingNumber": b.routingNumber}
+}
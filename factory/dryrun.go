@@ -0,0 +1,61 @@
+package factory
+
+import "context"
+
+// DryRunProcessor stands in front of a processor and never charges it.
+// Each payment is logged and answered with a receipt marked "dry_run", so a
+// whole checkout flow can be exercised against a real configuration without
+// moving money. Nothing is recorded in the refund ledger.
+type DryRunProcessor struct {
+	inner  PaymentProcessor
+	logger Logger
+}
+
+// NewDryRunProcessor wraps inner, logging to l (or the package logger if l is nil)
+func NewDryRunProcessor(inner PaymentProcessor, l Logger) *DryRunProcessor {
+	return &DryRunProcessor{inner: inner, logger: l}
+}
+
+// DryRun returns a Middleware that wraps processors with NewDryRunProcessor
+func DryRun(l Logger) Middleware {
+	return func(p PaymentProcessor) PaymentProcessor { return NewDryRunProcessor(p, l) }
+}
+
+func (d *DryRunProcessor) Process(amount float64) error {
+	_, err := d.ProcessReceipt(amount)
+	return err
+}
+
+// ProcessReceipt logs the charge that would have been made and returns a
+// receipt for it without calling the inner processor
+func (d *DryRunProcessor) ProcessReceipt(amount float64) (*Receipt, error) {
+	out := d.logger
+	if out == nil {
+		out = logger
+	}
+	out.Printf("dry run: would charge %s via %s\n", FormatAmount(amount, DefaultCurrency), d.inner.GetName())
+	return &Receipt{
+		Processor: d.inner.GetName(),
+		Amount:    amount,
+		Currency:  DefaultCurrency,
+		Status:    StatusSucceeded,
+		Timestamp: clock.Now(),
+		Metadata:  map[string]string{"dry_run": "true"},
+	}, nil
+}
+
+func (d *DryRunProcessor) GetName() string {
+	return d.inner.GetName()
+}
+
+func (d *DryRunProcessor) Details() map[string]string {
+	return detailsOf(d.inner)
+}
+
+func (d *DryRunProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(d.inner)
+}
+
+func (d *DryRunProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, d.inner)
+}
@@ -1,6 +1,9 @@
 package factory
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // Step 1: Define the Product Interface
 // This is what all our products will have in common.
@@ -18,12 +21,24 @@ type PaymentProcessor interface {
 
 // CreditCardProcessor handles credit card payments
 type CreditCardProcessor struct {
-	cardNumber string
-	cvv        string
+	cardNumber string `secret:"true"`
+	cvv        string `secret:"true"`
+	expMonth   int
+	expYear    int
 }
 
 func (c *CreditCardProcessor) Process(amount float64) error {
-	fmt.Printf("Processing $%.2f via Credit Card ending in %s\n", amount, c.cardNumber[len(c.cardNumber)-4:])
+	_, err := c.ProcessReceipt(amount)
+	return err
+}
+
+// ProcessReceipt charges the card and registers the transaction for refunds
+func (c *CreditCardProcessor) ProcessReceipt(amount float64) (*Receipt, error) {
+	return processRequest(c, c.charge, &PaymentRequest{Amount: amount, Currency: DefaultCurrency})
+}
+
+func (c *CreditCardProcessor) charge(amount float64, currency string) error {
+	logger.Printf("Processing %s via Credit Card ending in %s\n", FormatAmount(amount, currency), c.cardNumber[len(c.cardNumber)-4:])
 	// Simulate processing logic
 	return nil
 }
@@ -38,7 +53,17 @@ type PayPalProcessor struct {
 }
 
 func (p *PayPalProcessor) Process(amount float64) error {
-	fmt.Printf("Processing $%.2f via PayPal for %s\n", amount, p.email)
+	_, err := p.ProcessReceipt(amount)
+	return err
+}
+
+// ProcessReceipt charges the PayPal account and registers the transaction for refunds
+func (p *PayPalProcessor) ProcessReceipt(amount float64) (*Receipt, error) {
+	return processRequest(p, p.charge, &PaymentRequest{Amount: amount, Currency: DefaultCurrency})
+}
+
+func (p *PayPalProcessor) charge(amount float64, currency string) error {
+	logger.Printf("Processing %s via PayPal for %s\n", FormatAmount(amount, currency), p.email)
 	// Simulate processing logic
 	return nil
 }
@@ -51,10 +76,60 @@ func (p *PayPalProcessor) GetName() string {
 type BankTransferProcessor struct {
 	accountNumber string
 	routingNumber string
+	balances      BalanceSource
+	checkBalance  bool
+}
+
+// NewBankTransferProcessor validates the account details and returns a processor.
+// When checkBalance is true, Process first asks balances whether the account
+// can cover the amount and fails with ErrInsufficientFunds if it can't.
+func NewBankTransferProcessor(accountNumber, routingNumber string, balances BalanceSource, checkBalance bool) (*BankTransferProcessor, error) {
+	if err := ValidateAccountNumber(accountNumber); err != nil {
+		return nil, err
+	}
+	if err := ValidateRoutingNumber(routingNumber); err != nil {
+		return nil, err
+	}
+	if checkBalance && balances == nil {
+		return nil, errors.New("balance check requested but no balance source given")
+	}
+	return &BankTransferProcessor{
+		accountNumber: accountNumber,
+		routingNumber: routingNumber,
+		balances:      balances,
+		checkBalance:  checkBalance,
+	}, nil
+}
+
+// Balance returns the available balance of the account
+func (b *BankTransferProcessor) Balance() (float64, error) {
+	if b.balances == nil {
+		return 0, errors.New("no balance source configured")
+	}
+	return b.balances(b.accountNumber)
 }
 
 func (b *BankTransferProcessor) Process(amount float64) error {
-	fmt.Printf("Processing $%.2f via Bank Transfer to account %s\n", amount, b.accountNumber)
+	_, err := b.ProcessReceipt(amount)
+	return err
+}
+
+// ProcessReceipt makes the transfer and registers the transaction for refunds
+func (b *BankTransferProcessor) ProcessReceipt(amount float64) (*Receipt, error) {
+	return processRequest(b, b.charge, &PaymentRequest{Amount: amount, Currency: DefaultCurrency})
+}
+
+func (b *BankTransferProcessor) charge(amount float64, currency string) error {
+	if b.checkBalance {
+		balance, err := b.Balance()
+		if err != nil {
+			return err
+		}
+		if balance < amount {
+			return ErrInsufficientFunds
+		}
+	}
+	logger.Printf("Processing %s via Bank Transfer to account %s\n", FormatAmount(amount, currency), b.accountNumber)
 	// Simulate processing logic
 	return nil
 }
@@ -76,29 +151,69 @@ const (
 	BankTransfer PaymentType = "bank"
 )
 
+// UnknownPaymentTypeError is returned when the factory doesn't know how to
+// build the requested type. Use errors.As to get at the offending type.
+type UnknownPaymentTypeError struct {
+	Type PaymentType
+}
+
+func (e *UnknownPaymentTypeError) Error() string {
+	return fmt.Sprintf("unknown payment type: %s", e.Type)
+}
+
 // CreatePaymentProcessor is our factory function.
 // It takes a payment type and returns the appropriate processor.
 // Notice how all the "if type == X" logic is here, not scattered everywhere!
+// Interceptors added with AddCreateInterceptor and AddPostCreateInterceptor
+// run around every creation.
 func CreatePaymentProcessor(paymentType PaymentType, details map[string]string) (PaymentProcessor, error) {
+	if err := beforeCreate(paymentType, details); err != nil {
+		return nil, err
+	}
+	processor, err := createProcessor(paymentType, details)
+	if err != nil {
+		return nil, err
+	}
+	afterCreate(processor)
+	return processor, nil
+}
+
+func createProcessor(paymentType PaymentType, details map[string]string) (PaymentProcessor, error) {
+	// Registrations override the built-in types
+	if reg, ok := lookupRegistration(paymentType); ok {
+		return reg.create(details)
+	}
+
 	switch paymentType {
 	case CreditCard:
+		cardNumber := NormalizeCardNumber(details["cardNumber"])
+		if err := ValidateCardNumber(cardNumber); err != nil {
+			return nil, err
+		}
+		expMonth, expYear, err := ValidateExpiry(details["expMonth"], details["expYear"], clock.Now())
+		if err != nil {
+			return nil, err
+		}
 		return &CreditCardProcessor{
-			cardNumber: details["cardNumber"],
+			cardNumber: cardNumber,
 			cvv:        details["cvv"],
+			expMonth:   expMonth,
+			expYear:    expYear,
 		}, nil
 
 	case PayPal:
+		email := NormalizeEmail(details["email"])
+		if err := ValidateEmail(email); err != nil {
+			return nil, err
+		}
 		return &PayPalProcessor{
-			email: details["email"],
+			email: email,
 		}, nil
 
 	case BankTransfer:
-		return &BankTransferProcessor{
-			accountNumber: details["accountNumber"],
-			routingNumber: details["routingNumber"],
-		}, nil
+		return NewBankTransferProcessor(details["accountNumber"], details["routingNumber"], nil, false)
 
 	default:
-		return nil, fmt.Errorf("unknown payment type: %s", paymentType)
+		return nil, &UnknownPaymentTypeError{Type: paymentType}
 	}
 }
@@ -0,0 +1,53 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCreatePaymentProcessorUnknownType(t *testing.T) {
+	_, err := CreatePaymentProcessor("crypto", nil)
+
+	var unknown *UnknownPaymentTypeError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("CreatePaymentProcessor = %v, want an UnknownPaymentTypeError", err)
+	}
+	if unknown.Type != "crypto" {
+		t.Errorf("Type = %q, want crypto", unknown.Type)
+	}
+	if got, want := err.Error(), "unknown payment type: crypto"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	wrapped := fmt.Errorf("checkout: %w", err)
+	if !errors.As(wrapped, &unknown) {
+		t.Error("errors.As doesn't see through wrapping")
+	}
+}
+
+func TestCreatePaymentProcessorBuiltins(t *testing.T) {
+	tests := []struct {
+		paymentType PaymentType
+		details     map[string]string
+		name        string
+	}{
+		{CreditCard, map[string]string{"cardNumber": "4111 1111 1111 1111", "cvv": "123", "expMonth": "12", "expYear": "2099"}, "Credit Card"},
+		{PayPal, map[string]string{"email": "user@example.com"}, "PayPal"},
+		{BankTransfer, map[string]string{"accountNumber": "12345678", "routingNumber": "021000021"}, "Bank Transfer"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.paymentType), func(t *testing.T) {
+			p, err := CreatePaymentProcessor(tt.paymentType, tt.details)
+			if err != nil {
+				t.Fatalf("CreatePaymentProcessor: %v", err)
+			}
+			if got := p.GetName(); got != tt.name {
+				t.Errorf("GetName() = %q, want %q", got, tt.name)
+			}
+			if err := p.Process(10); err != nil {
+				t.Errorf("Process: %v", err)
+			}
+		})
+	}
+}
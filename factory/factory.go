@@ -0,0 +1,112 @@
+package factory
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Factory creates payment processors. Code that needs to create processors
+// can accept a Factory instead of calling CreatePaymentProcessor directly,
+// so tests can hand it a fake that returns stubs:
+//
+//	fake := factory.FactoryFunc(func(t factory.PaymentType, details map[string]string) (factory.PaymentProcessor, error) {
+//		return &stubProcessor{}, nil
+//	})
+//	checkout := NewCheckout(fake)
+//
+// DefaultFactory and *ProcessorFactory are the real implementations.
+type Factory interface {
+	Create(paymentType PaymentType, details map[string]string) (PaymentProcessor, error)
+}
+
+// FactoryFunc lets an ordinary function act as a Factory
+type FactoryFunc func(paymentType PaymentType, details map[string]string) (PaymentProcessor, error)
+
+func (f FactoryFunc) Create(paymentType PaymentType, details map[string]string) (PaymentProcessor, error) {
+	return f(paymentType, details)
+}
+
+// DefaultFactory creates processors with CreatePaymentProcessor
+var DefaultFactory Factory = FactoryFunc(CreatePaymentProcessor)
+
+// FactoryConfig holds the defaults a ProcessorFactory applies to every
+// processor it creates, so an app can set them up once instead of wrapping
+// each processor by hand.
+type FactoryConfig struct {
+	// Middlewares wrap every processor, first listed outermost
+	Middlewares []Middleware
+	// DryRun stops processors from charging; see NewDryRunProcessor
+	DryRun bool
+	// Logger, when set, logs every charge through NewLoggingProcessor
+	Logger Logger
+	// RejectUnknownDetails makes Create fail when the details map has keys
+	// the payment type's schema doesn't list, which are usually typos.
+	// By default they are ignored.
+	RejectUnknownDetails bool
+}
+
+// ProcessorFactory creates processors like CreatePaymentProcessor and wraps
+// them according to its FactoryConfig. The package-level function is still
+// the simplest choice when no defaults are needed.
+type ProcessorFactory struct {
+	config FactoryConfig
+}
+
+// NewFactory returns a factory that applies config to every processor
+func NewFactory(config FactoryConfig) *ProcessorFactory {
+	config.Middlewares = append([]Middleware(nil), config.Middlewares...)
+	return &ProcessorFactory{config: config}
+}
+
+// Create builds a processor through CreatePaymentProcessor and wraps it.
+// Logging is outermost so it sees the final outcome, then the configured
+// middlewares; dry run is innermost so the middlewares still run around it.
+func (f *ProcessorFactory) Create(paymentType PaymentType, details map[string]string) (PaymentProcessor, error) {
+	if f.config.RejectUnknownDetails {
+		if err := checkUnknownDetails(paymentType, details); err != nil {
+			return nil, err
+		}
+	}
+	processor, err := CreatePaymentProcessor(paymentType, details)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []Middleware
+	if f.config.Logger != nil {
+		chain = append(chain, Logging(f.config.Logger))
+	}
+	chain = append(chain, f.config.Middlewares...)
+	if f.config.DryRun {
+		chain = append(chain, DryRun(f.config.Logger))
+	}
+	return Chain(processor, chain...), nil
+}
+
+// checkUnknownDetails returns an error listing, in order, the keys of details
+// that aren't in the schema for paymentType
+func checkUnknownDetails(paymentType PaymentType, details map[string]string) error {
+	fields, err := PaymentFormSchema(paymentType)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.Name] = true
+	}
+
+	var unknown []string
+	for _, key := range slices.Sorted(maps.Keys(details)) {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		Field:   "details",
+		Message: "unknown keys for " + string(paymentType) + ": " + strings.Join(unknown, ", "),
+	}
+}
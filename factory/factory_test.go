@@ -0,0 +1,200 @@
+package factory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFactoryWrapsWithLogging(t *testing.T) {
+	log := &bufferLogger{}
+	f := NewFactory(FactoryConfig{Logger: log})
+
+	p, err := f.Create(PayPal, map[string]string{"email": "user@example.com"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, ok := p.(*LoggingProcessor); !ok {
+		t.Fatalf("Create returned %T, want *LoggingProcessor", p)
+	}
+	if err := p.Process(12.5); err != nil {
+		t.Fatal(err)
+	}
+	if got := log.String(); !strings.Contains(got, "charging $12.50 via PayPal") || !strings.Contains(got, "charge via PayPal succeeded") {
+		t.Errorf("log = %q, want the charge and its outcome", got)
+	}
+}
+
+func TestFactoryWrapOrder(t *testing.T) {
+	inner := &recordingProcessor{name: "Inner"}
+	register(t, "factory-order", func(map[string]string) (PaymentProcessor, error) { return inner, nil }, nil)
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(p PaymentProcessor) PaymentProcessor {
+			return &middlewareFunc{inner: p, before: func() { order = append(order, name) }}
+		}
+	}
+	log := &bufferLogger{}
+	f := NewFactory(FactoryConfig{
+		Middlewares: []Middleware{tag("first"), tag("second")},
+		DryRun:      true,
+		Logger:      log,
+	})
+
+	p, err := f.Create("factory-order", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Process(5); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(order, ","); got != "first,second" {
+		t.Errorf("middlewares ran %s, want first,second", got)
+	}
+	// Dry run is innermost: the middlewares ran, the processor was never charged
+	if charged := inner.charged(); len(charged) != 0 {
+		t.Errorf("dry run charged %v", charged)
+	}
+	if got := log.String(); !strings.Contains(got, "dry run: would charge $5.00 via Inner") {
+		t.Errorf("log = %q, want the dry run logged through the factory logger", got)
+	}
+}
+
+func TestFactoryWithoutDefaults(t *testing.T) {
+	p, err := NewFactory(FactoryConfig{}).Create(PayPal, map[string]string{"email": "user@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.(*PayPalProcessor); !ok {
+		t.Errorf("Create returned %T, want the bare *PayPalProcessor", p)
+	}
+	if _, err := NewFactory(FactoryConfig{}).Create(PayPal, map[string]string{"email": "nope"}); err == nil {
+		t.Error("Create accepted an invalid email")
+	}
+}
+
+// middlewareFunc calls before and then delegates to inner
+type middlewareFunc struct {
+	inner  PaymentProcessor
+	before func()
+}
+
+func (m *middlewareFunc) Process(amount float64) error {
+	m.before()
+	return m.inner.Process(amount)
+}
+
+func (m *middlewareFunc) GetName() string { return m.inner.GetName() }
+
+func TestFactoryUnknownDetails(t *testing.T) {
+	details := map[string]string{
+		"cardNumber": "4111 1111 1111 1111",
+		"cvv":        "123",
+		"expMonth":   "12",
+		"expYear":    "2099",
+		"expiryYear": "2099",
+		"cvc":        "123",
+	}
+
+	lenient := NewFactory(FactoryConfig{})
+	if _, err := lenient.Create(CreditCard, details); err != nil {
+		t.Fatalf("lenient Create: %v", err)
+	}
+
+	strict := NewFactory(FactoryConfig{RejectUnknownDetails: true})
+	p, err := strict.Create(CreditCard, details)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "details" {
+		t.Fatalf("strict Create = %v, want a details ValidationError", err)
+	}
+	if p != nil {
+		t.Error("strict Create returned a processor alongside the error")
+	}
+	if !strings.HasSuffix(verr.Message, "cvc, expiryYear") {
+		t.Errorf("message %q doesn't list the unknown keys in order", verr.Message)
+	}
+
+	delete(details, "expiryYear")
+	delete(details, "cvc")
+	if _, err := strict.Create(CreditCard, details); err != nil {
+		t.Errorf("strict Create with only known keys: %v", err)
+	}
+}
+
+func TestFactoryUnknownDetailsOptionalFields(t *testing.T) {
+	strict := NewFactory(FactoryConfig{RejectUnknownDetails: true})
+	// Optional fields count as known, not just the required ones
+	details := map[string]string{"forceDecline": "false"}
+	if _, err := strict.Create(Sandbox, details); err != nil {
+		t.Errorf("strict Create with an optional field: %v", err)
+	}
+
+	if _, err := strict.Create(PaymentType("nope"), map[string]string{"x": "y"}); err == nil {
+		t.Error("strict Create with an unknown payment type succeeded")
+	}
+}
+
+// checkout stands in for application code that creates processors through
+// an injected Factory
+type checkout struct {
+	factory Factory
+}
+
+func (c *checkout) pay(paymentType PaymentType, details map[string]string, amount float64) error {
+	p, err := c.factory.Create(paymentType, details)
+	if err != nil {
+		return err
+	}
+	return p.Process(amount)
+}
+
+var (
+	_ Factory = DefaultFactory
+	_ Factory = (*ProcessorFactory)(nil)
+	_ Factory = (*CachingFactory)(nil)
+)
+
+func TestFakeFactoryInjection(t *testing.T) {
+	stub := &recordingProcessor{name: "Stub"}
+	var asked []PaymentType
+	fake := FactoryFunc(func(paymentType PaymentType, details map[string]string) (PaymentProcessor, error) {
+		asked = append(asked, paymentType)
+		if paymentType == "crypto" {
+			return nil, &UnknownPaymentTypeError{Type: paymentType}
+		}
+		return stub, nil
+	})
+	c := &checkout{factory: fake}
+
+	// No real card details needed: the fake never validates them
+	if err := c.pay(CreditCard, nil, 42); err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+	if got := stub.charged(); len(got) != 1 || got[0] != 42 {
+		t.Errorf("stub charged %v, want [42]", got)
+	}
+
+	var unknown *UnknownPaymentTypeError
+	if err := c.pay("crypto", nil, 1); !errors.As(err, &unknown) {
+		t.Errorf("pay with an unknown type = %v, want the fake's error", err)
+	}
+	if len(asked) != 2 || asked[0] != CreditCard {
+		t.Errorf("factory asked for %v", asked)
+	}
+}
+
+func TestDefaultFactoryMatchesCreatePaymentProcessor(t *testing.T) {
+	details := map[string]string{"email": "user@example.com"}
+	p, err := DefaultFactory.Create(PayPal, details)
+	if err != nil {
+		t.Fatalf("DefaultFactory.Create: %v", err)
+	}
+	want, _ := CreatePaymentProcessor(PayPal, details)
+	if p.GetName() != want.GetName() {
+		t.Errorf("DefaultFactory created %q, want %q", p.GetName(), want.GetName())
+	}
+	if _, err := DefaultFactory.Create(PayPal, nil); err == nil {
+		t.Error("DefaultFactory.Create skipped validation")
+	}
+}
@@ -0,0 +1,88 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrProcessorPanicked is matched (via errors.Is) by the error a
+// FinalizingProcessor returns when the processor it wraps panics
+var ErrProcessorPanicked = errors.New("payment processor panicked")
+
+// FinalizingProcessor runs registered hooks after every charge, whatever
+// its outcome, like a defer around Process. A panic in the wrapped
+// processor is recovered and turned into an error wrapping
+// ErrProcessorPanicked, so the hooks still run and the caller gets an error
+// instead of a crash. Use it to release resources held for a charge or to
+// emit final metrics.
+type FinalizingProcessor struct {
+	inner PaymentProcessor
+
+	mu    sync.Mutex
+	hooks []func(*Receipt, error)
+}
+
+// NewFinalizingProcessor wraps inner; add hooks with AfterProcess
+func NewFinalizingProcessor(inner PaymentProcessor) *FinalizingProcessor {
+	return &FinalizingProcessor{inner: inner}
+}
+
+// AfterProcess registers fn to run after each charge with its receipt and
+// error. The receipt is nil if the charge failed. Like deferred calls, hooks
+// run in reverse order of registration.
+func (f *FinalizingProcessor) AfterProcess(fn func(*Receipt, error)) *FinalizingProcessor {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hooks = append(f.hooks, fn)
+	return f
+}
+
+func (f *FinalizingProcessor) Process(amount float64) error {
+	_, err := f.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+func (f *FinalizingProcessor) ProcessCtx(ctx context.Context, amount float64) (receipt *Receipt, err error) {
+	f.mu.Lock()
+	hooks := slices.Clone(f.hooks)
+	f.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			receipt, err = nil, fmt.Errorf("%w: %s: %v", ErrProcessorPanicked, f.inner.GetName(), r)
+		}
+		for i := len(hooks) - 1; i >= 0; i-- {
+			runHook(hooks[i], receipt, err)
+		}
+	}()
+	return ProcessCtx(ctx, f.inner, amount)
+}
+
+// runHook calls one hook, containing any panic in it so the remaining hooks still run
+func runHook(hook func(*Receipt, error), receipt *Receipt, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Printf("AfterProcess hook panicked: %v\n", r)
+		}
+	}()
+	hook(receipt, err)
+}
+
+func (f *FinalizingProcessor) GetName() string {
+	return f.inner.GetName()
+}
+
+func (f *FinalizingProcessor) Details() map[string]string {
+	return detailsOf(f.inner)
+}
+
+func (f *FinalizingProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(f.inner)
+}
+
+func (f *FinalizingProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, f.inner)
+}
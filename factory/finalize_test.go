@@ -0,0 +1,144 @@
+package factory
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// panickingProcessor panics with its message on every charge
+type panickingProcessor struct {
+	message string
+}
+
+func (p *panickingProcessor) Process(amount float64) error { panic(p.message) }
+
+func (p *panickingProcessor) GetName() string { return "Panicking" }
+
+// hookCall is one AfterProcess invocation
+type hookCall struct {
+	receipt *Receipt
+	err     error
+}
+
+// recordHook returns a hook that appends its arguments to calls
+func recordHook(calls *[]hookCall) func(*Receipt, error) {
+	return func(r *Receipt, err error) { *calls = append(*calls, hookCall{r, err}) }
+}
+
+func TestAfterProcessOnSuccess(t *testing.T) {
+	var calls []hookCall
+	p := NewFinalizingProcessor(&recordingProcessor{name: "Recording"}).AfterProcess(recordHook(&calls))
+
+	if err := p.Process(25); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("hook ran %d times, want 1", len(calls))
+	}
+	if calls[0].err != nil || calls[0].receipt == nil || calls[0].receipt.Amount != 25 {
+		t.Errorf("hook got %+v, %v, want the receipt for 25", calls[0].receipt, calls[0].err)
+	}
+}
+
+func TestAfterProcessOnError(t *testing.T) {
+	declined := errors.New("declined")
+	var calls []hookCall
+	p := NewFinalizingProcessor(&recordingProcessor{err: declined}).AfterProcess(recordHook(&calls))
+
+	if err := p.Process(25); !errors.Is(err, declined) {
+		t.Fatalf("Process = %v, want the inner error", err)
+	}
+	if len(calls) != 1 || calls[0].receipt != nil || !errors.Is(calls[0].err, declined) {
+		t.Errorf("hook calls = %+v, want one with a nil receipt and the error", calls)
+	}
+}
+
+func TestAfterProcessAfterPanic(t *testing.T) {
+	var calls []hookCall
+	p := NewFinalizingProcessor(&panickingProcessor{message: "card reader on fire"}).AfterProcess(recordHook(&calls))
+
+	err := p.Process(25)
+	if !errors.Is(err, ErrProcessorPanicked) {
+		t.Fatalf("Process = %v, want ErrProcessorPanicked", err)
+	}
+	if !strings.Contains(err.Error(), "Panicking") || !strings.Contains(err.Error(), "card reader on fire") {
+		t.Errorf("error %q doesn't name the processor and panic value", err)
+	}
+	if len(calls) != 1 || calls[0].receipt != nil || !errors.Is(calls[0].err, ErrProcessorPanicked) {
+		t.Errorf("hook calls = %+v, want one seeing the recovered error", calls)
+	}
+}
+
+func TestAfterProcessHookOrder(t *testing.T) {
+	var order []string
+	p := NewFinalizingProcessor(&recordingProcessor{})
+	for _, name := range []string{"first", "second", "third"} {
+		p.AfterProcess(func(*Receipt, error) { order = append(order, name) })
+	}
+
+	if err := p.Process(10); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"third", "second", "first"}; !slices.Equal(order, want) {
+		t.Errorf("hooks ran in order %v, want %v", order, want)
+	}
+}
+
+func TestAfterProcessPanickingHook(t *testing.T) {
+	log := captureLog(t)
+	var calls []hookCall
+	p := NewFinalizingProcessor(&recordingProcessor{}).
+		AfterProcess(recordHook(&calls)).
+		AfterProcess(func(*Receipt, error) { panic("hook broke") })
+
+	if err := p.Process(10); err != nil {
+		t.Fatalf("Process = %v, want a hook panic not to fail the charge", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("remaining hook ran %d times, want 1", len(calls))
+	}
+	if !strings.Contains(log.String(), "hook broke") {
+		t.Errorf("log = %q, want the hook panic reported", log.String())
+	}
+}
+
+func TestAfterProcessConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	count := 0
+	p := NewFinalizingProcessor(&recordingProcessor{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			p.AfterProcess(func(*Receipt, error) {
+				mu.Lock()
+				count++
+				mu.Unlock()
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			if err := p.Process(1); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	before := count
+	mu.Unlock()
+	if err := p.Process(1); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if count-before != 20 {
+		t.Errorf("final charge ran %d hooks, want all 20", count-before)
+	}
+}
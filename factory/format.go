@@ -0,0 +1,89 @@
+package factory
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency processors charge in when none is given
+const DefaultCurrency = "USD"
+
+// currencyFormat describes how amounts in a currency are written
+type currencyFormat struct {
+	symbol   string
+	decimals int
+	group    string
+	decimal  string
+}
+
+var currencyFormats = map[string]currencyFormat{
+	"USD": {symbol: "$", decimals: 2, group: ",", decimal: "."},
+	"EUR": {symbol: "€", decimals: 2, group: ".", decimal: ","},
+	"GBP": {symbol: "£", decimals: 2, group: ",", decimal: "."},
+	"JPY": {symbol: "¥", decimals: 0, group: ",", decimal: "."},
+	"BHD": {symbol: "BD ", decimals: 3, group: ",", decimal: "."},
+}
+
+// FormatAmount renders amount using the symbol, digit grouping and number
+// of decimal places customary for the currency:
+//
+//	FormatAmount(1234.5, "USD") // "$1,234.50"
+//	FormatAmount(1234.5, "EUR") // "€1.234,50"
+//	FormatAmount(1234.5, "JPY") // "¥1,235"
+//
+// Unknown currencies fall back to US-style digits followed by the code.
+func FormatAmount(amount float64, currency string) string {
+	currency = strings.ToUpper(currency)
+	format, known := currencyFormats[currency]
+	if !known {
+		format = currencyFormat{decimals: 2, group: ",", decimal: "."}
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	// Work in minor units so rounding happens once, up front
+	scale := math.Pow10(format.decimals)
+	minor := int64(math.Round(amount * scale))
+	whole := minor / int64(scale)
+	fraction := minor % int64(scale)
+
+	var b strings.Builder
+	b.WriteString(sign)
+	b.WriteString(format.symbol)
+	b.WriteString(groupDigits(strconv.FormatInt(whole, 10), format.group))
+	if format.decimals > 0 {
+		frac := strconv.FormatInt(fraction, 10)
+		b.WriteString(format.decimal)
+		b.WriteString(strings.Repeat("0", format.decimals-len(frac)))
+		b.WriteString(frac)
+	}
+	if !known {
+		b.WriteString(" ")
+		b.WriteString(currency)
+	}
+	return b.String()
+}
+
+// groupDigits inserts sep between every group of three digits
+func groupDigits(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
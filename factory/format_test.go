@@ -0,0 +1,46 @@
+package factory
+
+import "testing"
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     string
+	}{
+		{1234.5, "USD", "$1,234.50"},
+		{1234.5, "EUR", "€1.234,50"},
+		{1234.5, "GBP", "£1,234.50"},
+		{1234.5, "JPY", "¥1,235"},
+		{1234.5, "BHD", "BD 1,234.500"},
+		{1234.5, "usd", "$1,234.50"},
+		{0, "USD", "$0.00"},
+		{0.05, "USD", "$0.05"},
+		{999, "USD", "$999.00"},
+		{1000000, "USD", "$1,000,000.00"},
+		{-1234.5, "USD", "-$1,234.50"},
+		{19.999, "USD", "$20.00"},
+		{1234.5, "CHF", "1,234.50 CHF"},
+	}
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatAmount(%v, %q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestGroupDigits(t *testing.T) {
+	tests := map[string]string{
+		"":        "",
+		"1":       "1",
+		"123":     "123",
+		"1234":    "1,234",
+		"123456":  "123,456",
+		"1234567": "1,234,567",
+	}
+	for in, want := range tests {
+		if got := groupDigits(in, ","); got != want {
+			t.Errorf("groupDigits(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
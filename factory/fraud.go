@@ -0,0 +1,66 @@
+package factory
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrFraudSuspected is returned when a charge is blocked for looking fraudulent
+var ErrFraudSuspected = errors.New("payment blocked: fraud suspected")
+
+// FraudScorer rates how likely a charge is to be fraudulent,
+// from 0 (certainly fine) to 1 (certainly fraud).
+type FraudScorer interface {
+	Score(amount float64, details map[string]string) float64
+}
+
+// FraudScorerFunc lets an ordinary function act as a FraudScorer
+type FraudScorerFunc func(amount float64, details map[string]string) float64
+
+func (f FraudScorerFunc) Score(amount float64, details map[string]string) float64 {
+	return f(amount, details)
+}
+
+// FraudCheckProcessor scores every charge before passing it on
+type FraudCheckProcessor struct {
+	inner     PaymentProcessor
+	scorer    FraudScorer
+	threshold float64
+}
+
+// NewFraudCheckProcessor wraps inner so charges scoring above threshold
+// are rejected with ErrFraudSuspected and never reach inner.
+func NewFraudCheckProcessor(inner PaymentProcessor, scorer FraudScorer, threshold float64) *FraudCheckProcessor {
+	return &FraudCheckProcessor{inner: inner, scorer: scorer, threshold: threshold}
+}
+
+func (f *FraudCheckProcessor) Process(amount float64) error {
+	_, err := f.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+// ProcessCtx is Process with a context, returning the inner processor's receipt
+func (f *FraudCheckProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	if score := f.scorer.Score(amount, detailsOf(f.inner)); score > f.threshold {
+		logger.Printf("Blocked %s via %s (fraud score %.2f > %.2f)\n",
+			FormatAmount(amount, DefaultCurrency), f.inner.GetName(), score, f.threshold)
+		return nil, ErrFraudSuspected
+	}
+	return ProcessCtx(ctx, f.inner, amount)
+}
+
+func (f *FraudCheckProcessor) GetName() string {
+	return f.inner.GetName()
+}
+
+func (f *FraudCheckProcessor) Details() map[string]string {
+	return detailsOf(f.inner)
+}
+
+func (f *FraudCheckProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(f.inner)
+}
+
+func (f *FraudCheckProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, f.inner)
+}
@@ -0,0 +1,53 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFraudCheckProcessor(t *testing.T) {
+	// Scores rise with the amount: 0.1 per 100
+	scorer := FraudScorerFunc(func(amount float64, _ map[string]string) float64 { return amount / 1000 })
+	tests := []struct {
+		name    string
+		amount  float64
+		wantErr error
+	}{
+		{"low score", 100, nil},
+		{"at threshold", 800, nil},
+		{"high score", 900, ErrFraudSuspected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &recordingProcessor{}
+			p := NewFraudCheckProcessor(inner, scorer, 0.8)
+			if err := p.Process(tt.amount); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Process(%v) = %v, want %v", tt.amount, err, tt.wantErr)
+			}
+			charged := len(inner.charged()) == 1
+			if charged != (tt.wantErr == nil) {
+				t.Errorf("inner charged = %v, want %v", charged, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestFraudCheckProcessorSeesDetails(t *testing.T) {
+	inner, err := CreatePaymentProcessor(PayPal, map[string]string{"email": "fraudster@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scorer := FraudScorerFunc(func(_ float64, details map[string]string) float64 {
+		if details["email"] == "fraudster@example.com" {
+			return 1
+		}
+		return 0
+	})
+	p := NewFraudCheckProcessor(inner, scorer, 0.5)
+	if err := p.Process(1); !errors.Is(err, ErrFraudSuspected) {
+		t.Errorf("Process = %v, want ErrFraudSuspected", err)
+	}
+	if got := p.GetName(); got != "PayPal" {
+		t.Errorf("GetName() = %q, want PayPal", got)
+	}
+}
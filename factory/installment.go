@@ -0,0 +1,119 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Installment is one scheduled part of a payment
+type Installment struct {
+	Due    time.Time
+	Amount float64
+}
+
+// InstallmentProcessor splits a payment into equal monthly installments.
+// The first is charged right away; the rest are returned as a schedule for
+// the caller to charge when they fall due, which is why the processor it
+// wraps must support recurring charges.
+type InstallmentProcessor struct {
+	inner PaymentProcessor
+	count int
+	clock Clock
+}
+
+// NewInstallmentProcessor splits payments through inner into count
+// installments, one a month from today. A nil clock means the real one.
+func NewInstallmentProcessor(inner PaymentProcessor, count int, clock Clock) (*InstallmentProcessor, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("installment count must be at least 1, got %d", count)
+	}
+	if !capabilitiesOf(inner).Recurring {
+		return nil, fmt.Errorf("%s doesn't support recurring charges, so it can't take installments", inner.GetName())
+	}
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &InstallmentProcessor{inner: inner, count: count, clock: clock}, nil
+}
+
+// Schedule divides amount into the installments without charging anything.
+// Amounts are split in whole cents; cents that don't divide evenly go on the
+// first installment, so the installments always add up to amount exactly.
+// The first is due now and each one after it a calendar month later, on the
+// same day of the month or the month's last day if it is shorter: a schedule
+// starting on January 31 continues on February 28 (29 in leap years), then
+// March 31.
+func (i *InstallmentProcessor) Schedule(amount float64) ([]Installment, error) {
+	if err := ValidatePrecision(amount, DefaultCurrency); err != nil {
+		return nil, err
+	}
+	total := toMinor(amount, DefaultCurrency)
+	share, remainder := total/int64(i.count), total%int64(i.count)
+	if share <= 0 {
+		return nil, fmt.Errorf("%s is too small to split into %d installments", FormatAmount(amount, DefaultCurrency), i.count)
+	}
+
+	now := i.clock.Now()
+	schedule := make([]Installment, i.count)
+	for n := range schedule {
+		minor := share
+		if n == 0 {
+			minor += remainder
+		}
+		schedule[n] = Installment{Due: addMonths(now, n), Amount: fromMinor(minor, DefaultCurrency)}
+	}
+	return schedule, nil
+}
+
+// ProcessInstallments charges the first installment and returns its receipt
+// along with the full schedule, first installment included
+func (i *InstallmentProcessor) ProcessInstallments(ctx context.Context, amount float64) ([]Installment, *Receipt, error) {
+	schedule, err := i.Schedule(amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt, err := ProcessCtx(ctx, i.inner, schedule[0].Amount)
+	if err != nil {
+		return nil, nil, fmt.Errorf("first installment failed: %w", err)
+	}
+	return schedule, receipt, nil
+}
+
+func (i *InstallmentProcessor) Process(amount float64) error {
+	_, _, err := i.ProcessInstallments(context.Background(), amount)
+	return err
+}
+
+// ProcessCtx charges the first installment and returns its receipt
+func (i *InstallmentProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	_, receipt, err := i.ProcessInstallments(ctx, amount)
+	return receipt, err
+}
+
+func (i *InstallmentProcessor) GetName() string {
+	return i.inner.GetName()
+}
+
+func (i *InstallmentProcessor) Details() map[string]string {
+	return detailsOf(i.inner)
+}
+
+func (i *InstallmentProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(i.inner)
+}
+
+func (i *InstallmentProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, i.inner)
+}
+
+// addMonths returns t moved forward by months calendar months, keeping the
+// day of the month but clamping it to the last day of shorter months.
+// time.AddDate would normalize January 31 + 1 month to March 3 instead.
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	// Day 0 of the month after the target is the target's last day
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	hour, minute, sec := t.Clock()
+	return time.Date(year, month+time.Month(months), min(day, lastDay), hour, minute, sec, t.Nanosecond(), t.Location())
+}
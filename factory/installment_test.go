@@ -0,0 +1,189 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// recurringProcessor is a recordingProcessor that supports recurring charges
+type recurringProcessor struct {
+	recordingProcessor
+}
+
+func (r *recurringProcessor) Capabilities() ProcessorCapabilities {
+	return ProcessorCapabilities{Recurring: true}
+}
+
+func newInstallments(t *testing.T, inner PaymentProcessor, count int, clock Clock) *InstallmentProcessor {
+	t.Helper()
+	p, err := NewInstallmentProcessor(inner, count, clock)
+	if err != nil {
+		t.Fatalf("NewInstallmentProcessor: %v", err)
+	}
+	return p
+}
+
+func TestInstallmentSchedule(t *testing.T) {
+	tests := []struct {
+		amount float64
+		count  int
+		want   []float64
+	}{
+		{300, 3, []float64{100, 100, 100}},
+		{100, 3, []float64{33.34, 33.33, 33.33}},
+		{100.01, 4, []float64{25.01, 25, 25, 25}},
+		{0.05, 3, []float64{0.03, 0.01, 0.01}},
+		{19.99, 1, []float64{19.99}},
+	}
+	for _, tt := range tests {
+		p := newInstallments(t, &recurringProcessor{}, tt.count, newFakeClock(time.Now()))
+		schedule, err := p.Schedule(tt.amount)
+		if err != nil {
+			t.Errorf("Schedule(%v) into %d: %v", tt.amount, tt.count, err)
+			continue
+		}
+		var got []float64
+		var sum int64
+		for _, inst := range schedule {
+			got = append(got, inst.Amount)
+			sum += toMinor(inst.Amount, DefaultCurrency)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Schedule(%v) into %d = %v, want %v", tt.amount, tt.count, got, tt.want)
+		}
+		if sum != toMinor(tt.amount, DefaultCurrency) {
+			t.Errorf("Schedule(%v) into %d sums to %d cents", tt.amount, tt.count, sum)
+		}
+	}
+}
+
+func TestInstallmentDueDates(t *testing.T) {
+	start := time.Date(2025, time.January, 15, 10, 0, 0, 0, time.UTC)
+	p := newInstallments(t, &recurringProcessor{}, 3, newFakeClock(start))
+
+	schedule, err := p.Schedule(90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Time{start, start.AddDate(0, 1, 0), start.AddDate(0, 2, 0)}
+	for n, inst := range schedule {
+		if !inst.Due.Equal(want[n]) {
+			t.Errorf("installment %d due %v, want %v", n, inst.Due, want[n])
+		}
+	}
+}
+
+func TestInstallmentDueDatesAtMonthEnd(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+		want  []time.Time
+	}{
+		{"January 31", time.Date(2025, time.January, 31, 9, 30, 0, 0, time.UTC), []time.Time{
+			time.Date(2025, time.January, 31, 9, 30, 0, 0, time.UTC),
+			time.Date(2025, time.February, 28, 9, 30, 0, 0, time.UTC),
+			time.Date(2025, time.March, 31, 9, 30, 0, 0, time.UTC),
+			time.Date(2025, time.April, 30, 9, 30, 0, 0, time.UTC),
+		}},
+		{"leap year", time.Date(2024, time.January, 30, 0, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2024, time.January, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC),
+		}},
+		{"across the year", time.Date(2025, time.November, 30, 0, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2025, time.November, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, time.December, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, time.January, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newInstallments(t, &recurringProcessor{}, len(tt.want), newFakeClock(tt.start))
+			schedule, err := p.Schedule(100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for n, inst := range schedule {
+				if !inst.Due.Equal(tt.want[n]) {
+					t.Errorf("installment %d due %v, want %v", n, inst.Due, tt.want[n])
+				}
+			}
+		})
+	}
+}
+
+func TestProcessInstallmentsChargesFirstOnly(t *testing.T) {
+	inner := &recurringProcessor{}
+	p := newInstallments(t, inner, 3, newFakeClock(time.Now()))
+
+	schedule, receipt, err := p.ProcessInstallments(context.Background(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := inner.charged(); !slices.Equal(got, []float64{33.34}) {
+		t.Errorf("charged %v, want just the first installment", got)
+	}
+	if receipt == nil || receipt.Amount != 33.34 || len(schedule) != 3 {
+		t.Errorf("receipt = %+v, schedule = %v", receipt, schedule)
+	}
+}
+
+func TestProcessInstallmentsFailure(t *testing.T) {
+	declined := errors.New("declined")
+	p := newInstallments(t, &recurringProcessor{recordingProcessor{err: declined}}, 3, nil)
+
+	schedule, receipt, err := p.ProcessInstallments(context.Background(), 100)
+	if !errors.Is(err, declined) || schedule != nil || receipt != nil {
+		t.Errorf("ProcessInstallments = %v, %v, %v, want only the inner error", schedule, receipt, err)
+	}
+}
+
+func TestInstallmentErrors(t *testing.T) {
+	if _, err := NewInstallmentProcessor(&recurringProcessor{}, 0, nil); err == nil {
+		t.Error("NewInstallmentProcessor accepted 0 installments")
+	}
+	if _, err := NewInstallmentProcessor(&recordingProcessor{}, 3, nil); err == nil {
+		t.Error("NewInstallmentProcessor accepted a processor without recurring charges")
+	}
+
+	p := newInstallments(t, &recurringProcessor{}, 3, nil)
+	if _, err := p.Schedule(0.02); err == nil {
+		t.Error("Schedule split 2 cents into 3 installments")
+	}
+	if _, err := p.Schedule(99.999); !errors.Is(err, ErrInvalidPrecision) {
+		t.Errorf("Schedule(99.999) = %v, want ErrInvalidPrecision", err)
+	}
+}
+
+func FuzzInstallmentSchedule(f *testing.F) {
+	f.Add(int64(10000), 3)
+	f.Add(int64(10001), 4)
+	f.Add(int64(7), 7)
+	p := &InstallmentProcessor{inner: &recurringProcessor{}, clock: realClock{}}
+	f.Fuzz(func(t *testing.T, cents int64, count int) {
+		if cents < 1 || cents > 1e12 || count < 1 || count > 360 || cents < int64(count) {
+			t.Skip()
+		}
+		p.count = count
+		schedule, err := p.Schedule(fromMinor(cents, DefaultCurrency))
+		if err != nil {
+			t.Fatalf("Schedule(%d cents) into %d: %v", cents, count, err)
+		}
+		var sum int64
+		for n, inst := range schedule {
+			minor := toMinor(inst.Amount, DefaultCurrency)
+			if n > 0 && minor != toMinor(schedule[1].Amount, DefaultCurrency) {
+				t.Fatalf("installment %d is %d cents, unlike the rest", n, minor)
+			}
+			sum += minor
+		}
+		if sum != cents {
+			t.Fatalf("%d cents into %d sums to %d", cents, count, sum)
+		}
+	})
+}
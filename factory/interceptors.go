@@ -0,0 +1,58 @@
+package factory
+
+import "sync"
+
+// CreateInterceptor runs before the factory builds a processor.
+// Returning an error vetoes the creation, and the error is returned to the caller.
+type CreateInterceptor func(t PaymentType, details map[string]string) error
+
+// PostCreateInterceptor runs after the factory has built a processor
+type PostCreateInterceptor func(p PaymentProcessor)
+
+var (
+	interceptorsMu  sync.RWMutex
+	createHooks     []CreateInterceptor
+	postCreateHooks []PostCreateInterceptor
+)
+
+// AddCreateInterceptor registers fn to run before every CreatePaymentProcessor
+// call, for cross-cutting rules like auditing or disabling a payment type.
+// Interceptors run in registration order and the first error stops creation.
+func AddCreateInterceptor(fn CreateInterceptor) {
+	interceptorsMu.Lock()
+	defer interceptorsMu.Unlock()
+	createHooks = append(createHooks, fn)
+}
+
+// AddPostCreateInterceptor registers fn to observe every processor the
+// factory creates successfully, in registration order
+func AddPostCreateInterceptor(fn PostCreateInterceptor) {
+	interceptorsMu.Lock()
+	defer interceptorsMu.Unlock()
+	postCreateHooks = append(postCreateHooks, fn)
+}
+
+// beforeCreate runs the create interceptors, stopping at the first error.
+// Interceptors are called without the lock held so they may register more.
+func beforeCreate(t PaymentType, details map[string]string) error {
+	interceptorsMu.RLock()
+	hooks := append([]CreateInterceptor(nil), createHooks...)
+	interceptorsMu.RUnlock()
+
+	for _, fn := range hooks {
+		if err := fn(t, details); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func afterCreate(p PaymentProcessor) {
+	interceptorsMu.RLock()
+	hooks := append([]PostCreateInterceptor(nil), postCreateHooks...)
+	interceptorsMu.RUnlock()
+
+	for _, fn := range hooks {
+		fn(p)
+	}
+}
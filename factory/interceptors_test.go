@@ -0,0 +1,72 @@
+package factory
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// restoreInterceptors removes the interceptors a test adds once it ends,
+// since the package has no way to unregister them
+func restoreInterceptors(t *testing.T) {
+	t.Helper()
+	interceptorsMu.RLock()
+	create, post := slices.Clone(createHooks), slices.Clone(postCreateHooks)
+	interceptorsMu.RUnlock()
+	t.Cleanup(func() {
+		interceptorsMu.Lock()
+		defer interceptorsMu.Unlock()
+		createHooks, postCreateHooks = create, post
+	})
+}
+
+func TestCreateInterceptorVetoes(t *testing.T) {
+	restoreInterceptors(t)
+	disabled := errors.New("bank transfers are disabled")
+	var order []string
+	AddCreateInterceptor(func(pt PaymentType, details map[string]string) error {
+		order = append(order, "first:"+string(pt))
+		return nil
+	})
+	AddCreateInterceptor(func(pt PaymentType, details map[string]string) error {
+		order = append(order, "second:"+string(pt))
+		if pt == BankTransfer {
+			return disabled
+		}
+		return nil
+	})
+	AddCreateInterceptor(func(pt PaymentType, details map[string]string) error {
+		order = append(order, "third:"+string(pt))
+		return nil
+	})
+
+	_, err := CreatePaymentProcessor(BankTransfer, map[string]string{"accountNumber": "12345678", "routingNumber": "021000021"})
+	if !errors.Is(err, disabled) {
+		t.Fatalf("CreatePaymentProcessor = %v, want the veto", err)
+	}
+	if want := []string{"first:bank", "second:bank"}; !slices.Equal(order, want) {
+		t.Errorf("interceptors ran %v, want %v", order, want)
+	}
+
+	order = nil
+	if _, err := CreatePaymentProcessor(PayPal, map[string]string{"email": "user@example.com"}); err != nil {
+		t.Errorf("CreatePaymentProcessor(PayPal) = %v, want it allowed", err)
+	}
+	if len(order) != 3 {
+		t.Errorf("interceptors ran %v, want all three", order)
+	}
+}
+
+func TestPostCreateInterceptorObserves(t *testing.T) {
+	restoreInterceptors(t)
+	var seen []string
+	AddPostCreateInterceptor(func(p PaymentProcessor) { seen = append(seen, p.GetName()) })
+
+	CreatePaymentProcessor(PayPal, map[string]string{"email": "user@example.com"})
+	CreatePaymentProcessor(Sandbox, nil)
+	CreatePaymentProcessor(PayPal, map[string]string{"email": "invalid"}) // fails, not observed
+
+	if want := []string{"PayPal", "Sandbox"}; !slices.Equal(seen, want) {
+		t.Errorf("post-create interceptor saw %v, want %v", seen, want)
+	}
+}
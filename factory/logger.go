@@ -0,0 +1,55 @@
+package factory
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Logger receives the package's diagnostic output.
+// Anything with a Printf method fits, including *log.Logger.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// stdoutLogger is the default Logger and writes to standard output
+type stdoutLogger struct{}
+
+func (stdoutLogger) Printf(format string, args ...any) {
+	fmt.Printf(format, args...)
+}
+
+// discardLogger drops everything it is given
+type discardLogger struct{}
+
+func (discardLogger) Printf(string, ...any) {}
+
+// installedLogger boxes the Logger set by SetLogger so it fits in an
+// atomic.Pointer
+type installedLogger struct{ Logger }
+
+// currentLogger is nil until SetLogger is first called, which means stdout
+var currentLogger atomic.Pointer[installedLogger]
+
+// packageLogger forwards to whatever SetLogger installed last. Reading the
+// logger through an atomic lets SetLogger run while other goroutines log.
+type packageLogger struct{}
+
+func (packageLogger) Printf(format string, args ...any) {
+	if l := currentLogger.Load(); l != nil {
+		l.Printf(format, args...)
+		return
+	}
+	stdoutLogger{}.Printf(format, args...)
+}
+
+var logger Logger = packageLogger{}
+
+// SetLogger redirects the package's output to l.
+// Passing nil silences output entirely. It is safe to call concurrently
+// with code that logs.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = discardLogger{}
+	}
+	currentLogger.Store(&installedLogger{l})
+}
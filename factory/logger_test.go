@@ -0,0 +1,78 @@
+package factory
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// bufferLogger is a Logger that keeps everything it is given
+type bufferLogger struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *bufferLogger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(&l.buf, format, args...)
+}
+
+func (l *bufferLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
+
+// captureLog sends the package's output to a fresh bufferLogger for the
+// rest of the test
+func captureLog(t *testing.T) *bufferLogger {
+	t.Helper()
+	l := &bufferLogger{}
+	SetLogger(l)
+	t.Cleanup(func() { SetLogger(nil) })
+	return l
+}
+
+func TestSetLoggerCapturesProcessing(t *testing.T) {
+	log := captureLog(t)
+	p, err := CreatePaymentProcessor(PayPal, map[string]string{"email": "user@example.com"})
+	if err != nil {
+		t.Fatalf("CreatePaymentProcessor: %v", err)
+	}
+	if err := p.Process(25); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if got := log.String(); !strings.Contains(got, "via PayPal for user@example.com") {
+		t.Errorf("log = %q, want the PayPal processing line", got)
+	}
+}
+
+// Run with -race: swapping the logger must not race with processing
+func TestSetLoggerConcurrentWithProcessing(t *testing.T) {
+	t.Cleanup(func() { SetLogger(nil) })
+	p, err := CreatePaymentProcessor(PayPal, map[string]string{"email": "user@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				SetLogger(&bufferLogger{})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				p.Process(1)
+			}
+		}()
+	}
+	wg.Wait()
+}
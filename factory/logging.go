@@ -0,0 +1,79 @@
+package factory
+
+import "context"
+
+// LoggingProcessor logs every charge and its outcome.
+// When a request ID is present in the context it is included in each log
+// line and attached to the receipt, so a payment can be traced end to end.
+// Every line goes through a Scrubber first, so card numbers and CVVs in
+// error messages don't end up in the logs.
+type LoggingProcessor struct {
+	inner    PaymentProcessor
+	logger   Logger
+	scrubber *Scrubber
+}
+
+// NewLoggingProcessor wraps inner, writing to l (or the package logger if l
+// is nil) through DefaultScrubber
+func NewLoggingProcessor(inner PaymentProcessor, l Logger) *LoggingProcessor {
+	return &LoggingProcessor{inner: inner, logger: l, scrubber: DefaultScrubber()}
+}
+
+// WithScrubber replaces the scrubber run over each log line.
+// A nil scrubber logs lines as they are.
+func (l *LoggingProcessor) WithScrubber(s *Scrubber) *LoggingProcessor {
+	l.scrubber = s
+	return l
+}
+
+// Logging returns a Middleware that wraps processors with NewLoggingProcessor
+func Logging(l Logger) Middleware {
+	return func(p PaymentProcessor) PaymentProcessor { return NewLoggingProcessor(p, l) }
+}
+
+func (l *LoggingProcessor) Process(amount float64) error {
+	_, err := l.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+func (l *LoggingProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	base := l.logger
+	if base == nil {
+		base = logger
+	}
+	out := scrubbingLogger{out: base, scrubber: l.scrubber}
+
+	prefix := ""
+	if id, ok := RequestIDFromContext(ctx); ok {
+		prefix = "[" + id + "] "
+	}
+
+	out.Printf("%scharging %s via %s\n", prefix, FormatAmount(amount, DefaultCurrency), l.inner.GetName())
+	receipt, err := ProcessCtx(ctx, l.inner, amount)
+	if err != nil {
+		out.Printf("%scharge via %s failed: %v\n", prefix, l.inner.GetName(), err)
+		return nil, err
+	}
+
+	if id, ok := RequestIDFromContext(ctx); ok {
+		receipt.RequestID = id
+	}
+	out.Printf("%scharge via %s succeeded: %s\n", prefix, l.inner.GetName(), receipt.TransactionID)
+	return receipt, nil
+}
+
+func (l *LoggingProcessor) GetName() string {
+	return l.inner.GetName()
+}
+
+func (l *LoggingProcessor) Details() map[string]string {
+	return detailsOf(l.inner)
+}
+
+func (l *LoggingProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(l.inner)
+}
+
+func (l *LoggingProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, l.inner)
+}
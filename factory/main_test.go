@@ -0,0 +1,75 @@
+package factory
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	SetLogger(nil)
+	os.Exit(m.Run())
+}
+
+// recordingProcessor is a PaymentProcessor that remembers every amount it
+// was asked to charge and fails with err if one is set
+type recordingProcessor struct {
+	name string
+	err  error
+
+	mu      sync.Mutex
+	amounts []float64
+}
+
+func (r *recordingProcessor) Process(amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.amounts = append(r.amounts, amount)
+	return r.err
+}
+
+func (r *recordingProcessor) GetName() string {
+	if r.name == "" {
+		return "Recording"
+	}
+	return r.name
+}
+
+// charged returns a copy of the amounts charged so far
+func (r *recordingProcessor) charged() []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]float64(nil), r.amounts...)
+}
+
+// fakeClock is a Clock that only moves when told to
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// register adds a payment type for the rest of the test
+func register(t *testing.T, pt PaymentType, create ProcessorConstructor, fields []FormField) {
+	t.Helper()
+	if err := RegisterProcessor(pt, create, fields); err != nil {
+		t.Fatalf("RegisterProcessor(%q): %v", pt, err)
+	}
+	t.Cleanup(func() { UnregisterProcessor(pt) })
+}
@@ -0,0 +1,15 @@
+package factory
+
+// Middleware wraps a processor with extra behavior and returns the result.
+// Every decorator in this package has a Middleware form, so they can be
+// stacked without nesting constructor calls by hand.
+type Middleware func(PaymentProcessor) PaymentProcessor
+
+// Chain wraps p with the middlewares so that the first one listed ends up
+// outermost: Chain(p, a, b) behaves like a(b(p)).
+func Chain(p PaymentProcessor, middlewares ...Middleware) PaymentProcessor {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		p = middlewares[i](p)
+	}
+	return p
+}
@@ -0,0 +1,138 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OrderConstraint places a middleware stage relative to another one.
+// Stages listed earlier wrap the ones after them, so "before" means "outside".
+type OrderConstraint struct {
+	other  string
+	before bool
+}
+
+// Before requires the stage to wrap (come before) the named stage
+func Before(name string) OrderConstraint {
+	return OrderConstraint{other: name, before: true}
+}
+
+// After requires the stage to be wrapped by (come after) the named stage
+func After(name string) OrderConstraint {
+	return OrderConstraint{other: name}
+}
+
+type middlewareStage struct {
+	name        string
+	middleware  Middleware
+	constraints []OrderConstraint
+}
+
+// MiddlewareBuilder assembles a middleware pipeline from named stages and
+// ordering rules, instead of relying on everyone getting a hand-written list
+// right. For example, idempotency has to wrap retry (or each retry would look
+// like a new payment), and retry has to wrap logging (so each attempt is logged):
+//
+//	chain, err := NewMiddlewareBuilder().
+//		Add("logging", Logging(nil)).
+//		Add("retry", Retry(3, time.Second), Before("logging")).
+//		Add("idempotency", idem, Before("retry")).
+//		Build()
+//	p := Chain(processor, chain...)
+//
+// Stages without constraints between them keep the order they were added in.
+type MiddlewareBuilder struct {
+	stages []middlewareStage
+	err    error
+}
+
+// NewMiddlewareBuilder returns an empty builder
+func NewMiddlewareBuilder() *MiddlewareBuilder {
+	return &MiddlewareBuilder{}
+}
+
+// Add appends a named stage. Problems such as a duplicate name are reported by Build.
+func (b *MiddlewareBuilder) Add(name string, m Middleware, constraints ...OrderConstraint) *MiddlewareBuilder {
+	switch {
+	case b.err != nil:
+	case name == "":
+		b.err = errors.New("middleware stage name must not be empty")
+	case m == nil:
+		b.err = fmt.Errorf("middleware stage %q is nil", name)
+	case b.index(name) >= 0:
+		b.err = fmt.Errorf("middleware stage %q added twice", name)
+	default:
+		b.stages = append(b.stages, middlewareStage{name: name, middleware: m, constraints: constraints})
+	}
+	return b
+}
+
+func (b *MiddlewareBuilder) index(name string) int {
+	for i, s := range b.stages {
+		if s.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Build orders the stages to satisfy every constraint and returns the
+// middlewares outermost first, ready for Chain. It fails if a constraint names
+// an unknown stage or the constraints contradict each other.
+func (b *MiddlewareBuilder) Build() ([]Middleware, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	// outer[i] lists the stages that must come after stage i
+	n := len(b.stages)
+	outer := make([][]int, n)
+	incoming := make([]int, n)
+	for i, s := range b.stages {
+		for _, c := range s.constraints {
+			j := b.index(c.other)
+			if j < 0 {
+				return nil, fmt.Errorf("middleware stage %q is ordered against unknown stage %q", s.name, c.other)
+			}
+			if j == i {
+				return nil, fmt.Errorf("middleware stage %q is ordered against itself", s.name)
+			}
+			from, to := i, j
+			if !c.before {
+				from, to = j, i
+			}
+			outer[from] = append(outer[from], to)
+			incoming[to]++
+		}
+	}
+
+	// Kahn's algorithm, always taking the earliest-added ready stage so
+	// unconstrained stages keep their insertion order
+	ordered := make([]Middleware, 0, n)
+	placed := make([]bool, n)
+	for len(ordered) < n {
+		next := -1
+		for i := 0; i < n; i++ {
+			if !placed[i] && incoming[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var stuck []string
+			for i, s := range b.stages {
+				if !placed[i] {
+					stuck = append(stuck, s.name)
+				}
+			}
+			return nil, fmt.Errorf("middleware ordering constraints form a cycle among: %s", strings.Join(stuck, ", "))
+		}
+		placed[next] = true
+		ordered = append(ordered, b.stages[next].middleware)
+		for _, to := range outer[next] {
+			incoming[to]--
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,124 @@
+package factory
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// stageOrder builds the pipeline, runs one payment through it and returns
+// the stage names in the order they ran, outermost first
+func stageOrder(t *testing.T, add func(b *MiddlewareBuilder, tag func(string) Middleware)) []string {
+	t.Helper()
+	var order []string
+	tag := func(name string) Middleware {
+		return func(p PaymentProcessor) PaymentProcessor {
+			return &middlewareFunc{inner: p, before: func() { order = append(order, name) }}
+		}
+	}
+	b := NewMiddlewareBuilder()
+	add(b, tag)
+	chain, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if err := Chain(&recordingProcessor{}, chain...).Process(10); err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func TestMiddlewareBuilderOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		add  func(b *MiddlewareBuilder, tag func(string) Middleware)
+		want []string
+	}{
+		{
+			name: "insertion order without constraints",
+			add: func(b *MiddlewareBuilder, tag func(string) Middleware) {
+				b.Add("a", tag("a")).Add("b", tag("b")).Add("c", tag("c"))
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "before moves a later stage outward",
+			add: func(b *MiddlewareBuilder, tag func(string) Middleware) {
+				b.Add("logging", tag("logging")).
+					Add("retry", tag("retry"), Before("logging")).
+					Add("idempotency", tag("idempotency"), Before("retry"))
+			},
+			want: []string{"idempotency", "retry", "logging"},
+		},
+		{
+			name: "after moves an earlier stage inward",
+			add: func(b *MiddlewareBuilder, tag func(string) Middleware) {
+				b.Add("logging", tag("logging"), After("retry")).
+					Add("retry", tag("retry")).
+					Add("metrics", tag("metrics"))
+			},
+			want: []string{"retry", "logging", "metrics"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stageOrder(t, tt.add); !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareBuilderErrors(t *testing.T) {
+	nop := func(p PaymentProcessor) PaymentProcessor { return p }
+	tests := []struct {
+		name string
+		b    *MiddlewareBuilder
+		want string
+	}{
+		{
+			name: "cycle",
+			b: NewMiddlewareBuilder().
+				Add("a", nop, Before("b")).
+				Add("b", nop, Before("c")).
+				Add("c", nop, Before("a")),
+			want: "cycle among: a, b, c",
+		},
+		{
+			name: "unknown stage",
+			b:    NewMiddlewareBuilder().Add("a", nop, After("missing")),
+			want: `unknown stage "missing"`,
+		},
+		{
+			name: "self reference",
+			b:    NewMiddlewareBuilder().Add("a", nop, Before("a")),
+			want: "against itself",
+		},
+		{
+			name: "duplicate",
+			b:    NewMiddlewareBuilder().Add("a", nop).Add("a", nop),
+			want: "added twice",
+		},
+		{
+			name: "empty name",
+			b:    NewMiddlewareBuilder().Add("", nop),
+			want: "must not be empty",
+		},
+		{
+			name: "nil middleware",
+			b:    NewMiddlewareBuilder().Add("a", nil),
+			want: "is nil",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := tt.b.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Build = %v, want an error containing %q", err, tt.want)
+			}
+			if chain != nil {
+				t.Errorf("Build returned %d middlewares alongside the error", len(chain))
+			}
+		})
+	}
+}
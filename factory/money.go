@@ -0,0 +1,83 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// ErrCurrencyMismatch is returned when combining amounts in different currencies
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an amount in a specific currency, stored in minor units (cents,
+// yen, ...) so arithmetic is exact. Money values can only be combined when
+// their currencies match, which rules out silently adding dollars to euros.
+type Money struct {
+	minor    int64
+	currency string
+}
+
+// NewMoney rounds amount to the currency's smallest unit
+func NewMoney(amount float64, currency string) Money {
+	currency = strings.ToUpper(currency)
+	return Money{minor: toMinor(amount, currency), currency: currency}
+}
+
+// Amount returns the value in major units, e.g. 12.5 for $12.50
+func (m Money) Amount() float64 { return fromMinor(m.minor, m.currency) }
+
+// Currency returns the ISO currency code
+func (m Money) Currency() string { return m.currency }
+
+// Equal reports whether both values have the same amount and currency
+func (m Money) Equal(other Money) bool {
+	return m.minor == other.minor && m.currency == other.currency
+}
+
+// IsZero reports whether the amount is zero, in any currency
+func (m Money) IsZero() bool { return m.minor == 0 }
+
+// Add returns m + other, or ErrCurrencyMismatch if the currencies differ
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, fmt.Errorf("%w: can't add %s to %s", ErrCurrencyMismatch, other.currency, m.currency)
+	}
+	return Money{minor: m.minor + other.minor, currency: m.currency}, nil
+}
+
+// Sub returns m - other, or ErrCurrencyMismatch if the currencies differ
+func (m Money) Sub(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, fmt.Errorf("%w: can't subtract %s from %s", ErrCurrencyMismatch, other.currency, m.currency)
+	}
+	return Money{minor: m.minor - other.minor, currency: m.currency}, nil
+}
+
+func (m Money) String() string {
+	return FormatAmount(m.Amount(), m.currency)
+}
+
+// Money returns the receipt's amount and currency as a Money value
+func (r *Receipt) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+// Equal reports whether two receipts record the same charge, field by field
+func (r *Receipt) Equal(other *Receipt) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	return r.TransactionID == other.TransactionID &&
+		r.RequestID == other.RequestID &&
+		r.Processor == other.Processor &&
+		r.Money().Equal(other.Money()) &&
+		r.Status == other.Status &&
+		r.Timestamp.Equal(other.Timestamp) &&
+		maps.Equal(r.Metadata, other.Metadata)
+}
+
+// IsZero reports whether the receipt is empty, i.e. records no transaction
+func (r *Receipt) IsZero() bool {
+	return r == nil || r.TransactionID == ""
+}
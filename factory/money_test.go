@@ -0,0 +1,83 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMoneyAddSub(t *testing.T) {
+	// 0.1 + 0.2 is exact in minor units
+	sum, err := NewMoney(0.1, "usd").Add(NewMoney(0.2, "USD"))
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if !sum.Equal(NewMoney(0.3, "USD")) {
+		t.Errorf("0.10 + 0.20 = %v, want $0.30", sum)
+	}
+
+	diff, err := sum.Sub(NewMoney(0.3, "USD"))
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+	if !diff.IsZero() {
+		t.Errorf("0.30 - 0.30 = %v, want zero", diff)
+	}
+}
+
+func TestMoneyCurrencyMismatch(t *testing.T) {
+	usd, eur := NewMoney(10, "USD"), NewMoney(10, "EUR")
+	if _, err := usd.Add(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add across currencies = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Sub(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sub across currencies = %v, want ErrCurrencyMismatch", err)
+	}
+	if usd.Equal(eur) {
+		t.Error("10 USD equals 10 EUR")
+	}
+}
+
+func TestMoneyIsZero(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  bool
+	}{
+		{Money{}, true},
+		{NewMoney(0, "EUR"), true},
+		{NewMoney(0.001, "USD"), true}, // rounds to zero cents
+		{NewMoney(0.01, "USD"), false},
+		{NewMoney(0.4, "JPY"), true}, // yen have no minor unit
+		{NewMoney(-5, "USD"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.money.IsZero(); got != tt.want {
+			t.Errorf("%v.IsZero() = %v, want %v", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestReceiptEqualIsZero(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	a := &Receipt{TransactionID: "txn_1", Processor: "PayPal", Amount: 12.5, Currency: "USD",
+		Status: StatusSucceeded, Timestamp: at, Metadata: map[string]string{"order": "1"}}
+	b := *a
+	b.Timestamp = at.In(time.FixedZone("CET", 3600))
+	b.Amount = 12.501 // same number of cents
+
+	if !a.Equal(&b) {
+		t.Error("receipts differing only by time zone and sub-cent noise are not equal")
+	}
+	b.Metadata = map[string]string{"order": "2"}
+	if a.Equal(&b) {
+		t.Error("receipts with different metadata are equal")
+	}
+
+	var nilReceipt *Receipt
+	if !nilReceipt.Equal(nil) || a.Equal(nil) {
+		t.Error("nil receipt comparison is wrong")
+	}
+	if !nilReceipt.IsZero() || !(&Receipt{}).IsZero() || a.IsZero() {
+		t.Error("IsZero is wrong")
+	}
+}
@@ -0,0 +1,51 @@
+package factory
+
+import "sync"
+
+// PaymentEvent describes the outcome of one payment
+type PaymentEvent struct {
+	Processor string
+	Amount    float64
+	Receipt   *Receipt // nil if the payment failed
+	Err       error
+}
+
+// PaymentObserver is called after a payment is attempted
+type PaymentObserver func(event PaymentEvent)
+
+// PaymentNotifier fans payment events out to subscribers, so receipts,
+// analytics and alerts can react to payments without the code that charges
+// knowing about any of them.
+type PaymentNotifier struct {
+	mu        sync.Mutex
+	observers []PaymentObserver
+}
+
+// NewPaymentNotifier returns a notifier with no subscribers
+func NewPaymentNotifier() *PaymentNotifier {
+	return &PaymentNotifier{}
+}
+
+// Subscribe registers fn for every future event.
+// Safe to call from any goroutine, including from inside an observer.
+func (n *PaymentNotifier) Subscribe(fn PaymentObserver) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.observers = append(n.observers, fn)
+}
+
+// Notify calls every subscriber with event. Like the singleton's connection
+// observers, the list is copied under the lock and called without it.
+// A nil notifier does nothing.
+func (n *PaymentNotifier) Notify(event PaymentEvent) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	snapshot := append([]PaymentObserver(nil), n.observers...)
+	n.mu.Unlock()
+
+	for _, fn := range snapshot {
+		fn(event)
+	}
+}
@@ -0,0 +1,168 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// OutboxEvent announces a successful payment to other systems
+type OutboxEvent struct {
+	Type          string // "payment.succeeded"
+	TransactionID string
+	Processor     string
+	Amount        float64
+	Currency      string
+	CreatedAt     time.Time
+}
+
+// Outbox stores events to be published later by a relay. Appends happen in
+// a transaction so they can be undone together with the work they describe.
+type Outbox interface {
+	Begin() (OutboxTx, error)
+}
+
+// OutboxTx is one outbox transaction. Nothing appended is visible until Commit.
+type OutboxTx interface {
+	Append(event OutboxEvent) error
+	Commit() error
+	Rollback() error
+}
+
+// ErrChargedNotRecorded is wrapped by the error returned when the charge went
+// through but its event couldn't be stored. The receipt is returned with it:
+// the money moved, only the announcement is missing.
+var ErrChargedNotRecorded = errors.New("payment charged but its outbox event was not recorded")
+
+// OutboxProcessor implements the transactional outbox pattern: publishing
+// "payment succeeded" straight to a message broker can fail after the charge
+// went through (or succeed for a charge that then fails), leaving the two
+// out of step. Instead, the event is written to an outbox in the same
+// transaction as the charge, and a separate relay publishes committed events.
+// If the charge fails, the transaction is rolled back and no event exists.
+//
+// A charge can't be undone by a rollback, though. If storing the event fails
+// after the charge succeeded, ProcessCtx returns the receipt together with an
+// error wrapping ErrChargedNotRecorded, so the caller knows not to charge
+// again and can record the event some other way.
+type OutboxProcessor struct {
+	inner  PaymentProcessor
+	outbox Outbox
+}
+
+// NewOutboxProcessor wraps inner, recording successful charges in outbox
+func NewOutboxProcessor(inner PaymentProcessor, outbox Outbox) *OutboxProcessor {
+	return &OutboxProcessor{inner: inner, outbox: outbox}
+}
+
+func (o *OutboxProcessor) Process(amount float64) error {
+	_, err := o.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+func (o *OutboxProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	tx, err := o.outbox.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := ProcessCtx(ctx, o.inner, amount)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return nil, errors.Join(err, rbErr)
+		}
+		return nil, err
+	}
+
+	err = tx.Append(OutboxEvent{
+		Type:          "payment.succeeded",
+		TransactionID: receipt.TransactionID,
+		Processor:     receipt.Processor,
+		Amount:        receipt.Amount,
+		Currency:      receipt.Currency,
+		CreatedAt:     clock.Now(),
+	})
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = errors.Join(err, rbErr)
+		}
+		return receipt, fmt.Errorf("%w: %s: %w", ErrChargedNotRecorded, receipt.TransactionID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return receipt, fmt.Errorf("%w: %s: %w", ErrChargedNotRecorded, receipt.TransactionID, err)
+	}
+	return receipt, nil
+}
+
+func (o *OutboxProcessor) GetName() string {
+	return o.inner.GetName()
+}
+
+func (o *OutboxProcessor) Details() map[string]string {
+	return detailsOf(o.inner)
+}
+
+func (o *OutboxProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(o.inner)
+}
+
+func (o *OutboxProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, o.inner)
+}
+
+// ErrTxDone is returned when a finished outbox transaction is used again
+var ErrTxDone = errors.New("outbox transaction already committed or rolled back")
+
+// MemoryOutbox is an in-memory Outbox for tests and demos
+type MemoryOutbox struct {
+	mu     sync.Mutex
+	events []OutboxEvent
+}
+
+// Begin starts a transaction
+func (m *MemoryOutbox) Begin() (OutboxTx, error) {
+	return &memoryTx{outbox: m}, nil
+}
+
+// Events returns the committed events, oldest first
+func (m *MemoryOutbox) Events() []OutboxEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]OutboxEvent(nil), m.events...)
+}
+
+type memoryTx struct {
+	outbox  *MemoryOutbox
+	pending []OutboxEvent
+	done    bool
+}
+
+func (t *memoryTx) Append(event OutboxEvent) error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.pending = append(t.pending, event)
+	return nil
+}
+
+func (t *memoryTx) Commit() error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+	t.outbox.mu.Lock()
+	defer t.outbox.mu.Unlock()
+	t.outbox.events = append(t.outbox.events, t.pending...)
+	return nil
+}
+
+func (t *memoryTx) Rollback() error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+	t.pending = nil
+	return nil
+}
@@ -0,0 +1,129 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOutboxStoresEventOnSuccess(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	SetClock(clock)
+	t.Cleanup(func() { SetClock(nil) })
+
+	outbox := &MemoryOutbox{}
+	receipt, err := NewOutboxProcessor(&recordingProcessor{name: "Card"}, outbox).ProcessCtx(t.Context(), 42)
+	if err != nil {
+		t.Fatalf("ProcessCtx: %v", err)
+	}
+
+	events := outbox.Events()
+	if len(events) != 1 {
+		t.Fatalf("outbox has %d events, want 1", len(events))
+	}
+	want := OutboxEvent{
+		Type:          "payment.succeeded",
+		TransactionID: receipt.TransactionID,
+		Processor:     "Card",
+		Amount:        42,
+		Currency:      DefaultCurrency,
+		CreatedAt:     clock.Now(),
+	}
+	if events[0] != want {
+		t.Errorf("event = %+v, want %+v", events[0], want)
+	}
+}
+
+func TestOutboxRollsBackOnFailure(t *testing.T) {
+	outbox := &MemoryOutbox{}
+	boom := errors.New("declined by issuer")
+	if err := NewOutboxProcessor(&recordingProcessor{err: boom}, outbox).Process(42); !errors.Is(err, boom) {
+		t.Fatalf("Process = %v, want the charge error", err)
+	}
+	if events := outbox.Events(); len(events) != 0 {
+		t.Errorf("outbox has %v after a failed charge", events)
+	}
+}
+
+// failingOutbox hands out transactions whose steps fail as configured
+type failingOutbox struct {
+	beginErr, appendErr, commitErr error
+	rolledBack                     bool
+}
+
+func (f *failingOutbox) Begin() (OutboxTx, error) {
+	if f.beginErr != nil {
+		return nil, f.beginErr
+	}
+	return f, nil
+}
+
+func (f *failingOutbox) Append(OutboxEvent) error { return f.appendErr }
+func (f *failingOutbox) Commit() error            { return f.commitErr }
+func (f *failingOutbox) Rollback() error {
+	f.rolledBack = true
+	return nil
+}
+
+func TestOutboxStoreFailures(t *testing.T) {
+	unavailable := errors.New("outbox unavailable")
+
+	inner := &recordingProcessor{}
+	if err := NewOutboxProcessor(inner, &failingOutbox{beginErr: unavailable}).Process(1); !errors.Is(err, unavailable) {
+		t.Errorf("Begin failure: Process = %v", err)
+	}
+	if len(inner.charged()) != 0 {
+		t.Error("charged although the outbox transaction couldn't start")
+	}
+
+	outbox := &failingOutbox{appendErr: unavailable}
+	if err := NewOutboxProcessor(&recordingProcessor{}, outbox).Process(1); !errors.Is(err, unavailable) || !outbox.rolledBack {
+		t.Errorf("Append failure: Process = %v, rolled back %v", err, outbox.rolledBack)
+	}
+	if err := NewOutboxProcessor(&recordingProcessor{}, &failingOutbox{commitErr: unavailable}).Process(1); !errors.Is(err, unavailable) {
+		t.Errorf("Commit failure: Process = %v", err)
+	}
+}
+
+func TestOutboxChargedButNotRecorded(t *testing.T) {
+	unavailable := errors.New("outbox unavailable")
+	for name, outbox := range map[string]*failingOutbox{
+		"append": {appendErr: unavailable},
+		"commit": {commitErr: unavailable},
+	} {
+		t.Run(name, func(t *testing.T) {
+			inner := &recordingProcessor{}
+			receipt, err := NewOutboxProcessor(inner, outbox).ProcessCtx(context.Background(), 25)
+			if !errors.Is(err, ErrChargedNotRecorded) || !errors.Is(err, unavailable) {
+				t.Errorf("ProcessCtx = %v, want ErrChargedNotRecorded wrapping the outbox error", err)
+			}
+			// The charge stands, so its receipt comes back with the error
+			if receipt == nil || receipt.Amount != 25 {
+				t.Errorf("receipt = %+v, want the charge's receipt", receipt)
+			}
+			if got := inner.charged(); len(got) != 1 {
+				t.Errorf("charged %v, want one charge", got)
+			}
+		})
+	}
+
+	// A failed charge is not confused with a missing event
+	boom := errors.New("declined by issuer")
+	if _, err := NewOutboxProcessor(&recordingProcessor{err: boom}, &MemoryOutbox{}).ProcessCtx(context.Background(), 1); errors.Is(err, ErrChargedNotRecorded) {
+		t.Errorf("failed charge reported as %v", err)
+	}
+}
+
+func TestMemoryOutboxTxDone(t *testing.T) {
+	tx, _ := (&MemoryOutbox{}).Begin()
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Append(OutboxEvent{}); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Append after Commit = %v, want ErrTxDone", err)
+	}
+	if err := tx.Rollback(); !errors.Is(err, ErrTxDone) {
+		t.Errorf("Rollback after Commit = %v, want ErrTxDone", err)
+	}
+}
@@ -0,0 +1,69 @@
+package factory
+
+import (
+	"context"
+	"maps"
+
+	"go-design-patterns/pool"
+)
+
+// ProcessorPool keeps up to max processors of one type and details, for
+// processors that are costly to create or must not be shared between
+// concurrent payments. Each payment borrows a processor and returns it.
+type ProcessorPool struct {
+	pool *pool.Pool[PaymentProcessor]
+	name string
+}
+
+// NewProcessorPool validates the details once by creating a processor, then
+// returns a pool that creates more of the same on demand
+func NewProcessorPool(paymentType PaymentType, details map[string]string, max int) (*ProcessorPool, error) {
+	first, err := CreatePaymentProcessor(paymentType, details)
+	if err != nil {
+		return nil, err
+	}
+
+	details = maps.Clone(details)
+	create := func() (PaymentProcessor, error) {
+		// The details were validated, and create interceptors consulted,
+		// above, so this only fails if a registration changed in between
+		p, err := createProcessor(paymentType, details)
+		if err != nil {
+			return nil, err
+		}
+		afterCreate(p)
+		return p, nil
+	}
+	return &ProcessorPool{pool: pool.New(create, max), name: first.GetName()}, nil
+}
+
+// Get borrows a processor, waiting if all of them are in use. It fails if
+// a new processor was needed and couldn't be created.
+func (p *ProcessorPool) Get() (PaymentProcessor, error) {
+	return p.pool.Get()
+}
+
+// Put returns a processor obtained from Get
+func (p *ProcessorPool) Put(processor PaymentProcessor) {
+	p.pool.Put(processor)
+}
+
+// Process borrows a processor for a single charge
+func (p *ProcessorPool) Process(amount float64) error {
+	_, err := p.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+// ProcessCtx is Process with a context, returning the borrowed processor's receipt
+func (p *ProcessorPool) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	processor, err := p.pool.GetCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer p.Put(processor)
+	return ProcessCtx(ctx, processor, amount)
+}
+
+func (p *ProcessorPool) GetName() string {
+	return p.name
+}
@@ -0,0 +1,75 @@
+package factory
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProcessorPool(t *testing.T) {
+	var created atomic.Int32
+	register(t, "pooled", func(map[string]string) (PaymentProcessor, error) {
+		created.Add(1)
+		return &recordingProcessor{name: "Pooled"}, nil
+	}, nil)
+
+	p, err := NewProcessorPool("pooled", nil, 3)
+	if err != nil {
+		t.Fatalf("NewProcessorPool: %v", err)
+	}
+	if p.GetName() != "Pooled" {
+		t.Errorf("GetName = %q", p.GetName())
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if err := p.Process(1); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// One processor to validate the details, then at most three in the pool
+	if got := created.Load(); got > 4 {
+		t.Errorf("created %d processors, want at most 4", got)
+	}
+}
+
+func TestProcessorPoolInvalidDetails(t *testing.T) {
+	if _, err := NewProcessorPool(PayPal, map[string]string{"email": "nope"}, 2); err == nil {
+		t.Error("NewProcessorPool accepted invalid details")
+	}
+}
+
+func TestProcessorPoolCreateError(t *testing.T) {
+	unavailable := errors.New("gateway unavailable")
+	var fail atomic.Bool
+	register(t, "flaky", func(map[string]string) (PaymentProcessor, error) {
+		if fail.Load() {
+			return nil, unavailable
+		}
+		return &recordingProcessor{name: "Flaky"}, nil
+	}, nil)
+
+	p, err := NewProcessorPool("flaky", nil, 1)
+	if err != nil {
+		t.Fatalf("NewProcessorPool: %v", err)
+	}
+	fail.Store(true)
+	if err := p.Process(1); !errors.Is(err, unavailable) {
+		t.Fatalf("Process = %v, want the create error", err)
+	}
+
+	// The failed create didn't use up the pool's only slot
+	fail.Store(false)
+	if err := p.Process(1); err != nil {
+		t.Errorf("Process after the create error recovered: %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrInvalidPrecision is returned for amounts finer than the currency's
+// smallest unit, like 99.999 USD or 10.5 JPY
+var ErrInvalidPrecision = errors.New("amount has more decimal places than the currency allows")
+
+// ValidatePrecision checks that amount is a whole number of the currency's
+// minor units: cents for USD, yen for JPY, fils for BHD. The built-in
+// processors run it on every charge rather than silently rounding, since
+// 99.999 almost always means a bug upstream. Use a RoundingProcessor in front
+// of them when rounding is what you want. NaN and infinities have no
+// number of minor units at all and are rejected too.
+func ValidatePrecision(amount float64, currency string) error {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return fmt.Errorf("%w: %v %s is not a finite amount", ErrInvalidPrecision, amount, currency)
+	}
+	decimals := minorUnitDecimals(strings.ToUpper(currency))
+	scaled := amount * math.Pow10(decimals)
+	// Tolerate float noise: 0.1+0.2 is a valid number of cents
+	if math.Abs(scaled-math.Round(scaled)) > 1e-6 {
+		return fmt.Errorf("%w: %v %s (at most %d)", ErrInvalidPrecision, amount, currency, decimals)
+	}
+	return nil
+}
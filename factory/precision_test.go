@@ -0,0 +1,72 @@
+package factory
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestValidatePrecision(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		wantErr  bool
+	}{
+		{99.99, "USD", false},
+		{99.999, "USD", true},
+		{99.9, "usd", false},
+		{0.1 + 0.2, "USD", false},
+		{1000, "JPY", false},
+		{10.5, "JPY", true},
+		{1.234, "BHD", false},
+		{1.2345, "BHD", true},
+		{99.99, "XYZ", false},
+		{math.NaN(), "USD", true},
+		{math.Inf(1), "USD", true},
+		{math.Inf(-1), "JPY", true},
+	}
+	for _, tt := range tests {
+		err := ValidatePrecision(tt.amount, tt.currency)
+		if tt.wantErr && !errors.Is(err, ErrInvalidPrecision) {
+			t.Errorf("ValidatePrecision(%v, %s) = %v, want ErrInvalidPrecision", tt.amount, tt.currency, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("ValidatePrecision(%v, %s) = %v, want nil", tt.amount, tt.currency, err)
+		}
+	}
+}
+
+func TestProcessRejectsSubCentAmounts(t *testing.T) {
+	p := &PayPalProcessor{email: "user@example.com"}
+	if err := p.Process(99.99); err != nil {
+		t.Errorf("Process(99.99) = %v", err)
+	}
+	if err := p.Process(99.999); !errors.Is(err, ErrInvalidPrecision) {
+		t.Errorf("Process(99.999) = %v, want ErrInvalidPrecision", err)
+	}
+}
+
+func TestProcessRequestPrecisionFollowsCurrency(t *testing.T) {
+	p := &PayPalProcessor{email: "user@example.com"}
+	tests := []struct {
+		amount   float64
+		currency string
+		wantErr  bool
+	}{
+		{1500, "JPY", false},
+		{1500.5, "JPY", true},
+		{12.345, "BHD", false},
+		{12.345, "EUR", true},
+	}
+	for _, tt := range tests {
+		receipt, err := p.ProcessRequest(&PaymentRequest{Amount: tt.amount, Currency: tt.currency})
+		switch {
+		case tt.wantErr && !errors.Is(err, ErrInvalidPrecision):
+			t.Errorf("ProcessRequest(%v %s) = %v, want ErrInvalidPrecision", tt.amount, tt.currency, err)
+		case tt.wantErr && receipt != nil:
+			t.Errorf("ProcessRequest(%v %s) issued a receipt for a rejected amount", tt.amount, tt.currency)
+		case !tt.wantErr && err != nil:
+			t.Errorf("ProcessRequest(%v %s) = %v", tt.amount, tt.currency, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Preflighter is implemented by processors that can check, cheaply and
+// without charging anything, that they are able to process payments:
+// credentials are usable, the provider is reachable and so on. Apps call
+// it at startup to fail fast instead of on the first real charge.
+type Preflighter interface {
+	Preflight(ctx context.Context) error
+}
+
+// preflightOf runs p's preflight check. Processors without one pass as long
+// as ctx is still live.
+func preflightOf(ctx context.Context, p PaymentProcessor) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if pf, ok := p.(Preflighter); ok {
+		return pf.Preflight(ctx)
+	}
+	return nil
+}
+
+// PreflightAll runs every processor's preflight check and reports all the
+// failures together, each prefixed with the processor's name. It returns
+// nil when every check passes.
+func PreflightAll(ctx context.Context, processors ...PaymentProcessor) error {
+	var errs []error
+	for _, p := range processors {
+		if err := preflightOf(ctx, p); err != nil {
+			errs = append(errs, fmt.Errorf("%s preflight: %w", p.GetName(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Preflight checks the card hasn't expired since the processor was created
+func (c *CreditCardProcessor) Preflight(ctx context.Context) error {
+	_, _, err := ValidateExpiry(strconv.Itoa(c.expMonth), strconv.Itoa(c.expYear), clock.Now())
+	return err
+}
+
+// Preflight simulates logging in to the PayPal account
+func (p *PayPalProcessor) Preflight(ctx context.Context) error {
+	logger.Printf("Preflight: PayPal account %s reachable\n", p.email)
+	return nil
+}
+
+// Preflight checks the balance source answers, when the processor relies on one
+func (b *BankTransferProcessor) Preflight(ctx context.Context) error {
+	if !b.checkBalance {
+		return nil
+	}
+	_, err := b.Balance()
+	return err
+}
+
+// Preflight fails with ErrSandboxFailure when forceError is set. A forced
+// decline passes: the processor is up, it just refuses charges.
+func (s *SandboxProcessor) Preflight(ctx context.Context) error {
+	if s.forceError {
+		return ErrSandboxFailure
+	}
+	return nil
+}
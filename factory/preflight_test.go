@@ -0,0 +1,122 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// preflightStub is a processor whose preflight check returns err
+type preflightStub struct {
+	recordingProcessor
+	err   error
+	calls int
+}
+
+func (p *preflightStub) Preflight(ctx context.Context) error {
+	p.calls++
+	return p.err
+}
+
+func TestPreflightAllPasses(t *testing.T) {
+	a := &preflightStub{recordingProcessor: recordingProcessor{name: "A"}}
+	plain := &recordingProcessor{name: "Plain"}
+
+	if err := PreflightAll(context.Background(), a, plain); err != nil {
+		t.Fatalf("PreflightAll = %v, want nil", err)
+	}
+	if a.calls != 1 {
+		t.Errorf("preflight ran %d times, want 1", a.calls)
+	}
+	if len(a.charged()) != 0 {
+		t.Errorf("preflight charged %v", a.charged())
+	}
+}
+
+func TestPreflightAllReportsEveryFailure(t *testing.T) {
+	errAuth := errors.New("bad credentials")
+	errDown := errors.New("provider unreachable")
+	ok := &preflightStub{recordingProcessor: recordingProcessor{name: "Ok"}}
+	auth := &preflightStub{recordingProcessor: recordingProcessor{name: "Auth"}, err: errAuth}
+	down := &preflightStub{recordingProcessor: recordingProcessor{name: "Down"}, err: errDown}
+
+	err := PreflightAll(context.Background(), auth, ok, down)
+	if !errors.Is(err, errAuth) || !errors.Is(err, errDown) {
+		t.Fatalf("PreflightAll = %v, want both failures", err)
+	}
+	for _, want := range []string{"Auth preflight: bad credentials", "Down preflight: provider unreachable"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "Ok") {
+		t.Errorf("error %q names a passing processor", err)
+	}
+	if ok.calls != 1 || down.calls != 1 {
+		t.Error("PreflightAll stopped at the first failure")
+	}
+}
+
+func TestPreflightAllCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stub := &preflightStub{recordingProcessor: recordingProcessor{name: "Stub"}}
+
+	if err := PreflightAll(ctx, stub, &recordingProcessor{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("PreflightAll = %v, want context.Canceled", err)
+	}
+	if stub.calls != 0 {
+		t.Error("preflight ran on a cancelled context")
+	}
+}
+
+func TestPreflightThroughDecorators(t *testing.T) {
+	errDown := errors.New("provider unreachable")
+	inner := &preflightStub{recordingProcessor: recordingProcessor{name: "Inner"}, err: errDown}
+	p := NewRetryProcessor(NewReconciledProcessor(inner, &MemorySink{}), 3, time.Millisecond)
+
+	if err := PreflightAll(context.Background(), p); !errors.Is(err, errDown) {
+		t.Errorf("PreflightAll through decorators = %v, want the inner failure", err)
+	}
+}
+
+func TestBuiltinPreflight(t *testing.T) {
+	SetClock(newFakeClock(time.Date(2030, time.June, 15, 0, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	valid := &CreditCardProcessor{cardNumber: "4111111111111111", expMonth: 12, expYear: 2030}
+	expired := &CreditCardProcessor{cardNumber: "4111111111111111", expMonth: 5, expYear: 2030}
+	sandbox, err := CreatePaymentProcessor(Sandbox, map[string]string{"forceError": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	declining, err := CreatePaymentProcessor(Sandbox, map[string]string{"forceDecline": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		p    PaymentProcessor
+		want error
+	}{
+		{"valid card", valid, nil},
+		{"expired card", expired, ErrCardExpired},
+		{"paypal", &PayPalProcessor{email: "user@example.com"}, nil},
+		{"sandbox error", sandbox, ErrSandboxFailure},
+		{"sandbox decline", declining, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := PreflightAll(context.Background(), tt.p)
+			if tt.want == nil && err != nil {
+				t.Errorf("PreflightAll = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("PreflightAll = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ReceiptStatus is the outcome recorded on a receipt
+type ReceiptStatus string
+
+const (
+	StatusSucceeded ReceiptStatus = "succeeded"
+	StatusFailed    ReceiptStatus = "failed"
+)
+
+// Receipt records a single charge made through a processor
+type Receipt struct {
+	TransactionID string
+	RequestID     string
+	Processor     string
+	Amount        float64
+	Currency      string
+	Status        ReceiptStatus
+	Timestamp     time.Time
+	Metadata      map[string]string
+}
+
+// ReceiptProcessor is implemented by processors that issue their own receipts
+type ReceiptProcessor interface {
+	PaymentProcessor
+	ProcessReceipt(amount float64) (*Receipt, error)
+}
+
+// Charge processes amount through p and returns a receipt for it.
+// Processors that issue their own receipts, or that pass on the receipt of the
+// processor they wrap, are asked for one; for the rest, a receipt is written
+// on their behalf after Process succeeds.
+func Charge(p PaymentProcessor, amount float64) (*Receipt, error) {
+	if rp, ok := p.(ReceiptProcessor); ok {
+		return rp.ProcessReceipt(amount)
+	}
+	if cp, ok := p.(ContextProcessor); ok {
+		return cp.ProcessCtx(context.Background(), amount)
+	}
+	if err := p.Process(amount); err != nil {
+		return nil, err
+	}
+	return newReceipt(p.GetName(), amount, DefaultCurrency), nil
+}
+
+var txnCounter atomic.Int64
+
+// newReceipt creates a successful receipt with a fresh transaction ID and
+// registers the capture in the ledger so it can be refunded later.
+func newReceipt(processor string, amount float64, currency string) *Receipt {
+	receipt := unrecordedReceipt(processor, amount, currency)
+	transactions.capture(receipt.TransactionID, amount, currency)
+	return receipt
+}
+
+// unrecordedReceipt creates a successful receipt that isn't in the ledger,
+// for charges whose money was captured under other transaction IDs.
+func unrecordedReceipt(processor string, amount float64, currency string) *Receipt {
+	return &Receipt{
+		TransactionID: fmt.Sprintf("txn_%06d", txnCounter.Add(1)),
+		Processor:     processor,
+		Amount:        amount,
+		Currency:      currency,
+		Status:        StatusSucceeded,
+		Timestamp:     clock.Now(),
+		Metadata:      make(map[string]string),
+	}
+}
@@ -0,0 +1,89 @@
+package factory
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// receiptJSON is the stored form of a Receipt:
+//
+//	{
+//	  "transaction_id": "txn_000042",
+//	  "request_id": "req-7",              // omitted when empty
+//	  "processor": "Credit Card",
+//	  "amount": "99.99",                  // string, in the currency's minor-unit precision
+//	  "currency": "USD",
+//	  "status": "succeeded",              // "succeeded" or "failed"
+//	  "timestamp": "2024-05-01T12:30:00Z", // RFC 3339, UTC
+//	  "metadata": {"attempts": "2"}       // omitted when empty
+//	}
+//
+// The amount is a string so that JSON consumers in other languages don't
+// round it through a binary float.
+type receiptJSON struct {
+	TransactionID string            `json:"transaction_id"`
+	RequestID     string            `json:"request_id,omitempty"`
+	Processor     string            `json:"processor"`
+	Amount        string            `json:"amount"`
+	Currency      string            `json:"currency"`
+	Status        ReceiptStatus     `json:"status"`
+	Timestamp     string            `json:"timestamp"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// MarshalJSON encodes the receipt in its documented, stable JSON shape
+func (r Receipt) MarshalJSON() ([]byte, error) {
+	decimals := 2
+	if format, ok := currencyFormats[r.Currency]; ok {
+		decimals = format.decimals
+	}
+	return json.Marshal(receiptJSON{
+		TransactionID: r.TransactionID,
+		RequestID:     r.RequestID,
+		Processor:     r.Processor,
+		Amount:        strconv.FormatFloat(r.Amount, 'f', decimals, 64),
+		Currency:      r.Currency,
+		Status:        r.Status,
+		Timestamp:     r.Timestamp.UTC().Format(time.RFC3339Nano),
+		Metadata:      r.Metadata,
+	})
+}
+
+// UnmarshalJSON decodes a receipt written by MarshalJSON
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	var raw receiptJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	amount, err := strconv.ParseFloat(raw.Amount, 64)
+	if err != nil {
+		return fmt.Errorf("receipt amount %q: %w", raw.Amount, err)
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, raw.Timestamp)
+	if err != nil {
+		return fmt.Errorf("receipt timestamp: %w", err)
+	}
+	switch raw.Status {
+	case StatusSucceeded, StatusFailed:
+	default:
+		return fmt.Errorf("unknown receipt status %q", raw.Status)
+	}
+
+	*r = Receipt{
+		TransactionID: raw.TransactionID,
+		RequestID:     raw.RequestID,
+		Processor:     raw.Processor,
+		Amount:        amount,
+		Currency:      raw.Currency,
+		Status:        raw.Status,
+		Timestamp:     timestamp,
+		Metadata:      raw.Metadata,
+	}
+	if r.Metadata == nil {
+		r.Metadata = make(map[string]string)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package factory
+
+import (
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleReceipt() Receipt {
+	return Receipt{
+		TransactionID: "txn_000042",
+		RequestID:     "req-7",
+		Processor:     "Credit Card",
+		Amount:        99.99,
+		Currency:      "USD",
+		Status:        StatusSucceeded,
+		Timestamp:     time.Date(2024, time.May, 1, 14, 30, 0, 123000000, time.FixedZone("CEST", 2*60*60)),
+		Metadata:      map[string]string{"attempts": "2"},
+	}
+}
+
+func TestReceiptJSONRoundTrip(t *testing.T) {
+	tests := map[string]Receipt{
+		"full":    sampleReceipt(),
+		"minimal": {TransactionID: "txn_1", Processor: "PayPal", Amount: 5, Currency: "EUR", Status: StatusFailed, Timestamp: time.Unix(0, 0).UTC(), Metadata: map[string]string{}},
+		"yen":     {TransactionID: "txn_2", Processor: "Bank", Amount: 1500, Currency: "JPY", Status: StatusSucceeded, Timestamp: time.Unix(1700000000, 0).UTC(), Metadata: map[string]string{}},
+	}
+	for name, original := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(original)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var decoded Receipt
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", data, err)
+			}
+			if !decoded.Timestamp.Equal(original.Timestamp) {
+				t.Errorf("Timestamp = %v, want %v", decoded.Timestamp, original.Timestamp)
+			}
+			decoded.Timestamp = original.Timestamp
+			if decoded.TransactionID != original.TransactionID || decoded.RequestID != original.RequestID ||
+				decoded.Processor != original.Processor || decoded.Amount != original.Amount ||
+				decoded.Currency != original.Currency || decoded.Status != original.Status ||
+				!maps.Equal(decoded.Metadata, original.Metadata) {
+				t.Errorf("round trip gave %+v, want %+v", decoded, original)
+			}
+		})
+	}
+}
+
+func TestReceiptJSONShape(t *testing.T) {
+	data, err := json.Marshal(sampleReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if fields["amount"] != "99.99" {
+		t.Errorf("amount = %#v, want the string \"99.99\"", fields["amount"])
+	}
+	if fields["status"] != "succeeded" {
+		t.Errorf("status = %#v, want \"succeeded\"", fields["status"])
+	}
+	timestamp, _ := fields["timestamp"].(string)
+	if timestamp != "2024-05-01T12:30:00.123Z" {
+		t.Errorf("timestamp = %q, want RFC 3339 in UTC", timestamp)
+	}
+	if _, err := time.Parse(time.RFC3339, timestamp); err != nil {
+		t.Errorf("timestamp %q isn't RFC 3339: %v", timestamp, err)
+	}
+
+	minimal, _ := json.Marshal(Receipt{Currency: "JPY", Amount: 1500, Status: StatusSucceeded})
+	if strings.Contains(string(minimal), "request_id") || strings.Contains(string(minimal), "metadata") {
+		t.Errorf("empty optional fields weren't omitted: %s", minimal)
+	}
+	if !strings.Contains(string(minimal), `"amount":"1500"`) {
+		t.Errorf("JPY amount should have no decimals: %s", minimal)
+	}
+}
+
+func TestReceiptUnmarshalRejects(t *testing.T) {
+	tests := map[string]string{
+		"bad amount":    `{"amount": "lots", "status": "succeeded", "timestamp": "2024-05-01T12:30:00Z"}`,
+		"bad timestamp": `{"amount": "1.00", "status": "succeeded", "timestamp": "yesterday"}`,
+		"bad status":    `{"amount": "1.00", "status": "pending", "timestamp": "2024-05-01T12:30:00Z"}`,
+		"not an object": `[1, 2]`,
+	}
+	for name, data := range tests {
+		var r Receipt
+		if err := json.Unmarshal([]byte(data), &r); err == nil {
+			t.Errorf("%s: Unmarshal succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// ReconciliationSink stores receipts so they can later be matched against
+// the payment provider's settlement reports
+type ReconciliationSink interface {
+	Record(receipt Receipt) error
+}
+
+// ReconciledProcessor sends every successful receipt to a reconciliation
+// sink. The customer has already been charged by then, so a sink outage must
+// not fail the payment: receipts the sink rejects are buffered and retried,
+// oldest first, before each later receipt and on Flush.
+//
+// The buffer holds at most DefaultMaxPendingReceipts receipts (see
+// WithMaxPending). During a long outage the oldest are dropped to make room,
+// and counted in Dropped.
+type ReconciledProcessor struct {
+	inner PaymentProcessor
+	sink  ReconciliationSink
+
+	// flushMu lets one Flush talk to the sink at a time, so receipts still
+	// arrive in order while mu is free for payments to buffer theirs
+	flushMu sync.Mutex
+
+	mu         sync.Mutex
+	pending    []Receipt
+	maxPending int
+	dropped    int
+}
+
+// DefaultMaxPendingReceipts is how many receipts a ReconciledProcessor
+// buffers while its sink is down, unless WithMaxPending says otherwise
+const DefaultMaxPendingReceipts = 1000
+
+// NewReconciledProcessor wraps inner, recording its receipts in sink
+func NewReconciledProcessor(inner PaymentProcessor, sink ReconciliationSink) *ReconciledProcessor {
+	return &ReconciledProcessor{inner: inner, sink: sink, maxPending: DefaultMaxPendingReceipts}
+}
+
+// WithMaxPending sets how many receipts are buffered while the sink is down.
+// A value below 1 is treated as 1.
+func (r *ReconciledProcessor) WithMaxPending(n int) *ReconciledProcessor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxPending = max(n, 1)
+	r.trimLocked()
+	return r
+}
+
+func (r *ReconciledProcessor) Process(amount float64) error {
+	_, err := r.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+func (r *ReconciledProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	receipt, err := ProcessCtx(ctx, r.inner, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.pending = append(r.pending, *receipt)
+	r.trimLocked()
+	r.mu.Unlock()
+	if err := r.Flush(); err != nil {
+		logger.Printf("reconciliation sink unavailable, %d receipt(s) buffered: %v\n", r.Pending(), err)
+	}
+	return receipt, nil
+}
+
+// Flush retries the buffered receipts in order, stopping at the first one the
+// sink rejects so receipts reach the sink in the order they were issued.
+// The sink is called without holding the buffer, so a slow sink doesn't
+// hold up payments buffering their receipts meanwhile.
+func (r *ReconciledProcessor) Flush() error {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	r.mu.Lock()
+	batch := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	for i, receipt := range batch {
+		if err := r.sink.Record(receipt); err != nil {
+			// Put the rest back ahead of anything buffered since
+			r.mu.Lock()
+			r.pending = slices.Concat(batch[i:], r.pending)
+			r.trimLocked()
+			r.mu.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+// trimLocked drops the oldest buffered receipts beyond maxPending.
+// The caller must hold r.mu.
+func (r *ReconciledProcessor) trimLocked() {
+	if over := len(r.pending) - r.maxPending; over > 0 {
+		logger.Printf("reconciliation buffer full, dropping %d oldest receipt(s)\n", over)
+		r.pending = append([]Receipt(nil), r.pending[over:]...)
+		r.dropped += over
+	}
+}
+
+// Dropped returns how many receipts were dropped because the buffer was full
+func (r *ReconciledProcessor) Dropped() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dropped
+}
+
+// Pending returns how many receipts are waiting to be recorded
+func (r *ReconciledProcessor) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending)
+}
+
+func (r *ReconciledProcessor) GetName() string {
+	return r.inner.GetName()
+}
+
+func (r *ReconciledProcessor) Details() map[string]string {
+	return detailsOf(r.inner)
+}
+
+func (r *ReconciledProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(r.inner)
+}
+
+func (r *ReconciledProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, r.inner)
+}
+
+// ErrSinkUnavailable is what MemorySink returns while failing on purpose
+var ErrSinkUnavailable = errors.New("reconciliation sink unavailable")
+
+// MemorySink is an in-memory ReconciliationSink for tests and demos
+type MemorySink struct {
+	mu       sync.Mutex
+	receipts []Receipt
+	failures int
+}
+
+// FailNext makes the next n Record calls fail with ErrSinkUnavailable
+func (m *MemorySink) FailNext(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures = n
+}
+
+func (m *MemorySink) Record(receipt Receipt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failures > 0 {
+		m.failures--
+		return ErrSinkUnavailable
+	}
+	m.receipts = append(m.receipts, receipt)
+	return nil
+}
+
+// Receipts returns the recorded receipts, oldest first
+func (m *MemorySink) Receipts() []Receipt {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Receipt(nil), m.receipts...)
+}
@@ -0,0 +1,203 @@
+package factory
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiptAmounts lists the amounts of the receipts, in order
+func receiptAmounts(receipts []Receipt) []float64 {
+	amounts := make([]float64, len(receipts))
+	for i, r := range receipts {
+		amounts[i] = r.Amount
+	}
+	return amounts
+}
+
+func TestReconciledProcessorRecordsReceipts(t *testing.T) {
+	sink := &MemorySink{}
+	p := NewReconciledProcessor(&recordingProcessor{name: "Recording"}, sink)
+
+	for _, amount := range []float64{10, 20} {
+		if err := p.Process(amount); err != nil {
+			t.Fatalf("Process(%v): %v", amount, err)
+		}
+	}
+
+	got := sink.Receipts()
+	if len(got) != 2 || got[0].Amount != 10 || got[1].Amount != 20 {
+		t.Fatalf("sink receipts = %v, want amounts [10 20]", receiptAmounts(got))
+	}
+	if got[0].Processor != "Recording" || got[0].TransactionID == "" {
+		t.Errorf("receipt = %+v, want the processor name and a transaction ID", got[0])
+	}
+	if p.Pending() != 0 {
+		t.Errorf("Pending = %d, want 0", p.Pending())
+	}
+}
+
+func TestReconciledProcessorSkipsFailedPayments(t *testing.T) {
+	sink := &MemorySink{}
+	declined := errors.New("declined")
+	p := NewReconciledProcessor(&recordingProcessor{err: declined}, sink)
+
+	if err := p.Process(10); !errors.Is(err, declined) {
+		t.Fatalf("Process = %v, want the inner error", err)
+	}
+	if n := len(sink.Receipts()); n != 0 || p.Pending() != 0 {
+		t.Errorf("failed payment reached the sink (%d recorded, %d pending)", n, p.Pending())
+	}
+}
+
+func TestReconciledProcessorSinkFailure(t *testing.T) {
+	log := captureLog(t)
+	sink := &MemorySink{}
+	inner := &recordingProcessor{}
+	p := NewReconciledProcessor(inner, sink)
+
+	sink.FailNext(2)
+	for _, amount := range []float64{10, 20} {
+		if err := p.Process(amount); err != nil {
+			t.Fatalf("Process(%v) with the sink down = %v, want the payment to succeed", amount, err)
+		}
+	}
+	if len(inner.charged()) != 2 {
+		t.Fatalf("charged %v, want both payments", inner.charged())
+	}
+	if p.Pending() != 2 || len(sink.Receipts()) != 0 {
+		t.Fatalf("Pending = %d, recorded = %d, want 2 buffered and none recorded", p.Pending(), len(sink.Receipts()))
+	}
+	if !strings.Contains(log.String(), "2 receipt(s) buffered") {
+		t.Errorf("log = %q, want the outage reported", log.String())
+	}
+
+	// The next payment retries the backlog first, keeping issue order
+	if err := p.Process(30); err != nil {
+		t.Fatal(err)
+	}
+	if got := receiptAmounts(sink.Receipts()); len(got) != 3 || got[0] != 10 || got[1] != 20 || got[2] != 30 {
+		t.Errorf("sink receipts = %v, want [10 20 30]", got)
+	}
+	if p.Pending() != 0 {
+		t.Errorf("Pending = %d after recovery, want 0", p.Pending())
+	}
+}
+
+func TestReconciledProcessorFlush(t *testing.T) {
+	sink := &MemorySink{}
+	p := NewReconciledProcessor(&recordingProcessor{}, sink)
+
+	sink.FailNext(3)
+	for _, amount := range []float64{10, 20} {
+		if err := p.Process(amount); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Two failures were used by the payments; Flush stops at the third
+	if err := p.Flush(); !errors.Is(err, ErrSinkUnavailable) {
+		t.Fatalf("Flush = %v, want ErrSinkUnavailable", err)
+	}
+	if p.Pending() != 2 {
+		t.Fatalf("Pending = %d after a failed Flush, want 2", p.Pending())
+	}
+	if err := p.Flush(); err != nil {
+		t.Fatalf("Flush = %v, want the sink to accept the backlog", err)
+	}
+	if got := receiptAmounts(sink.Receipts()); len(got) != 2 || got[0] != 10 || got[1] != 20 {
+		t.Errorf("sink receipts = %v, want [10 20]", got)
+	}
+}
+
+func TestReconciledProcessorConcurrent(t *testing.T) {
+	sink := &MemorySink{}
+	p := NewReconciledProcessor(&recordingProcessor{}, sink)
+	sink.FailNext(5)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := p.Process(1); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(sink.Receipts()); n != 20 {
+		t.Errorf("recorded %d receipts, want 20", n)
+	}
+}
+
+func TestReconciledProcessorBoundedBuffer(t *testing.T) {
+	captureLog(t)
+	sink := &MemorySink{}
+	p := NewReconciledProcessor(&recordingProcessor{}, sink).WithMaxPending(2)
+
+	sink.FailNext(4)
+	for _, amount := range []float64{10, 20, 30} {
+		if err := p.Process(amount); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The buffer keeps the newest receipts
+	if p.Pending() != 2 || p.Dropped() != 1 {
+		t.Fatalf("Pending = %d, Dropped = %d, want 2 and 1", p.Pending(), p.Dropped())
+	}
+	sink.FailNext(0)
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := receiptAmounts(sink.Receipts()); len(got) != 2 || got[0] != 20 || got[1] != 30 {
+		t.Errorf("sink receipts = %v, want [20 30]", got)
+	}
+}
+
+// blockingSink holds every Record until release is closed
+type blockingSink struct {
+	MemorySink
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingSink) Record(receipt Receipt) error {
+	b.entered <- struct{}{}
+	<-b.release
+	return b.MemorySink.Record(receipt)
+}
+
+func TestReconciledProcessorFlushDoesNotHoldBuffer(t *testing.T) {
+	sink := &blockingSink{entered: make(chan struct{}, 10), release: make(chan struct{})}
+	p := NewReconciledProcessor(&recordingProcessor{}, sink)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := p.Process(10); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-sink.entered
+
+	// With the sink stuck, the buffer is still usable
+	pending := make(chan int)
+	go func() { pending <- p.Pending() }()
+	select {
+	case <-pending:
+	case <-time.After(time.Second):
+		t.Fatal("Pending blocked while the sink was being called")
+	}
+
+	close(sink.release)
+	<-done
+	if got := receiptAmounts(sink.Receipts()); len(got) != 1 || got[0] != 10 {
+		t.Errorf("sink receipts = %v, want [10]", got)
+	}
+}
@@ -0,0 +1,131 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+var (
+	// ErrUnknownTransaction is returned when refunding a transaction ID that was never captured
+	ErrUnknownTransaction = errors.New("unknown transaction")
+	// ErrRefundExceedsCapture is returned when a refund is larger than what remains to refund
+	ErrRefundExceedsCapture = errors.New("refund exceeds remaining captured amount")
+)
+
+// ledger tracks captured transactions and how much of each has been refunded.
+// Amounts are stored in minor units (cents) so repeated partial refunds can't
+// drift through float rounding.
+//
+// It keeps at most max transactions; once full, capturing another drops the
+// oldest, which can no longer be refunded through this package.
+type ledger struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]*ledgerEntry
+	order   []string // transaction IDs, oldest first
+}
+
+type ledgerEntry struct {
+	currency string
+	captured int64
+	refunded int64
+}
+
+// maxLedgerEntries is how many captured transactions stay refundable
+const maxLedgerEntries = 10000
+
+var transactions = newLedger(maxLedgerEntries)
+
+func newLedger(max int) *ledger {
+	return &ledger{max: max, entries: make(map[string]*ledgerEntry)}
+}
+
+func (l *ledger) capture(txnID string, amount float64, currency string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.entries[txnID]; !ok {
+		if len(l.order) >= l.max {
+			delete(l.entries, l.order[0])
+			l.order = l.order[1:]
+		}
+		l.order = append(l.order, txnID)
+	}
+	l.entries[txnID] = &ledgerEntry{currency: currency, captured: toMinor(amount, currency)}
+}
+
+// RefundPartial refunds part of a captured transaction. Several partial
+// refunds may be made as long as their total doesn't exceed the capture.
+// Only the most recent maxLedgerEntries captures can be refunded; older ones
+// report ErrUnknownTransaction.
+func RefundPartial(txnID string, amount float64) error {
+	if amount <= 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return errors.New("refund amount must be a positive, finite number")
+	}
+
+	transactions.mu.Lock()
+	defer transactions.mu.Unlock()
+
+	entry, ok := transactions.entries[txnID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTransaction, txnID)
+	}
+	// Compare before converting: an amount far above the capture would
+	// overflow int64 in minor units and could pass the check below
+	if amount > fromMinor(entry.captured, entry.currency) {
+		return fmt.Errorf("%w: %s remaining", ErrRefundExceedsCapture,
+			FormatAmount(fromMinor(entry.captured-entry.refunded, entry.currency), entry.currency))
+	}
+	minor := toMinor(amount, entry.currency)
+	if minor == 0 {
+		return fmt.Errorf("refund amount %v is smaller than one %s minor unit", amount, entry.currency)
+	}
+	if entry.refunded+minor > entry.captured {
+		return fmt.Errorf("%w: %s remaining", ErrRefundExceedsCapture,
+			FormatAmount(fromMinor(entry.captured-entry.refunded, entry.currency), entry.currency))
+	}
+	entry.refunded += minor
+	logger.Printf("Refunded %s of transaction %s\n", FormatAmount(amount, entry.currency), txnID)
+	return nil
+}
+
+// Refund refunds whatever remains of a captured transaction
+func Refund(txnID string) error {
+	_, remaining, err := RefundStatus(txnID)
+	if err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return fmt.Errorf("%w: nothing left to refund", ErrRefundExceedsCapture)
+	}
+	return RefundPartial(txnID, remaining)
+}
+
+// RefundStatus returns how much of a transaction has been refunded and how much remains
+func RefundStatus(txnID string) (refunded, remaining float64, err error) {
+	transactions.mu.Lock()
+	defer transactions.mu.Unlock()
+
+	entry, ok := transactions.entries[txnID]
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %s", ErrUnknownTransaction, txnID)
+	}
+	return fromMinor(entry.refunded, entry.currency), fromMinor(entry.captured-entry.refunded, entry.currency), nil
+}
+
+// minorUnitDecimals returns how many decimal places the currency's smallest unit has
+func minorUnitDecimals(currency string) int {
+	if format, ok := currencyFormats[currency]; ok {
+		return format.decimals
+	}
+	return 2
+}
+
+func toMinor(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(minorUnitDecimals(currency))))
+}
+
+func fromMinor(minor int64, currency string) float64 {
+	return float64(minor) / math.Pow10(minorUnitDecimals(currency))
+}
@@ -0,0 +1,151 @@
+package factory
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// captured charges amount through a PayPal processor and returns the transaction ID
+func captured(t *testing.T, amount float64) string {
+	t.Helper()
+	p := &PayPalProcessor{email: "user@example.com"}
+	receipt, err := p.ProcessReceipt(amount)
+	if err != nil {
+		t.Fatalf("ProcessReceipt(%v): %v", amount, err)
+	}
+	return receipt.TransactionID
+}
+
+func TestRefundPartialSumsToCapture(t *testing.T) {
+	txn := captured(t, 100)
+
+	// Thirds don't divide evenly in floats; the ledger works in cents
+	for _, amount := range []float64{33.33, 33.33, 33.34} {
+		if err := RefundPartial(txn, amount); err != nil {
+			t.Fatalf("RefundPartial(%v): %v", amount, err)
+		}
+	}
+	refunded, remaining, err := RefundStatus(txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refunded != 100 || remaining != 0 {
+		t.Errorf("RefundStatus = %v refunded, %v remaining, want 100 and 0", refunded, remaining)
+	}
+	if err := Refund(txn); !errors.Is(err, ErrRefundExceedsCapture) {
+		t.Errorf("Refund after full refund = %v, want ErrRefundExceedsCapture", err)
+	}
+}
+
+func TestRefundPartialOverRefund(t *testing.T) {
+	txn := captured(t, 50)
+
+	if err := RefundPartial(txn, 30); err != nil {
+		t.Fatal(err)
+	}
+	if err := RefundPartial(txn, 20.01); !errors.Is(err, ErrRefundExceedsCapture) {
+		t.Errorf("over-refund = %v, want ErrRefundExceedsCapture", err)
+	}
+	// The rejected refund leaves the balance alone
+	if _, remaining, _ := RefundStatus(txn); remaining != 20 {
+		t.Errorf("remaining = %v, want 20", remaining)
+	}
+	if err := Refund(txn); err != nil {
+		t.Errorf("Refund of the remainder: %v", err)
+	}
+}
+
+func TestRefundPartialRejects(t *testing.T) {
+	if err := RefundPartial("txn_missing", 5); !errors.Is(err, ErrUnknownTransaction) {
+		t.Errorf("unknown transaction = %v, want ErrUnknownTransaction", err)
+	}
+	txn := captured(t, 10)
+	for _, amount := range []float64{0, -1, math.NaN(), math.Inf(1), 1e300, 0.001} {
+		if err := RefundPartial(txn, amount); err == nil {
+			t.Errorf("RefundPartial(%v) succeeded", amount)
+		}
+	}
+}
+
+func TestLedgerEvictsOldest(t *testing.T) {
+	l := newLedger(2)
+	l.capture("txn_a", 1, DefaultCurrency)
+	l.capture("txn_b", 2, DefaultCurrency)
+	l.capture("txn_a", 3, DefaultCurrency) // recapturing doesn't take another slot
+	l.capture("txn_c", 4, DefaultCurrency)
+
+	if _, ok := l.entries["txn_a"]; ok {
+		t.Error("oldest transaction still in a full ledger")
+	}
+	if len(l.entries) != 2 || l.entries["txn_b"] == nil || l.entries["txn_c"] == nil {
+		t.Errorf("entries = %v, want txn_b and txn_c", l.entries)
+	}
+}
+
+// TestChargeThroughDecoratorsCapturesOnce checks that charging a decorator
+// registers only the transaction made by the processor it wraps, so the same
+// money can't be refunded twice.
+func TestChargeThroughDecoratorsCapturesOnce(t *testing.T) {
+	newInner := func() PaymentProcessor { return &PayPalProcessor{email: "user@example.com"} }
+	installments, err := NewInstallmentProcessor(newInner(), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewProcessorPool(PayPal, map[string]string{"email": "user@example.com"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weighted, err := NewWeightedRouter([]WeightedProcessor{{Processor: newInner(), Weight: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	never := FraudScorerFunc(func(float64, map[string]string) float64 { return 0 })
+
+	decorators := map[string]PaymentProcessor{
+		"daily limit": NewDailyLimitProcessor(newInner(), 1000, nil),
+		"velocity":    NewVelocityProcessor(newInner(), 10, time.Minute, nil),
+		"fraud check": NewFraudCheckProcessor(newInner(), never, 0.5),
+		"router":      NewRouter(newInner()),
+		"weighted":    weighted,
+		"pool":        pool,
+		"installment": installments,
+		"converting":  NewConvertingProcessor(newInner(), FixedRates{}, DefaultCurrency),
+		"logging":     NewLoggingProcessor(newInner(), &bufferLogger{}),
+	}
+	for name, p := range decorators {
+		t.Run(name, func(t *testing.T) {
+			before := txnCounter.Load()
+			receipt, err := Charge(p, 40)
+			if err != nil {
+				t.Fatalf("Charge: %v", err)
+			}
+			if captures := txnCounter.Load() - before; captures != 1 {
+				t.Errorf("Charge made %d transactions, want 1", captures)
+			}
+			if err := Refund(receipt.TransactionID); err != nil {
+				t.Errorf("Refund(%s): %v", receipt.TransactionID, err)
+			}
+		})
+	}
+}
+
+func TestSplitReceiptNamesLegs(t *testing.T) {
+	split, err := NewSplitProcessor(&PayPalProcessor{email: "platform@example.com"}, &PayPalProcessor{email: "seller@example.com"}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := Charge(split, 100)
+	if err != nil {
+		t.Fatalf("Charge: %v", err)
+	}
+	if err := Refund(receipt.TransactionID); !errors.Is(err, ErrUnknownTransaction) {
+		t.Errorf("Refund of the combined receipt = %v, want ErrUnknownTransaction", err)
+	}
+	for _, key := range []string{"seller_transaction", "platform_transaction"} {
+		if err := Refund(receipt.Metadata[key]); err != nil {
+			t.Errorf("Refund of %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ProcessorConstructor builds a processor from the details the caller supplied
+type ProcessorConstructor func(details map[string]string) (PaymentProcessor, error)
+
+// registration is everything the factory knows about a custom payment type
+type registration struct {
+	create ProcessorConstructor
+	fields []FormField
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[PaymentType]registration)
+)
+
+// ErrNotRegistered is returned when unregistering a type that was never registered
+var ErrNotRegistered = errors.New("payment type is not registered")
+
+// RegisterProcessor teaches the factory a new payment type without editing
+// CreatePaymentProcessor. fields describes the details the type expects, so
+// it shows up in PaymentFormSchema like the built-in types do.
+//
+// Registrations take precedence over the built-in types, so registering
+// CreditCard replaces the built-in card processor until UnregisterProcessor
+// is called. Registering a type again replaces the earlier registration.
+func RegisterProcessor(t PaymentType, create ProcessorConstructor, fields []FormField) error {
+	if t == "" {
+		return errors.New("payment type must not be empty")
+	}
+	if create == nil {
+		return errors.New("constructor must not be nil")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[t] = registration{
+		create: create,
+		fields: append([]FormField(nil), fields...),
+	}
+	return nil
+}
+
+// UnregisterProcessor removes a registration. A built-in type goes back to
+// its built-in processor; any other type becomes unknown to the factory.
+func UnregisterProcessor(t PaymentType) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[t]; !ok {
+		return fmt.Errorf("%w: %q", ErrNotRegistered, t)
+	}
+	delete(registry, t)
+	return nil
+}
+
+// RegisteredTypes returns the payment types added with RegisterProcessor,
+// sorted. It is a copy taken under the registry lock, so it's safe to range
+// over while other goroutines register and unregister types, and it doesn't
+// change when they do. Built-in types appear only if a registration
+// overrides them.
+func RegisteredTypes() []PaymentType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return slices.Sorted(maps.Keys(registry))
+}
+
+func lookupRegistration(t PaymentType) (registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	reg, ok := registry[t]
+	return reg, ok
+}
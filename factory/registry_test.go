@@ -0,0 +1,201 @@
+package factory
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRegisterOverridesBuiltin(t *testing.T) {
+	stub := &recordingProcessor{name: "Stub Card"}
+	register(t, CreditCard, func(map[string]string) (PaymentProcessor, error) { return stub, nil }, nil)
+
+	p, err := CreatePaymentProcessor(CreditCard, nil)
+	if err != nil {
+		t.Fatalf("CreatePaymentProcessor: %v", err)
+	}
+	if p != stub {
+		t.Errorf("got %T, want the registered processor", p)
+	}
+	if !slices.Contains(RegisteredTypes(), CreditCard) {
+		t.Error("an overridden built-in is missing from RegisteredTypes")
+	}
+}
+
+func TestReregisterReplaces(t *testing.T) {
+	first, second := &recordingProcessor{name: "First"}, &recordingProcessor{name: "Second"}
+	register(t, "rereg", func(map[string]string) (PaymentProcessor, error) { return first, nil }, nil)
+	register(t, "rereg", func(map[string]string) (PaymentProcessor, error) { return second, nil }, nil)
+
+	if p, _ := CreatePaymentProcessor("rereg", nil); p != second {
+		t.Errorf("got %v, want the later registration", p)
+	}
+}
+
+func TestUnregisterFallsBackToBuiltin(t *testing.T) {
+	if err := RegisterProcessor(PayPal, func(map[string]string) (PaymentProcessor, error) {
+		return &recordingProcessor{}, nil
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnregisterProcessor(PayPal); err != nil {
+		t.Fatalf("UnregisterProcessor: %v", err)
+	}
+
+	p, err := CreatePaymentProcessor(PayPal, map[string]string{"email": "user@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.(*PayPalProcessor); !ok {
+		t.Errorf("got %T, want the built-in *PayPalProcessor", p)
+	}
+	if err := UnregisterProcessor(PayPal); !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("second UnregisterProcessor = %v, want ErrNotRegistered", err)
+	}
+}
+
+func TestUnregisterWithoutBuiltin(t *testing.T) {
+	if err := RegisterProcessor("custom-gone", func(map[string]string) (PaymentProcessor, error) {
+		return &recordingProcessor{}, nil
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnregisterProcessor("custom-gone"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreatePaymentProcessor("custom-gone", nil); err == nil {
+		t.Error("an unregistered custom type can still be created")
+	}
+}
+
+func TestRegisterProcessorRejects(t *testing.T) {
+	create := func(map[string]string) (PaymentProcessor, error) { return nil, nil }
+	if err := RegisterProcessor("", create, nil); err == nil {
+		t.Error("registered an empty type")
+	}
+	if err := RegisterProcessor("no-constructor", nil, nil); err == nil {
+		t.Error("registered a nil constructor")
+	}
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	create := func(map[string]string) (PaymentProcessor, error) { return &recordingProcessor{}, nil }
+	var wg sync.WaitGroup
+	for _, pt := range []PaymentType{"conc-a", "conc-b", "conc-c"} {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				RegisterProcessor(pt, create, nil)
+				UnregisterProcessor(pt)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				for range RegisteredTypes() {
+				}
+				CreatePaymentProcessor(pt, nil)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRegisteredTypesSorted(t *testing.T) {
+	create := func(map[string]string) (PaymentProcessor, error) { return &recordingProcessor{}, nil }
+	for _, pt := range []PaymentType{"sorted-c", "sorted-a", "sorted-b"} {
+		register(t, pt, create, nil)
+	}
+
+	types := RegisteredTypes()
+	if !slices.IsSorted(types) {
+		t.Errorf("RegisteredTypes() = %v, want sorted", types)
+	}
+	i := slices.Index(types, "sorted-a")
+	if i < 0 || len(types) < i+3 || !slices.Equal(types[i:i+3], []PaymentType{"sorted-a", "sorted-b", "sorted-c"}) {
+		t.Errorf("RegisteredTypes() = %v, want the three registrations in order", types)
+	}
+	if slices.Contains(types, PayPal) {
+		t.Error("a built-in type that isn't overridden is listed")
+	}
+}
+
+func TestRegisteredTypesIsSnapshot(t *testing.T) {
+	create := func(map[string]string) (PaymentProcessor, error) { return &recordingProcessor{}, nil }
+	register(t, "snap-kept", create, nil)
+
+	snapshot := RegisteredTypes()
+	before := slices.Clone(snapshot)
+	register(t, "snap-added", create, nil)
+	if err := UnregisterProcessor("snap-kept"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(snapshot, before) {
+		t.Errorf("snapshot changed to %v after registry updates, want %v", snapshot, before)
+	}
+
+	// Scribbling on a snapshot doesn't reach the registry
+	snapshot[0] = "scribbled"
+	if slices.Contains(RegisteredTypes(), "scribbled") {
+		t.Error("modifying a snapshot changed the registry")
+	}
+}
+
+func TestRegisteredTypesConcurrentSnapshots(t *testing.T) {
+	create := func(map[string]string) (PaymentProcessor, error) { return &recordingProcessor{}, nil }
+	register(t, "stable-a", create, nil)
+	register(t, "stable-b", create, nil)
+
+	stop := make(chan struct{})
+	var writers sync.WaitGroup
+	for _, pt := range []PaymentType{"churn-a", "churn-b", "churn-c", "churn-d"} {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			for {
+				select {
+				case <-stop:
+					UnregisterProcessor(pt)
+					return
+				default:
+				}
+				RegisterProcessor(pt, create, nil)
+				UnregisterProcessor(pt)
+			}
+		}()
+	}
+
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for range 200 {
+				snapshot := RegisteredTypes()
+				before := slices.Clone(snapshot)
+				var seenA, seenB bool
+				for i, pt := range snapshot {
+					if i > 0 && snapshot[i-1] >= pt {
+						t.Errorf("snapshot %v isn't strictly sorted", snapshot)
+						return
+					}
+					seenA = seenA || pt == "stable-a"
+					seenB = seenB || pt == "stable-b"
+				}
+				if !seenA || !seenB {
+					t.Errorf("snapshot %v is missing a stable registration", snapshot)
+					return
+				}
+				if !slices.Equal(snapshot, before) {
+					t.Errorf("snapshot changed while iterating: %v, was %v", snapshot, before)
+					return
+				}
+			}
+		}()
+	}
+	readers.Wait()
+	close(stop)
+	writers.Wait()
+}
@@ -0,0 +1,122 @@
+package factory
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"strings"
+)
+
+// PaymentRequest describes a single charge
+type PaymentRequest struct {
+	Amount         float64
+	Currency       string
+	Description    string
+	Metadata       map[string]string
+	IdempotencyKey string
+}
+
+// PaymentRequestBuilder assembles a PaymentRequest step by step.
+// It is the same Builder pattern used for ServerConfig in the builder package,
+// applied to payments instead of loose (amount, details) arguments.
+type PaymentRequestBuilder struct {
+	request   PaymentRequest
+	amountSet bool
+}
+
+// NewPaymentRequestBuilder starts a request in DefaultCurrency
+func NewPaymentRequestBuilder() *PaymentRequestBuilder {
+	return &PaymentRequestBuilder{
+		request: PaymentRequest{
+			Currency: DefaultCurrency,
+			Metadata: make(map[string]string),
+		},
+	}
+}
+
+func (b *PaymentRequestBuilder) Amount(amount float64) *PaymentRequestBuilder {
+	b.request.Amount = amount
+	b.amountSet = true
+	return b
+}
+
+func (b *PaymentRequestBuilder) Currency(currency string) *PaymentRequestBuilder {
+	b.request.Currency = strings.ToUpper(strings.TrimSpace(currency))
+	return b
+}
+
+func (b *PaymentRequestBuilder) Description(description string) *PaymentRequestBuilder {
+	b.request.Description = description
+	return b
+}
+
+// Metadata adds one key/value pair; call it repeatedly to add more.
+// Setting an existing key overwrites it.
+func (b *PaymentRequestBuilder) Metadata(key, value string) *PaymentRequestBuilder {
+	b.request.Metadata[key] = value
+	return b
+}
+
+func (b *PaymentRequestBuilder) IdempotencyKey(key string) *PaymentRequestBuilder {
+	b.request.IdempotencyKey = key
+	return b
+}
+
+// Build validates the request and returns a copy of it
+func (b *PaymentRequestBuilder) Build() (*PaymentRequest, error) {
+	if !b.amountSet {
+		return nil, &ValidationError{Field: "amount", Message: "amount is required"}
+	}
+	if b.request.Amount <= 0 || math.IsNaN(b.request.Amount) || math.IsInf(b.request.Amount, 0) {
+		return nil, &ValidationError{Field: "amount", Message: "amount must be a positive number"}
+	}
+	if len(b.request.Currency) != 3 {
+		return nil, &ValidationError{Field: "currency", Message: "currency must be a 3-letter ISO code like USD"}
+	}
+
+	request := b.request
+	request.Metadata = maps.Clone(b.request.Metadata)
+	return &request, nil
+}
+
+// RequestProcessor is implemented by processors that accept a full PaymentRequest
+type RequestProcessor interface {
+	PaymentProcessor
+	ProcessRequest(req *PaymentRequest) (*Receipt, error)
+}
+
+func (c *CreditCardProcessor) ProcessRequest(req *PaymentRequest) (*Receipt, error) {
+	return processRequest(c, c.charge, req)
+}
+
+func (p *PayPalProcessor) ProcessRequest(req *PaymentRequest) (*Receipt, error) {
+	return processRequest(p, p.charge, req)
+}
+
+func (b *BankTransferProcessor) ProcessRequest(req *PaymentRequest) (*Receipt, error) {
+	return processRequest(b, b.charge, req)
+}
+
+// processRequest charges a request in its own currency and writes the receipt,
+// copying the request's metadata, description and idempotency key onto it.
+func processRequest(p PaymentProcessor, charge func(float64, string) error, req *PaymentRequest) (*Receipt, error) {
+	if req == nil {
+		return nil, errors.New("payment request is nil")
+	}
+	if err := ValidatePrecision(req.Amount, req.Currency); err != nil {
+		return nil, err
+	}
+	if err := charge(req.Amount, req.Currency); err != nil {
+		return nil, err
+	}
+
+	receipt := newReceipt(p.GetName(), req.Amount, req.Currency)
+	maps.Copy(receipt.Metadata, req.Metadata)
+	if req.Description != "" {
+		receipt.Metadata["description"] = req.Description
+	}
+	if req.IdempotencyKey != "" {
+		receipt.Metadata["idempotency_key"] = req.IdempotencyKey
+	}
+	return receipt, nil
+}
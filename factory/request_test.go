@@ -0,0 +1,89 @@
+package factory
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"testing"
+)
+
+func TestPaymentRequestBuilderRequiresAmount(t *testing.T) {
+	tests := map[string]*PaymentRequestBuilder{
+		"missing":  NewPaymentRequestBuilder(),
+		"zero":     NewPaymentRequestBuilder().Amount(0),
+		"negative": NewPaymentRequestBuilder().Amount(-5),
+		"NaN":      NewPaymentRequestBuilder().Amount(math.NaN()),
+		"infinite": NewPaymentRequestBuilder().Amount(math.Inf(1)),
+	}
+	for name, b := range tests {
+		var verr *ValidationError
+		if _, err := b.Build(); !errors.As(err, &verr) || verr.Field != "amount" {
+			t.Errorf("%s: Build = %v, want an amount ValidationError", name, err)
+		}
+	}
+
+	var verr *ValidationError
+	if _, err := NewPaymentRequestBuilder().Amount(5).Currency("dollars").Build(); !errors.As(err, &verr) || verr.Field != "currency" {
+		t.Errorf("bad currency: Build = %v, want a currency ValidationError", err)
+	}
+}
+
+func TestPaymentRequestBuilderMetadata(t *testing.T) {
+	b := NewPaymentRequestBuilder().
+		Amount(25).
+		Currency(" eur ").
+		Metadata("order", "1001").
+		Metadata("customer", "c-7").
+		Metadata("order", "1002")
+
+	req, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	want := map[string]string{"order": "1002", "customer": "c-7"}
+	if !maps.Equal(req.Metadata, want) {
+		t.Errorf("Metadata = %v, want %v", req.Metadata, want)
+	}
+	if req.Currency != "EUR" {
+		t.Errorf("Currency = %q, want EUR", req.Currency)
+	}
+
+	// The built request doesn't share its map with the builder
+	b.Metadata("late", "x")
+	if _, ok := req.Metadata["late"]; ok {
+		t.Error("metadata added after Build leaked into the request")
+	}
+}
+
+func TestProcessRequestReceipt(t *testing.T) {
+	p, err := CreatePaymentProcessor(PayPal, map[string]string{"email": "user@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := NewPaymentRequestBuilder().
+		Amount(42.5).
+		Currency("GBP").
+		Description("T-shirt").
+		IdempotencyKey("idem-1").
+		Metadata("order", "1001").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receipt, err := p.(RequestProcessor).ProcessRequest(req)
+	if err != nil {
+		t.Fatalf("ProcessRequest: %v", err)
+	}
+	if receipt.Amount != 42.5 || receipt.Currency != "GBP" || receipt.Processor != "PayPal" {
+		t.Errorf("receipt = %+v", receipt)
+	}
+	want := map[string]string{"order": "1001", "description": "T-shirt", "idempotency_key": "idem-1"}
+	if !maps.Equal(receipt.Metadata, want) {
+		t.Errorf("receipt metadata = %v, want %v", receipt.Metadata, want)
+	}
+
+	if _, err := p.(RequestProcessor).ProcessRequest(nil); err == nil {
+		t.Error("ProcessRequest(nil) succeeded")
+	}
+}
@@ -0,0 +1,82 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RetryProcessor retries failed charges with exponential backoff
+type RetryProcessor struct {
+	inner    PaymentProcessor
+	attempts int
+	backoff  time.Duration
+}
+
+// NewRetryProcessor wraps inner so a failed charge is tried up to attempts
+// times in total, waiting backoff before the first retry and doubling it
+// after each one. Values below 1 attempt are treated as 1.
+func NewRetryProcessor(inner PaymentProcessor, attempts int, backoff time.Duration) *RetryProcessor {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &RetryProcessor{inner: inner, attempts: attempts, backoff: backoff}
+}
+
+// Retry returns a Middleware that wraps processors with NewRetryProcessor
+func Retry(attempts int, backoff time.Duration) Middleware {
+	return func(p PaymentProcessor) PaymentProcessor { return NewRetryProcessor(p, attempts, backoff) }
+}
+
+func (r *RetryProcessor) Process(amount float64) error {
+	_, err := r.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+// ProcessCtx stops retrying as soon as ctx is done, returning the context error.
+// The number of attempts it took is recorded in the receipt's metadata.
+func (r *RetryProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	backoff := r.backoff
+	var lastErr error
+	for attempt := 1; attempt <= r.attempts; attempt++ {
+		receipt, err := ProcessCtx(ctx, r.inner, amount)
+		if err == nil {
+			if receipt.Metadata == nil {
+				receipt.Metadata = make(map[string]string)
+			}
+			receipt.Metadata["attempts"] = strconv.Itoa(attempt)
+			return receipt, nil
+		}
+		lastErr = err
+
+		if attempt == r.attempts {
+			break
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("retry cancelled after %d attempt(s): %w", attempt, ctx.Err())
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+	return nil, fmt.Errorf("payment failed after %d attempts: %w", r.attempts, lastErr)
+}
+
+func (r *RetryProcessor) GetName() string {
+	return r.inner.GetName()
+}
+
+func (r *RetryProcessor) Details() map[string]string {
+	return detailsOf(r.inner)
+}
+
+func (r *RetryProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(r.inner)
+}
+
+func (r *RetryProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, r.inner)
+}
@@ -0,0 +1,21 @@
+package factory
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRetryProcessorBareReceipt(t *testing.T) {
+	inner := newBlockingProcessor()
+	close(inner.release)
+	p := NewRetryProcessor(inner, 3, time.Millisecond)
+
+	receipt, err := p.ProcessCtx(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("ProcessCtx: %v", err)
+	}
+	if got := receipt.Metadata["attempts"]; got != "1" {
+		t.Errorf("attempts = %q, want %q", got, "1")
+	}
+}
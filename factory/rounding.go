@@ -0,0 +1,110 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrUnrepresentableAmount is returned in strict mode for amounts finer than
+// the currency's smallest unit, like 10.5 JPY
+var ErrUnrepresentableAmount = errors.New("amount can't be represented in the currency's smallest unit")
+
+// RoundingProcessor rounds amounts to the currency's minor unit (1 for JPY,
+// 0.01 for USD, 0.001 for BHD) before passing them on, so the inner processor
+// never sees a fraction of a cent. The difference is recorded on the receipt
+// as the "rounding_delta" metadata entry.
+//
+// The rounded amount is charged in the processor's currency. Outside
+// DefaultCurrency that takes an inner processor that accepts a PaymentRequest;
+// any other inner processor only charges DefaultCurrency, so charges through
+// it fail with ErrCurrencyMismatch.
+type RoundingProcessor struct {
+	inner    PaymentProcessor
+	currency string
+	strict   bool
+}
+
+// NewRoundingProcessor wraps inner so amounts are rounded for currency
+func NewRoundingProcessor(inner PaymentProcessor, currency string) *RoundingProcessor {
+	return &RoundingProcessor{inner: inner, currency: strings.ToUpper(currency)}
+}
+
+// Strict makes the processor reject amounts that need rounding with
+// ErrUnrepresentableAmount instead of rounding them
+func (r *RoundingProcessor) Strict() *RoundingProcessor {
+	r.strict = true
+	return r
+}
+
+// Round returns amount rounded half away from zero to the currency's minor unit
+func (r *RoundingProcessor) Round(amount float64) float64 {
+	return fromMinor(toMinor(amount, r.currency), r.currency)
+}
+
+func (r *RoundingProcessor) Process(amount float64) error {
+	_, err := r.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+func (r *RoundingProcessor) ProcessReceipt(amount float64) (*Receipt, error) {
+	return r.ProcessCtx(context.Background(), amount)
+}
+
+// ProcessCtx rounds amount and charges the result through the inner processor
+func (r *RoundingProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	rounded := r.Round(amount)
+	// Allow for float noise: 0.1+0.2 is representable in cents even though
+	// it isn't exactly 0.3
+	delta := math.Round((rounded-amount)*1e9) / 1e9
+	if delta == 0 {
+		delta = 0 // normalize -0
+	}
+	if r.strict && delta != 0 {
+		return nil, fmt.Errorf("%w: %v %s", ErrUnrepresentableAmount, amount, r.currency)
+	}
+
+	receipt, err := r.charge(ctx, rounded)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Metadata == nil {
+		receipt.Metadata = make(map[string]string)
+	}
+	receipt.Metadata["rounding_delta"] = strconv.FormatFloat(delta, 'f', -1, 64)
+	return receipt, nil
+}
+
+// charge passes the rounded amount on in the processor's currency
+func (r *RoundingProcessor) charge(ctx context.Context, amount float64) (*Receipt, error) {
+	if r.currency == DefaultCurrency {
+		return ProcessCtx(ctx, r.inner, amount)
+	}
+	rp, ok := r.inner.(RequestProcessor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s can only charge %s, not %s", ErrCurrencyMismatch, r.inner.GetName(), DefaultCurrency, r.currency)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return rp.ProcessRequest(&PaymentRequest{Amount: amount, Currency: r.currency})
+}
+
+func (r *RoundingProcessor) GetName() string {
+	return r.inner.GetName()
+}
+
+func (r *RoundingProcessor) Details() map[string]string {
+	return detailsOf(r.inner)
+}
+
+func (r *RoundingProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(r.inner)
+}
+
+func (r *RoundingProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, r.inner)
+}
@@ -0,0 +1,93 @@
+package factory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// requestRecorder is a recordingProcessor that takes payment requests,
+// so it can be charged in any currency
+type requestRecorder struct {
+	recordingProcessor
+	currencies []string
+}
+
+func (r *requestRecorder) ProcessRequest(req *PaymentRequest) (*Receipt, error) {
+	r.mu.Lock()
+	r.currencies = append(r.currencies, req.Currency)
+	r.mu.Unlock()
+	if err := r.Process(req.Amount); err != nil {
+		return nil, err
+	}
+	return &Receipt{Amount: req.Amount, Currency: req.Currency}, nil
+}
+
+func TestRoundingProcessor(t *testing.T) {
+	tests := []struct {
+		currency  string
+		amount    float64
+		want      float64
+		wantDelta string
+	}{
+		{"JPY", 1234.5, 1235, "0.5"},
+		{"JPY", 1234.4, 1234, "-0.4"},
+		{"JPY", 500, 500, "0"},
+		{"usd", 10.006, 10.01, "0.004"},
+		{"USD", 0.1 + 0.2, 0.3, "0"}, // float noise isn't a delta
+		{"BHD", 1.23456, 1.235, "0.00044"},
+	}
+	for _, tt := range tests {
+		inner := &requestRecorder{}
+		receipt, err := NewRoundingProcessor(inner, tt.currency).ProcessReceipt(tt.amount)
+		if err != nil {
+			t.Errorf("%v %s: %v", tt.amount, tt.currency, err)
+			continue
+		}
+		if charged := inner.charged(); len(charged) != 1 || charged[0] != tt.want {
+			t.Errorf("%v %s: charged %v, want %v", tt.amount, tt.currency, charged, tt.want)
+		}
+		if receipt.Currency != strings.ToUpper(tt.currency) {
+			t.Errorf("%v %s: charged in %s", tt.amount, tt.currency, receipt.Currency)
+		}
+		if got := receipt.Metadata["rounding_delta"]; got != tt.wantDelta {
+			t.Errorf("%v %s: rounding_delta = %s, want %s", tt.amount, tt.currency, got, tt.wantDelta)
+		}
+	}
+}
+
+func TestRoundingProcessorStrict(t *testing.T) {
+	inner := &requestRecorder{}
+	p := NewRoundingProcessor(inner, "JPY").Strict()
+
+	if err := p.Process(10.5); !errors.Is(err, ErrUnrepresentableAmount) {
+		t.Errorf("Process(10.5 JPY) = %v, want ErrUnrepresentableAmount", err)
+	}
+	if len(inner.charged()) != 0 {
+		t.Error("a rejected amount reached the inner processor")
+	}
+	if err := p.Process(1000); err != nil {
+		t.Errorf("Process(1000 JPY) = %v", err)
+	}
+}
+
+func TestRoundingProcessorInnerError(t *testing.T) {
+	boom := errors.New("boom")
+	p := NewRoundingProcessor(&recordingProcessor{err: boom}, "USD")
+	if err := p.Process(5.555); !errors.Is(err, boom) {
+		t.Errorf("Process = %v, want the inner error", err)
+	}
+}
+
+func TestRoundingProcessorNeedsCurrencyAwareInner(t *testing.T) {
+	inner := &recordingProcessor{}
+	if err := NewRoundingProcessor(inner, "JPY").Process(1000); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Process(1000 JPY) = %v, want ErrCurrencyMismatch", err)
+	}
+	if len(inner.charged()) != 0 {
+		t.Error("a JPY amount was charged through a DefaultCurrency processor")
+	}
+	if err := NewRoundingProcessor(inner, DefaultCurrency).Process(5); err != nil {
+		t.Errorf("Process(5 %s) = %v", DefaultCurrency, err)
+	}
+}
@@ -0,0 +1,124 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrNoRoute is returned when no rule matches and the router has no default
+var ErrNoRoute = errors.New("no processor matches this payment")
+
+// RouteMatcher decides whether a rule applies to a payment
+type RouteMatcher func(amount float64, currency string) bool
+
+// AmountOver matches payments strictly larger than limit
+func AmountOver(limit float64) RouteMatcher {
+	return func(amount float64, _ string) bool { return amount > limit }
+}
+
+// CurrencyIs matches payments in the given currency
+func CurrencyIs(currency string) RouteMatcher {
+	return func(_ float64, c string) bool { return strings.EqualFold(c, currency) }
+}
+
+type routeRule struct {
+	name      string
+	match     RouteMatcher
+	processor PaymentProcessor
+}
+
+// Router picks a processor for each payment from an ordered list of rules.
+// This is the Strategy pattern layered over the factory: the factory builds
+// the strategies, the router chooses between them at runtime.
+//
+//	router := NewRouter(cardProcessor).
+//		Rule("large amounts", AmountOver(10000), bankProcessor).
+//		Rule("euro", CurrencyIs("EUR"), sepaProcessor)
+//
+// Rules are checked in the order they were added; the first match wins.
+type Router struct {
+	mu       sync.RWMutex
+	rules    []routeRule
+	fallback PaymentProcessor
+}
+
+// NewRouter returns a router that uses fallback when no rule matches.
+// fallback may be nil, in which case unmatched payments get ErrNoRoute.
+func NewRouter(fallback PaymentProcessor) *Router {
+	return &Router{fallback: fallback}
+}
+
+// Rule appends a rule sending matching payments to processor
+func (r *Router) Rule(name string, match RouteMatcher, processor PaymentProcessor) *Router {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, routeRule{name: name, match: match, processor: processor})
+	return r
+}
+
+// Select returns the processor for a payment of amount in currency
+func (r *Router) Select(amount float64, currency string) (PaymentProcessor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rule := range r.rules {
+		if rule.match(amount, currency) {
+			return rule.processor, nil
+		}
+	}
+	if r.fallback != nil {
+		return r.fallback, nil
+	}
+	return nil, ErrNoRoute
+}
+
+// CurrencyProcessor is implemented by processors that can charge in a
+// currency other than DefaultCurrency
+type CurrencyProcessor interface {
+	PaymentProcessor
+	ProcessIn(amount float64, currency string) error
+}
+
+// ProcessIn routes and charges a payment in the given currency.
+// The currency is passed on to targets that accept one (CurrencyProcessor or
+// RequestProcessor); any other target can only be charged in DefaultCurrency,
+// so a payment in another currency routed to it fails with ErrCurrencyMismatch.
+func (r *Router) ProcessIn(amount float64, currency string) error {
+	processor, err := r.Select(amount, currency)
+	if err != nil {
+		return err
+	}
+	switch p := processor.(type) {
+	case CurrencyProcessor:
+		return p.ProcessIn(amount, currency)
+	case RequestProcessor:
+		_, err := p.ProcessRequest(&PaymentRequest{Amount: amount, Currency: currency})
+		return err
+	}
+	if !strings.EqualFold(currency, DefaultCurrency) {
+		return fmt.Errorf("%w: %s can only charge %s, not %s", ErrCurrencyMismatch, processor.GetName(), DefaultCurrency, currency)
+	}
+	return processor.Process(amount)
+}
+
+// Process routes and charges a payment in DefaultCurrency
+func (r *Router) Process(amount float64) error {
+	return r.ProcessIn(amount, DefaultCurrency)
+}
+
+// ProcessCtx routes a payment in DefaultCurrency and returns the receipt
+// issued by the processor it was routed to
+func (r *Router) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	processor, err := r.Select(amount, DefaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+	return ProcessCtx(ctx, processor, amount)
+}
+
+func (r *Router) GetName() string {
+	return "Router"
+}
@@ -0,0 +1,95 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRouterSelect(t *testing.T) {
+	card := &recordingProcessor{name: "Card"}
+	bank := &recordingProcessor{name: "Bank"}
+	sepa := &recordingProcessor{name: "SEPA"}
+	router := NewRouter(card).
+		Rule("large amounts", AmountOver(10000), bank).
+		Rule("euro", CurrencyIs("EUR"), sepa)
+
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		want     PaymentProcessor
+	}{
+		{"default", 50, "USD", card},
+		{"at the limit", 10000, "USD", card},
+		{"large", 10000.01, "USD", bank},
+		{"euro", 50, "EUR", sepa},
+		{"euro lowercase", 50, "eur", sepa},
+		{"first rule wins", 20000, "EUR", bank},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := router.Select(tt.amount, tt.currency)
+			if err != nil {
+				t.Fatalf("Select: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Select(%v, %s) = %s, want %s", tt.amount, tt.currency, got.GetName(), tt.want.GetName())
+			}
+		})
+	}
+}
+
+// currencyRecorder is a recordingProcessor that also accepts a currency
+type currencyRecorder struct {
+	recordingProcessor
+	currencies []string
+}
+
+func (c *currencyRecorder) ProcessIn(amount float64, currency string) error {
+	c.mu.Lock()
+	c.currencies = append(c.currencies, currency)
+	c.mu.Unlock()
+	return c.Process(amount)
+}
+
+func TestRouterNoDefault(t *testing.T) {
+	sepa := &currencyRecorder{recordingProcessor: recordingProcessor{name: "SEPA"}}
+	router := NewRouter(nil).Rule("euro", CurrencyIs("EUR"), sepa)
+
+	if _, err := router.Select(10, "USD"); !errors.Is(err, ErrNoRoute) {
+		t.Errorf("Select = %v, want ErrNoRoute", err)
+	}
+	if err := router.Process(10); !errors.Is(err, ErrNoRoute) {
+		t.Errorf("Process = %v, want ErrNoRoute", err)
+	}
+	if err := router.ProcessIn(10, "EUR"); err != nil {
+		t.Errorf("ProcessIn(EUR): %v", err)
+	}
+	if got := sepa.charged(); len(got) != 1 || got[0] != 10 {
+		t.Errorf("SEPA charged %v, want [10]", got)
+	}
+}
+
+func TestRouterProcessInForwardsCurrency(t *testing.T) {
+	sepa := &currencyRecorder{recordingProcessor: recordingProcessor{name: "SEPA"}}
+	card := &recordingProcessor{name: "Card"}
+	router := NewRouter(card).Rule("euro", CurrencyIs("EUR"), sepa)
+
+	if err := router.ProcessIn(10, "EUR"); err != nil {
+		t.Fatalf("ProcessIn(EUR): %v", err)
+	}
+	if len(sepa.currencies) != 1 || sepa.currencies[0] != "EUR" {
+		t.Errorf("SEPA charged in %v, want [EUR]", sepa.currencies)
+	}
+
+	// The fallback only charges DefaultCurrency, so it can't take GBP
+	if err := router.ProcessIn(10, "GBP"); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("ProcessIn(GBP) = %v, want ErrCurrencyMismatch", err)
+	}
+	if err := router.ProcessIn(10, DefaultCurrency); err != nil {
+		t.Errorf("ProcessIn(%s): %v", DefaultCurrency, err)
+	}
+	if got := card.charged(); len(got) != 1 {
+		t.Errorf("Card charged %v, want one DefaultCurrency payment", got)
+	}
+}
@@ -0,0 +1,95 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// Sandbox is the payment type for the test processor
+const Sandbox PaymentType = "sandbox"
+
+var (
+	// ErrDeclined is returned when the payment method refuses the charge,
+	// e.g. a card declined by the issuer. Trying another method may succeed.
+	ErrDeclined = errors.New("payment declined")
+	// ErrSandboxFailure is the hard error a sandbox processor produces on demand
+	ErrSandboxFailure = errors.New("sandbox: simulated processor error")
+)
+
+// SandboxProcessor never moves real money. It succeeds by default and
+// issues sequential receipts (sbx_000001, sbx_000002, ...), which makes it
+// handy for integration tests. The sequence is shared by every sandbox
+// processor, so two of them never issue the same transaction ID. Failure paths can be forced via details:
+//
+//	"forceDecline": "true" → every charge fails with ErrDeclined
+//	"forceError":   "true" → every charge fails with ErrSandboxFailure
+type SandboxProcessor struct {
+	forceDecline bool
+	forceError   bool
+}
+
+var sandboxSeq atomic.Int64
+
+func init() {
+	fields := []FormField{
+		{Name: "forceDecline", Label: "Force decline", Type: FieldText},
+		{Name: "forceError", Label: "Force error", Type: FieldText},
+	}
+	if err := RegisterProcessor(Sandbox, newSandboxProcessor, fields); err != nil {
+		panic(err)
+	}
+}
+
+func newSandboxProcessor(details map[string]string) (PaymentProcessor, error) {
+	s := &SandboxProcessor{}
+	for key, target := range map[string]*bool{"forceDecline": &s.forceDecline, "forceError": &s.forceError} {
+		raw, ok := details[key]
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, &ValidationError{Field: key, Message: "must be true or false"}
+		}
+		*target = value
+	}
+	return s, nil
+}
+
+func (s *SandboxProcessor) Process(amount float64) error {
+	_, err := s.ProcessReceipt(amount)
+	return err
+}
+
+func (s *SandboxProcessor) ProcessReceipt(amount float64) (*Receipt, error) {
+	switch {
+	case s.forceError:
+		return nil, ErrSandboxFailure
+	case s.forceDecline:
+		return nil, ErrDeclined
+	}
+	if err := ValidatePrecision(amount, DefaultCurrency); err != nil {
+		return nil, err
+	}
+
+	id := fmt.Sprintf("sbx_%06d", sandboxSeq.Add(1))
+
+	logger.Printf("Processing %s via Sandbox (%s)\n", FormatAmount(amount, DefaultCurrency), id)
+	receipt := &Receipt{
+		TransactionID: id,
+		Processor:     s.GetName(),
+		Amount:        amount,
+		Currency:      DefaultCurrency,
+		Status:        StatusSucceeded,
+		Timestamp:     clock.Now(),
+		Metadata:      map[string]string{"sandbox": "true"},
+	}
+	transactions.capture(id, amount, DefaultCurrency)
+	return receipt, nil
+}
+
+func (s *SandboxProcessor) GetName() string {
+	return "Sandbox"
+}
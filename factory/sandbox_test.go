@@ -0,0 +1,84 @@
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSandboxForcedOutcomes(t *testing.T) {
+	tests := []struct {
+		name    string
+		details map[string]string
+		wantErr error
+	}{
+		{"succeeds by default", nil, nil},
+		{"force decline", map[string]string{"forceDecline": "true"}, ErrDeclined},
+		{"force error", map[string]string{"forceError": "1"}, ErrSandboxFailure},
+		{"error wins over decline", map[string]string{"forceDecline": "true", "forceError": "true"}, ErrSandboxFailure},
+		{"explicitly off", map[string]string{"forceDecline": "false"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := CreatePaymentProcessor(Sandbox, tt.details)
+			if err != nil {
+				t.Fatalf("CreatePaymentProcessor: %v", err)
+			}
+			if err := p.Process(10); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Process = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSandboxRejectsBadFlag(t *testing.T) {
+	var verr *ValidationError
+	_, err := CreatePaymentProcessor(Sandbox, map[string]string{"forceError": "maybe"})
+	if !errors.As(err, &verr) || verr.Field != "forceError" {
+		t.Errorf("CreatePaymentProcessor = %v, want a forceError ValidationError", err)
+	}
+}
+
+func TestSandboxSequentialReceipts(t *testing.T) {
+	p, err := CreatePaymentProcessor(Sandbox, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sandbox := p.(*SandboxProcessor)
+	next := sandboxSeq.Load()
+	for range 2 {
+		next++
+		want := fmt.Sprintf("sbx_%06d", next)
+		receipt, err := sandbox.ProcessReceipt(5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt.TransactionID != want || receipt.Processor != "Sandbox" || receipt.Status != StatusSucceeded {
+			t.Errorf("receipt = %+v, want transaction %s", receipt, want)
+		}
+	}
+}
+
+func TestSandboxInstancesShareSequence(t *testing.T) {
+	var ids []string
+	for _, amount := range []float64{5, 7} {
+		p, err := CreatePaymentProcessor(Sandbox, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		receipt, err := Charge(p, amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, receipt.TransactionID)
+	}
+	if ids[0] == ids[1] {
+		t.Fatalf("two sandbox processors both issued %s", ids[0])
+	}
+	// Each charge keeps its own ledger entry
+	for i, want := range []float64{5, 7} {
+		if _, remaining, err := RefundStatus(ids[i]); err != nil || remaining != want {
+			t.Errorf("RefundStatus(%s) = %v, %v, want %v remaining", ids[i], remaining, err, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package factory
+
+// FieldType tells a form renderer which kind of input to show
+type FieldType string
+
+const (
+	FieldText   FieldType = "text"
+	FieldEmail  FieldType = "email"
+	FieldNumber FieldType = "number"
+)
+
+// FormField describes one entry of a payment type's details map.
+// Secret fields (like a CVV) should be masked in the UI and never logged.
+type FormField struct {
+	Name     string
+	Label    string
+	Type     FieldType
+	Required bool
+	Secret   bool
+}
+
+// builtinSchemas lists the details each built-in payment type reads
+var builtinSchemas = map[PaymentType][]FormField{
+	CreditCard: {
+		{Name: "cardNumber", Label: "Card number", Type: FieldNumber, Required: true, Secret: true},
+		{Name: "cvv", Label: "CVV", Type: FieldNumber, Required: true, Secret: true},
+		{Name: "expMonth", Label: "Expiry month", Type: FieldNumber, Required: true},
+		{Name: "expYear", Label: "Expiry year", Type: FieldNumber, Required: true},
+	},
+	PayPal: {
+		{Name: "email", Label: "PayPal email", Type: FieldEmail, Required: true},
+	},
+	BankTransfer: {
+		{Name: "accountNumber", Label: "Account number", Type: FieldNumber, Required: true},
+		{Name: "routingNumber", Label: "Routing number", Type: FieldNumber, Required: true},
+	},
+}
+
+// PaymentFormSchema returns the fields a frontend should render to collect
+// details for the given payment type, including registered custom types.
+func PaymentFormSchema(t PaymentType) ([]FormField, error) {
+	if reg, ok := lookupRegistration(t); ok {
+		return append([]FormField(nil), reg.fields...), nil
+	}
+	if fields, ok := builtinSchemas[t]; ok {
+		return append([]FormField(nil), fields...), nil
+	}
+	return nil, &UnknownPaymentTypeError{Type: t}
+}
@@ -0,0 +1,59 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPaymentFormSchemaCreditCard(t *testing.T) {
+	fields, err := PaymentFormSchema(CreditCard)
+	if err != nil {
+		t.Fatalf("PaymentFormSchema: %v", err)
+	}
+
+	byName := make(map[string]FormField)
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+	for _, name := range []string{"cardNumber", "cvv", "expMonth", "expYear"} {
+		if !byName[name].Required {
+			t.Errorf("%s is missing or not required", name)
+		}
+	}
+	if !byName["cvv"].Secret {
+		t.Error("cvv is not marked secret")
+	}
+	if byName["expMonth"].Secret {
+		t.Error("expMonth is marked secret")
+	}
+}
+
+func TestPaymentFormSchemaReturnsCopy(t *testing.T) {
+	fields, _ := PaymentFormSchema(PayPal)
+	fields[0].Name = "changed"
+	again, _ := PaymentFormSchema(PayPal)
+	if again[0].Name != "email" {
+		t.Error("modifying the returned schema changed the built-in one")
+	}
+}
+
+func TestPaymentFormSchemaCustomType(t *testing.T) {
+	register(t, "giftcard", func(map[string]string) (PaymentProcessor, error) {
+		return &recordingProcessor{}, nil
+	}, []FormField{{Name: "code", Label: "Gift card code", Type: FieldText, Required: true, Secret: true}})
+
+	fields, err := PaymentFormSchema("giftcard")
+	if err != nil {
+		t.Fatalf("PaymentFormSchema: %v", err)
+	}
+	if len(fields) != 1 || fields[0].Name != "code" || !fields[0].Secret {
+		t.Errorf("fields = %+v, want the registered code field", fields)
+	}
+}
+
+func TestPaymentFormSchemaUnknownType(t *testing.T) {
+	var unknown *UnknownPaymentTypeError
+	if _, err := PaymentFormSchema("crypto"); !errors.As(err, &unknown) {
+		t.Errorf("PaymentFormSchema = %v, want an UnknownPaymentTypeError", err)
+	}
+}
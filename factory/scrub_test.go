@@ -0,0 +1,74 @@
+package factory
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDefaultScrubber(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"card 4111111111111111 declined", "card ****1111 declined"},
+		{"card 4111 1111 1111 1111 declined", "card ****1111 declined"},
+		{"card 3782-822463-10005 declined", "card ****0005 declined"},
+		{"cvv=123", "cvv=***"},
+		{"CVC: 4567", "CVC: ***"},
+		{"csc 999 rejected", "csc *** rejected"},
+		{"card 5500000000000004 cvv2 321", "card ****0004 cvv2 ***"},
+		{"order 12345 for $99.99", "order 12345 for $99.99"},
+		{"routing 021000021", "routing 021000021"},
+		{"", ""},
+	}
+	sc := DefaultScrubber()
+	for _, tt := range tests {
+		if got := sc.Scrub(tt.in); got != tt.want {
+			t.Errorf("Scrub(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCustomScrubRules(t *testing.T) {
+	email := ScrubRule{Pattern: regexp.MustCompile(`[\w.+-]+@([\w-]+\.[\w.]+)`), Replacement: "***@$1"}
+	sc := NewScrubber(append(DefaultScrubRules(), email)...)
+
+	got := sc.Scrub("user@example.com paid with 4111111111111111")
+	if want := "***@example.com paid with ****1111"; got != want {
+		t.Errorf("Scrub = %q, want %q", got, want)
+	}
+
+	var nilScrubber *Scrubber
+	if got := nilScrubber.Scrub("4111111111111111"); got != "4111111111111111" {
+		t.Errorf("nil Scrubber changed the line to %q", got)
+	}
+}
+
+func TestLoggingProcessorScrubs(t *testing.T) {
+	log := &bufferLogger{}
+	failure := errors.New("card 4111 1111 1111 1111 with cvv 123 was declined")
+	p := NewLoggingProcessor(&recordingProcessor{name: "Card", err: failure}, log)
+
+	if err := p.Process(10); !errors.Is(err, failure) {
+		t.Fatalf("Process = %v, want the inner error unscrubbed", err)
+	}
+	out := log.String()
+	if strings.Contains(out, "4111 1111 1111 1111") || strings.Contains(out, "cvv 123") {
+		t.Errorf("log leaks card data: %q", out)
+	}
+	if !strings.Contains(out, "card ****1111 with cvv *** was declined") {
+		t.Errorf("log = %q, want the masked error", out)
+	}
+}
+
+func TestLoggingProcessorWithoutScrubber(t *testing.T) {
+	log := &bufferLogger{}
+	failure := errors.New("cvv 123")
+	p := NewLoggingProcessor(&recordingProcessor{err: failure}, log).WithScrubber(nil)
+
+	p.Process(10)
+	if !strings.Contains(log.String(), "cvv 123") {
+		t.Errorf("log = %q, want the line as it was", log.String())
+	}
+}
@@ -0,0 +1,82 @@
+package factory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go-design-patterns/redact"
+)
+
+// ProcessorSpec declares a processor in data rather than code, so it can be
+// loaded from a config file. Middlewares are names looked up in the
+// middleware registry, listed outermost first.
+type ProcessorSpec struct {
+	Type        PaymentType
+	Details     map[string]string
+	Middlewares []string
+}
+
+// Redacted masks the details the payment type's form schema marks as
+// secret. Details of an unknown type are all masked, since we can't tell
+// which of them are sensitive.
+func (s ProcessorSpec) Redacted() any {
+	fields, err := PaymentFormSchema(s.Type)
+	secret := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		secret[f.Name] = f.Secret
+	}
+
+	details := make(map[string]any, len(s.Details))
+	for k, v := range s.Details {
+		if err != nil || secret[k] {
+			details[k] = redact.Mask
+		} else {
+			details[k] = v
+		}
+	}
+	return map[string]any{
+		"Type":        s.Type,
+		"Details":     details,
+		"Middlewares": append([]string(nil), s.Middlewares...),
+	}
+}
+
+var (
+	middlewaresMu sync.RWMutex
+	middlewares   = map[string]Middleware{
+		"logging": Logging(nil),
+		"retry":   Retry(3, 100*time.Millisecond),
+		"timeout": Timeout(5 * time.Second),
+	}
+)
+
+// RegisterMiddleware makes a middleware available to specs under name.
+// Registering an existing name replaces it.
+func RegisterMiddleware(name string, m Middleware) {
+	middlewaresMu.Lock()
+	defer middlewaresMu.Unlock()
+	middlewares[name] = m
+}
+
+// CreateFromSpec builds the base processor through the factory and wraps it
+// with the spec's middlewares.
+func CreateFromSpec(spec ProcessorSpec) (PaymentProcessor, error) {
+	chain := make([]Middleware, 0, len(spec.Middlewares))
+	middlewaresMu.RLock()
+	for _, name := range spec.Middlewares {
+		m, ok := middlewares[name]
+		if !ok {
+			middlewaresMu.RUnlock()
+			return nil, fmt.Errorf("unknown middleware %q", name)
+		}
+		chain = append(chain, m)
+	}
+	middlewaresMu.RUnlock()
+
+	processor, err := CreatePaymentProcessor(spec.Type, spec.Details)
+	if err != nil {
+		return nil, err
+	}
+	return Chain(processor, chain...), nil
+}
@@ -0,0 +1,82 @@
+package factory
+
+import (
+	"testing"
+)
+
+func TestCreateFromSpecWrapsInOrder(t *testing.T) {
+	p, err := CreateFromSpec(ProcessorSpec{
+		Type:        PayPal,
+		Details:     map[string]string{"email": "user@example.com"},
+		Middlewares: []string{"logging", "retry"},
+	})
+	if err != nil {
+		t.Fatalf("CreateFromSpec: %v", err)
+	}
+
+	logging, ok := p.(*LoggingProcessor)
+	if !ok {
+		t.Fatalf("outermost processor is %T, want *LoggingProcessor", p)
+	}
+	retry, ok := logging.inner.(*RetryProcessor)
+	if !ok {
+		t.Fatalf("second processor is %T, want *RetryProcessor", logging.inner)
+	}
+	if _, ok := retry.inner.(*PayPalProcessor); !ok {
+		t.Fatalf("base processor is %T, want *PayPalProcessor", retry.inner)
+	}
+	if err := p.Process(10); err != nil {
+		t.Errorf("Process: %v", err)
+	}
+}
+
+func TestCreateFromSpecCustomMiddleware(t *testing.T) {
+	var wrapped int
+	RegisterMiddleware("counting-"+t.Name(), func(p PaymentProcessor) PaymentProcessor {
+		wrapped++
+		return p
+	})
+	_, err := CreateFromSpec(ProcessorSpec{
+		Type:        PayPal,
+		Details:     map[string]string{"email": "user@example.com"},
+		Middlewares: []string{"counting-" + t.Name()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wrapped != 1 {
+		t.Errorf("custom middleware applied %d times, want 1", wrapped)
+	}
+}
+
+func TestCreateFromSpecErrors(t *testing.T) {
+	tests := map[string]ProcessorSpec{
+		"unknown middleware": {Type: PayPal, Details: map[string]string{"email": "user@example.com"}, Middlewares: []string{"logging", "nope"}},
+		"invalid details":    {Type: PayPal, Details: map[string]string{"email": "nope"}},
+		"unknown type":       {Type: "crypto"},
+	}
+	for name, spec := range tests {
+		if p, err := CreateFromSpec(spec); err == nil {
+			t.Errorf("%s: CreateFromSpec = %v, want an error", name, p)
+		}
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(p PaymentProcessor) PaymentProcessor {
+			order = append(order, name)
+			return p
+		}
+	}
+	Chain(&recordingProcessor{}, mark("outer"), mark("middle"), mark("inner"))
+
+	// Wrapping happens inside out, so the innermost middleware is applied first
+	want := []string{"inner", "middle", "outer"}
+	for i := range want {
+		if i >= len(order) || order[i] != want[i] {
+			t.Fatalf("applied %v, want %v", order, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrPartialSplit is wrapped by the error returned when one leg of a split
+// payment succeeded and the other failed.
+var ErrPartialSplit = errors.New("split payment partially completed")
+
+// SplitProcessor charges a marketplace payment in two legs: the platform fee
+// goes to one processor and the remainder to the seller's processor.
+//
+// Partial-failure policy: the seller leg is charged first, since it carries
+// most of the money. If it fails, nothing is charged. If the platform leg then
+// fails, the seller charge stands and the error wraps ErrPartialSplit; the
+// seller receipt is still returned so the fee can be collected or reconciled later.
+type SplitProcessor struct {
+	platform   PaymentProcessor
+	seller     PaymentProcessor
+	feePercent float64
+}
+
+// NewSplitProcessor returns a SplitProcessor taking feePercent (0-100) for the platform
+func NewSplitProcessor(platform, seller PaymentProcessor, feePercent float64) (*SplitProcessor, error) {
+	if feePercent < 0 || feePercent > 100 || math.IsNaN(feePercent) {
+		return nil, fmt.Errorf("fee percentage must be between 0 and 100, got %v", feePercent)
+	}
+	return &SplitProcessor{platform: platform, seller: seller, feePercent: feePercent}, nil
+}
+
+// Split returns how amount divides into the platform fee and the seller's share.
+// The fee is rounded to the cent and the seller gets the rest, so the two
+// always add back up to amount.
+func (s *SplitProcessor) Split(amount float64) (fee, remainder float64) {
+	fee = math.Round(amount*s.feePercent) / 100
+	remainder = math.Round((amount-fee)*100) / 100
+	return fee, remainder
+}
+
+// ProcessSplit charges both legs and returns a receipt for each.
+// A leg with nothing to charge (e.g. a 0% fee) is skipped and its receipt is nil.
+func (s *SplitProcessor) ProcessSplit(amount float64) (platformReceipt, sellerReceipt *Receipt, err error) {
+	fee, remainder := s.Split(amount)
+
+	if remainder > 0 {
+		sellerReceipt, err = Charge(s.seller, remainder)
+		if err != nil {
+			return nil, nil, fmt.Errorf("seller charge failed: %w", err)
+		}
+	}
+	if fee > 0 {
+		platformReceipt, err = Charge(s.platform, fee)
+		if err != nil {
+			return nil, sellerReceipt, fmt.Errorf("%w: platform fee failed: %w", ErrPartialSplit, err)
+		}
+	}
+	return platformReceipt, sellerReceipt, nil
+}
+
+func (s *SplitProcessor) Process(amount float64) error {
+	_, _, err := s.ProcessSplit(amount)
+	return err
+}
+
+// ProcessCtx charges both legs and returns a receipt for the whole payment.
+// Each leg is captured under its own transaction, recorded in the receipt's
+// metadata; the combined receipt isn't in the ledger, so refunds are made
+// against the legs.
+func (s *SplitProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	platformReceipt, sellerReceipt, err := s.ProcessSplit(amount)
+	if err != nil {
+		return nil, err
+	}
+	receipt := unrecordedReceipt(s.GetName(), amount, DefaultCurrency)
+	if sellerReceipt != nil {
+		receipt.Metadata["seller_transaction"] = sellerReceipt.TransactionID
+	}
+	if platformReceipt != nil {
+		receipt.Metadata["platform_transaction"] = platformReceipt.TransactionID
+	}
+	return receipt, nil
+}
+
+func (s *SplitProcessor) GetName() string {
+	return "Split (" + s.platform.GetName() + " / " + s.seller.GetName() + ")"
+}
@@ -0,0 +1,94 @@
+package factory
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSplitMath(t *testing.T) {
+	tests := []struct {
+		amount, percent float64
+		fee, remainder  float64
+	}{
+		{100, 10, 10, 90},
+		{100, 2.5, 2.5, 97.5},
+		{10.01, 15, 1.5, 8.51},
+		{0.99, 33.3, 0.33, 0.66},
+		{50, 0, 0, 50},
+		{50, 100, 50, 0},
+	}
+	for _, tt := range tests {
+		s, err := NewSplitProcessor(&recordingProcessor{}, &recordingProcessor{}, tt.percent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fee, remainder := s.Split(tt.amount)
+		if fee != tt.fee || remainder != tt.remainder {
+			t.Errorf("Split(%v) at %v%% = %v + %v, want %v + %v", tt.amount, tt.percent, fee, remainder, tt.fee, tt.remainder)
+		}
+	}
+}
+
+func TestNewSplitProcessorRejectsBadFee(t *testing.T) {
+	for _, percent := range []float64{-1, 100.01, math.NaN()} {
+		if _, err := NewSplitProcessor(&recordingProcessor{}, &recordingProcessor{}, percent); err == nil {
+			t.Errorf("NewSplitProcessor(%v%%) succeeded", percent)
+		}
+	}
+}
+
+func TestProcessSplitChargesBothLegs(t *testing.T) {
+	platform, seller := &recordingProcessor{name: "Platform"}, &recordingProcessor{name: "Seller"}
+	s, _ := NewSplitProcessor(platform, seller, 10)
+
+	platformReceipt, sellerReceipt, err := s.ProcessSplit(200)
+	if err != nil {
+		t.Fatalf("ProcessSplit: %v", err)
+	}
+	if !slices.Equal(platform.charged(), []float64{20}) || !slices.Equal(seller.charged(), []float64{180}) {
+		t.Errorf("charged platform %v and seller %v, want [20] and [180]", platform.charged(), seller.charged())
+	}
+	if platformReceipt == nil || platformReceipt.Amount != 20 || sellerReceipt == nil || sellerReceipt.Amount != 180 {
+		t.Errorf("receipts = %+v and %+v", platformReceipt, sellerReceipt)
+	}
+}
+
+func TestProcessSplitSkipsEmptyLeg(t *testing.T) {
+	platform, seller := &recordingProcessor{}, &recordingProcessor{}
+	s, _ := NewSplitProcessor(platform, seller, 0)
+
+	platformReceipt, _, err := s.ProcessSplit(10)
+	if err != nil || platformReceipt != nil || len(platform.charged()) != 0 {
+		t.Errorf("0%% fee: err %v, platform receipt %v, platform charged %v", err, platformReceipt, platform.charged())
+	}
+}
+
+func TestProcessSplitSellerFails(t *testing.T) {
+	errDeclined := errors.New("declined")
+	platform, seller := &recordingProcessor{}, &recordingProcessor{err: errDeclined}
+	s, _ := NewSplitProcessor(platform, seller, 10)
+
+	_, _, err := s.ProcessSplit(100)
+	if !errors.Is(err, errDeclined) || errors.Is(err, ErrPartialSplit) {
+		t.Errorf("ProcessSplit = %v, want the seller error and no partial split", err)
+	}
+	if len(platform.charged()) != 0 {
+		t.Error("platform was charged although the seller leg failed")
+	}
+}
+
+func TestProcessSplitPlatformFails(t *testing.T) {
+	errDeclined := errors.New("declined")
+	platform, seller := &recordingProcessor{err: errDeclined}, &recordingProcessor{}
+	s, _ := NewSplitProcessor(platform, seller, 10)
+
+	platformReceipt, sellerReceipt, err := s.ProcessSplit(100)
+	if !errors.Is(err, ErrPartialSplit) || !errors.Is(err, errDeclined) {
+		t.Errorf("ProcessSplit = %v, want ErrPartialSplit wrapping the platform error", err)
+	}
+	if platformReceipt != nil || sellerReceipt == nil || sellerReceipt.Amount != 90 {
+		t.Errorf("receipts = %v and %+v, want only the seller's", platformReceipt, sellerReceipt)
+	}
+}
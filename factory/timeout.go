@@ -0,0 +1,80 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTimeout is returned when a processor doesn't finish within its deadline
+var ErrTimeout = errors.New("payment processing timed out")
+
+// TimeoutProcessor wraps another PaymentProcessor and gives up on it after a deadline.
+type TimeoutProcessor struct {
+	inner   PaymentProcessor
+	timeout time.Duration
+}
+
+// NewTimeoutProcessor wraps inner so Process returns ErrTimeout
+// if the inner call takes longer than d.
+func NewTimeoutProcessor(inner PaymentProcessor, d time.Duration) *TimeoutProcessor {
+	return &TimeoutProcessor{inner: inner, timeout: d}
+}
+
+// Process runs the inner processor and waits for it up to the timeout
+func (t *TimeoutProcessor) Process(amount float64) error {
+	_, err := t.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+// ProcessCtx runs the inner processor in a goroutine and waits for it until
+// ctx's deadline or the processor's own timeout, whichever comes first.
+// The inner processor receives the shortened context, so context-aware
+// processors can stop early too. The result channel is buffered, so if we
+// stop waiting the goroutine can still deliver its result and exit instead
+// of blocking forever.
+func (t *TimeoutProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	type result struct {
+		receipt *Receipt
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		receipt, err := ProcessCtx(ctx, t.inner, amount)
+		done <- result{receipt, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.receipt, r.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrTimeout
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Timeout returns a Middleware that wraps processors with NewTimeoutProcessor
+func Timeout(d time.Duration) Middleware {
+	return func(p PaymentProcessor) PaymentProcessor { return NewTimeoutProcessor(p, d) }
+}
+
+func (t *TimeoutProcessor) GetName() string {
+	return t.inner.GetName()
+}
+
+func (t *TimeoutProcessor) Details() map[string]string {
+	return detailsOf(t.inner)
+}
+
+func (t *TimeoutProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(t.inner)
+}
+
+func (t *TimeoutProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, t.inner)
+}
@@ -0,0 +1,82 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingProcessor doesn't finish a charge until release is closed or its
+// context ends, and closes finished when it returns. Tests wait on finished
+// so the charging goroutine is gone before the next test swaps the clock.
+type blockingProcessor struct {
+	release  chan struct{}
+	finished chan struct{}
+}
+
+func newBlockingProcessor() *blockingProcessor {
+	return &blockingProcessor{release: make(chan struct{}), finished: make(chan struct{})}
+}
+
+func (b *blockingProcessor) Process(amount float64) error {
+	_, err := b.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+func (b *blockingProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	defer close(b.finished)
+	select {
+	case <-b.release:
+		return &Receipt{Amount: amount}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (b *blockingProcessor) GetName() string { return "Blocking" }
+
+func TestTimeoutProcessorSlowInner(t *testing.T) {
+	slow := newBlockingProcessor()
+	p := NewTimeoutProcessor(slow, 10*time.Millisecond)
+	if err := p.Process(10); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Process = %v, want ErrTimeout", err)
+	}
+	// The inner processor got the deadline too and gave up
+	<-slow.finished
+}
+
+func TestTimeoutProcessorFastInner(t *testing.T) {
+	inner := &recordingProcessor{}
+	p := NewTimeoutProcessor(inner, time.Second)
+
+	receipt, err := p.ProcessCtx(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("ProcessCtx: %v", err)
+	}
+	if receipt == nil || receipt.Amount != 10 {
+		t.Errorf("receipt = %+v, want one for 10", receipt)
+	}
+	if len(inner.charged()) != 1 {
+		t.Errorf("inner charged %v, want one charge", inner.charged())
+	}
+}
+
+func TestTimeoutProcessorInnerError(t *testing.T) {
+	errDeclined := errors.New("declined")
+	p := NewTimeoutProcessor(&recordingProcessor{err: errDeclined}, time.Second)
+	if err := p.Process(10); !errors.Is(err, errDeclined) {
+		t.Errorf("Process = %v, want %v", err, errDeclined)
+	}
+}
+
+func TestTimeoutProcessorCallerCancels(t *testing.T) {
+	slow := newBlockingProcessor()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := NewTimeoutProcessor(slow, time.Second)
+	if _, err := p.ProcessCtx(ctx, 10); !errors.Is(err, context.Canceled) {
+		t.Errorf("ProcessCtx = %v, want context.Canceled", err)
+	}
+	<-slow.finished
+}
@@ -0,0 +1,141 @@
+package factory
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// Tracer starts spans. It mirrors the small part of OpenTelemetry the
+// processors need, so a real tracer can be adapted without this package
+// depending on it.
+type Tracer interface {
+	StartSpan(name string) Span
+}
+
+// Span is one traced operation
+type Span interface {
+	SetAttribute(key string, value any)
+	// End finishes the span; err is nil if the operation succeeded
+	End(err error)
+}
+
+// NoopTracer discards every span. It's what a TracingProcessor uses when
+// given a nil tracer.
+type NoopTracer struct{}
+
+func (NoopTracer) StartSpan(string) Span { return noopSpan{} }
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) End(error)                {}
+
+// TracingProcessor opens a span around every charge. The span is named
+// after the processor, carries the amount and currency as attributes, and
+// ends with the charge's error, if any.
+type TracingProcessor struct {
+	inner  PaymentProcessor
+	tracer Tracer
+}
+
+// NewTracingProcessor wraps inner, reporting spans to tracer (or NoopTracer if nil)
+func NewTracingProcessor(inner PaymentProcessor, tracer Tracer) *TracingProcessor {
+	if tracer == nil {
+		tracer = NoopTracer{}
+	}
+	return &TracingProcessor{inner: inner, tracer: tracer}
+}
+
+// Tracing returns a Middleware that wraps processors with NewTracingProcessor
+func Tracing(tracer Tracer) Middleware {
+	return func(p PaymentProcessor) PaymentProcessor { return NewTracingProcessor(p, tracer) }
+}
+
+func (t *TracingProcessor) Process(amount float64) error {
+	_, err := t.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+func (t *TracingProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	span := t.tracer.StartSpan(t.inner.GetName())
+	span.SetAttribute("payment.amount", amount)
+	span.SetAttribute("payment.currency", DefaultCurrency)
+	if id, ok := RequestIDFromContext(ctx); ok {
+		span.SetAttribute("request.id", id)
+	}
+
+	receipt, err := ProcessCtx(ctx, t.inner, amount)
+	if err != nil {
+		span.SetAttribute("error", true)
+		span.SetAttribute("error.message", err.Error())
+	} else {
+		span.SetAttribute("payment.transaction_id", receipt.TransactionID)
+	}
+	span.End(err)
+	return receipt, err
+}
+
+func (t *TracingProcessor) GetName() string {
+	return t.inner.GetName()
+}
+
+func (t *TracingProcessor) Details() map[string]string {
+	return detailsOf(t.inner)
+}
+
+func (t *TracingProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(t.inner)
+}
+
+func (t *TracingProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, t.inner)
+}
+
+// RecordingTracer keeps every span in memory, for tests and demos
+type RecordingTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// RecordedSpan is a span captured by a RecordingTracer
+type RecordedSpan struct {
+	Name       string
+	Attributes map[string]any
+	Ended      bool
+	Err        error
+
+	mu *sync.Mutex
+}
+
+func (r *RecordingTracer) StartSpan(name string) Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	span := &RecordedSpan{Name: name, Attributes: make(map[string]any), mu: &r.mu}
+	r.spans = append(r.spans, span)
+	return span
+}
+
+// Spans returns copies of the spans started so far, oldest first
+func (r *RecordingTracer) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]RecordedSpan, len(r.spans))
+	for i, s := range r.spans {
+		out[i] = RecordedSpan{Name: s.Name, Attributes: maps.Clone(s.Attributes), Ended: s.Ended, Err: s.Err}
+	}
+	return out
+}
+
+func (s *RecordedSpan) SetAttribute(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+func (s *RecordedSpan) End(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Ended = true
+	s.Err = err
+}
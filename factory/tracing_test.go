@@ -0,0 +1,68 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTracingProcessorSuccess(t *testing.T) {
+	tracer := &RecordingTracer{}
+	p := NewTracingProcessor(&recordingProcessor{name: "Traced"}, tracer)
+
+	ctx := WithRequestID(context.Background(), "req-9")
+	receipt, err := p.ProcessCtx(ctx, 19.99)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := tracer.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "Traced" || !span.Ended || span.Err != nil {
+		t.Errorf("span = %+v, want an ended, successful span named Traced", span)
+	}
+	want := map[string]any{
+		"payment.amount":         19.99,
+		"payment.currency":       DefaultCurrency,
+		"request.id":             "req-9",
+		"payment.transaction_id": receipt.TransactionID,
+	}
+	for k, v := range want {
+		if span.Attributes[k] != v {
+			t.Errorf("attribute %s = %v, want %v", k, span.Attributes[k], v)
+		}
+	}
+	if _, ok := span.Attributes["error"]; ok {
+		t.Error("successful span has an error attribute")
+	}
+}
+
+func TestTracingProcessorFailure(t *testing.T) {
+	tracer := &RecordingTracer{}
+	boom := errors.New("card declined")
+	p := NewTracingProcessor(&recordingProcessor{err: boom}, tracer)
+
+	if err := p.Process(5); !errors.Is(err, boom) {
+		t.Fatalf("Process = %v, want the inner error", err)
+	}
+	span := tracer.Spans()[0]
+	if !span.Ended || !errors.Is(span.Err, boom) {
+		t.Errorf("span ended %v with %v, want ended with the error", span.Ended, span.Err)
+	}
+	if span.Attributes["error"] != true || span.Attributes["error.message"] != "card declined" {
+		t.Errorf("attributes = %v, want the error recorded", span.Attributes)
+	}
+}
+
+func TestTracingProcessorNilTracer(t *testing.T) {
+	inner := &recordingProcessor{}
+	if err := NewTracingProcessor(inner, nil).Process(3); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(inner.charged()) != 1 {
+		t.Error("the inner processor wasn't charged")
+	}
+}
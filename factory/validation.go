@@ -0,0 +1,154 @@
+package factory
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError is returned by the factory when the details for a
+// payment type are malformed. Field names the offending detail key.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// NormalizeEmail trims surrounding whitespace and lowercases the address
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ValidateEmail performs a lightweight RFC 5322 style check:
+// exactly one "@", a non-empty local part, and a domain containing a dot
+// that does not start or end with one.
+func ValidateEmail(email string) error {
+	if email == "" {
+		return &ValidationError{Field: "email", Message: "email is required"}
+	}
+	if strings.ContainsAny(email, " \t\r\n") {
+		return &ValidationError{Field: "email", Message: "email must not contain whitespace"}
+	}
+	if strings.Count(email, "@") != 1 {
+		return &ValidationError{Field: "email", Message: "email must contain exactly one @"}
+	}
+
+	local, domain, _ := strings.Cut(email, "@")
+	if local == "" {
+		return &ValidationError{Field: "email", Message: "email local part is empty"}
+	}
+	if domain == "" {
+		return &ValidationError{Field: "email", Message: "email domain is empty"}
+	}
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return &ValidationError{Field: "email", Message: "email domain must contain a dot, e.g. example.com"}
+	}
+	return nil
+}
+
+// abaWeights are the per-digit weights of the ABA routing number checksum
+var abaWeights = [9]int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+
+// ValidateRoutingNumber checks that a US bank routing number is 9 digits
+// and passes the ABA checksum: the weighted digit sum must be a multiple of 10.
+func ValidateRoutingNumber(routing string) error {
+	if len(routing) != 9 {
+		return &ValidationError{Field: "routingNumber", Message: "routing number must be exactly 9 digits"}
+	}
+
+	sum := 0
+	for i := 0; i < len(routing); i++ {
+		c := routing[i]
+		if c < '0' || c > '9' {
+			return &ValidationError{Field: "routingNumber", Message: "routing number must contain only digits"}
+		}
+		sum += int(c-'0') * abaWeights[i]
+	}
+	if sum%10 != 0 {
+		return &ValidationError{Field: "routingNumber", Message: "routing number failed ABA checksum"}
+	}
+	return nil
+}
+
+// ValidateAccountNumber checks that a bank account number is 4-17 digits long
+func ValidateAccountNumber(account string) error {
+	if len(account) < 4 || len(account) > 17 {
+		return &ValidationError{Field: "accountNumber", Message: "account number must be between 4 and 17 digits"}
+	}
+	for i := 0; i < len(account); i++ {
+		if account[i] < '0' || account[i] > '9' {
+			return &ValidationError{Field: "accountNumber", Message: "account number must contain only digits"}
+		}
+	}
+	return nil
+}
+
+// NormalizeCardNumber strips the spaces and dashes people type between digit groups.
+// Normalizing an already normalized number returns it unchanged. Trimming
+// comes last, so whitespace a removed dash was hiding goes too.
+func NormalizeCardNumber(number string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, number))
+}
+
+// ValidateCardNumber checks that a (normalized) card number is 12-19 ASCII
+// digits and passes the Luhn checksum. Only '0'-'9' count as digits, so
+// look-alikes such as full-width or Arabic-Indic numerals are rejected.
+func ValidateCardNumber(number string) error {
+	if len(number) < 12 || len(number) > 19 {
+		return &ValidationError{Field: "cardNumber", Message: "card number must be between 12 and 19 digits"}
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			return &ValidationError{Field: "cardNumber", Message: "card number must contain only digits"}
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	if sum%10 != 0 {
+		return &ValidationError{Field: "cardNumber", Message: "card number failed Luhn checksum"}
+	}
+	return nil
+}
+
+// ErrCardExpired is returned when a card's expiry date is in the past
+var ErrCardExpired = errors.New("card has expired")
+
+// ValidateExpiry parses the expiry month (1-12) and year and checks the card
+// is still valid at now. Cards are valid through the last day of their expiry month.
+func ValidateExpiry(month, year string, now time.Time) (int, int, error) {
+	m, err := strconv.Atoi(month)
+	if err != nil || m < 1 || m > 12 {
+		return 0, 0, &ValidationError{Field: "expMonth", Message: "expiry month must be between 1 and 12"}
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil || y < 1 {
+		return 0, 0, &ValidationError{Field: "expYear", Message: "expiry year must be a year like 2030"}
+	}
+
+	// The first instant after the card stops being valid
+	expiresAt := time.Date(y, time.Month(m)+1, 1, 0, 0, 0, 0, now.Location())
+	if !now.Before(expiresAt) {
+		return 0, 0, ErrCardExpired
+	}
+	return m, y, nil
+}
@@ -0,0 +1,256 @@
+package factory
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		ok    bool
+	}{
+		{"user@example.com", true},
+		{"first.last+tag@mail.example.co.uk", true},
+		{"a@b.c", true},
+		{"", false},
+		{"userexample.com", false},
+		{"user@@example.com", false},
+		{"a@b@example.com", false},
+		{"@example.com", false},
+		{"user@", false},
+		{"user@localhost", false},
+		{"user@.example.com", false},
+		{"user@example.com.", false},
+		{"us er@example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			err := ValidateEmail(tt.email)
+			if tt.ok && err != nil {
+				t.Fatalf("ValidateEmail(%q) = %v, want nil", tt.email, err)
+			}
+			if !tt.ok {
+				var verr *ValidationError
+				if !errors.As(err, &verr) || verr.Field != "email" {
+					t.Fatalf("ValidateEmail(%q) = %v, want a ValidationError for email", tt.email, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	if got := NormalizeEmail("  User@Example.COM\n"); got != "user@example.com" {
+		t.Errorf("NormalizeEmail = %q, want user@example.com", got)
+	}
+}
+
+func TestCreatePayPalNormalizesEmail(t *testing.T) {
+	p, err := CreatePaymentProcessor(PayPal, map[string]string{"email": " Jane.Doe@Example.com "})
+	if err != nil {
+		t.Fatalf("CreatePaymentProcessor: %v", err)
+	}
+	if got := p.(*PayPalProcessor).email; got != "jane.doe@example.com" {
+		t.Errorf("stored email = %q, want jane.doe@example.com", got)
+	}
+
+	_, err = CreatePaymentProcessor(PayPal, map[string]string{"email": "not-an-email"})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("CreatePaymentProcessor with a bad email = %v, want a ValidationError", err)
+	}
+}
+
+func TestValidateRoutingNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		routing string
+		ok      bool
+	}{
+		{"chase", "021000021", true},
+		{"federal reserve", "011000015", true},
+		{"bad checksum", "021000022", false},
+		{"too short", "02100002", false},
+		{"too long", "0210000210", false},
+		{"letters", "02100002a", false},
+		{"empty", "", false},
+		{"non-ascii digit", "02100002١", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoutingNumber(tt.routing)
+			if tt.ok != (err == nil) {
+				t.Fatalf("ValidateRoutingNumber(%q) = %v, want ok=%v", tt.routing, err, tt.ok)
+			}
+			var verr *ValidationError
+			if err != nil && (!errors.As(err, &verr) || verr.Field != "routingNumber") {
+				t.Errorf("error %v is not a ValidationError for routingNumber", err)
+			}
+		})
+	}
+}
+
+func TestValidateAccountNumber(t *testing.T) {
+	tests := []struct {
+		account string
+		ok      bool
+	}{
+		{"1234", true},
+		{"12345678901234567", true},
+		{"123", false},
+		{"123456789012345678", false},
+		{"1234-5678", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if err := ValidateAccountNumber(tt.account); tt.ok != (err == nil) {
+			t.Errorf("ValidateAccountNumber(%q) = %v, want ok=%v", tt.account, err, tt.ok)
+		}
+	}
+}
+
+func TestCreateBankTransferValidatesDetails(t *testing.T) {
+	if _, err := CreatePaymentProcessor(BankTransfer, map[string]string{"accountNumber": "12345678", "routingNumber": "021000021"}); err != nil {
+		t.Fatalf("valid details: %v", err)
+	}
+	_, err := CreatePaymentProcessor(BankTransfer, map[string]string{"accountNumber": "12345678", "routingNumber": "123456789"})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Field != "routingNumber" {
+		t.Errorf("bad routing number: got %v, want a routingNumber ValidationError", err)
+	}
+}
+
+// tricky inputs shared by the fuzz targets: look-alike digits and
+// whitespace, invalid UTF-8 and very long strings
+var fuzzSeeds = []string{
+	"",
+	"4111111111111111",
+	"4111 1111 1111 1111",
+	"4111-1111-1111-1111",
+	" 4111111111111111\t",
+	"４１１１１１１１１１１１１１１１", // full-width digits
+	"٤١١١١١١١١١١١١١١١", // Arabic-Indic digits
+	"४१११११११११११११११", // Devanagari digits
+	"- 4111111111111111",
+	" user@example.com ",
+	"user@example.com",
+	"USER@EXAMPLE.COM",
+	"user@@example.com",
+	"İstanbul@example.com",
+	"user@exa​mple.com",
+	"\xff\xfe@example.com",
+	strings.Repeat("4", 10000),
+	strings.Repeat("a", 100000) + "@" + strings.Repeat("b", 100000) + ".com",
+}
+
+func FuzzValidateCardNumber(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		normalized := NormalizeCardNumber(input)
+		if again := NormalizeCardNumber(normalized); again != normalized {
+			t.Fatalf("NormalizeCardNumber not idempotent: %q -> %q -> %q", input, normalized, again)
+		}
+
+		err := ValidateCardNumber(normalized)
+		if again := ValidateCardNumber(normalized); (err == nil) != (again == nil) {
+			t.Fatalf("ValidateCardNumber(%q) unstable: %v then %v", normalized, err, again)
+		}
+		if err != nil {
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Field != "cardNumber" {
+				t.Fatalf("ValidateCardNumber(%q) = %v, want a cardNumber ValidationError", normalized, err)
+			}
+			return
+		}
+		if len(normalized) < 12 || len(normalized) > 19 {
+			t.Fatalf("accepted %q with %d bytes", normalized, len(normalized))
+		}
+		for _, r := range normalized {
+			if r < '0' || r > '9' {
+				t.Fatalf("accepted %q containing %q", normalized, r)
+			}
+		}
+	})
+}
+
+func FuzzValidateEmail(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		normalized := NormalizeEmail(input)
+		if again := NormalizeEmail(normalized); again != normalized {
+			t.Fatalf("NormalizeEmail not idempotent: %q -> %q -> %q", input, normalized, again)
+		}
+
+		err := ValidateEmail(normalized)
+		if again := ValidateEmail(normalized); (err == nil) != (again == nil) {
+			t.Fatalf("ValidateEmail(%q) unstable: %v then %v", normalized, err, again)
+		}
+		if err != nil {
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Field != "email" {
+				t.Fatalf("ValidateEmail(%q) = %v, want an email ValidationError", normalized, err)
+			}
+			return
+		}
+		local, domain, _ := strings.Cut(normalized, "@")
+		if local == "" || !strings.Contains(domain, ".") || strings.Contains(domain, "@") {
+			t.Fatalf("accepted malformed address %q", normalized)
+		}
+	})
+}
+
+func TestValidateExpiry(t *testing.T) {
+	now := time.Date(2025, time.June, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		month, year string
+		wantErr     error
+		wantField   string
+	}{
+		{"future", "12", "2030", nil, ""},
+		{"current month", "6", "2025", nil, ""},
+		{"last month", "5", "2025", ErrCardExpired, ""},
+		{"last year", "12", "2024", ErrCardExpired, ""},
+		{"month zero", "0", "2030", nil, "expMonth"},
+		{"month thirteen", "13", "2030", nil, "expMonth"},
+		{"month not a number", "june", "2030", nil, "expMonth"},
+		{"year missing", "6", "", nil, "expYear"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ValidateExpiry(tt.month, tt.year, now)
+			if tt.wantField != "" {
+				var verr *ValidationError
+				if !errors.As(err, &verr) || verr.Field != tt.wantField {
+					t.Fatalf("ValidateExpiry = %v, want a %s ValidationError", err, tt.wantField)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateExpiry = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateCreditCardUsesClock(t *testing.T) {
+	SetClock(newFakeClock(time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { SetClock(nil) })
+
+	details := map[string]string{"cardNumber": "4111111111111111", "cvv": "123", "expMonth": "12", "expYear": "2030"}
+	if _, err := CreatePaymentProcessor(CreditCard, details); !errors.Is(err, ErrCardExpired) {
+		t.Errorf("CreatePaymentProcessor = %v, want ErrCardExpired", err)
+	}
+
+	SetClock(newFakeClock(time.Date(2030, time.December, 31, 23, 59, 0, 0, time.UTC)))
+	if _, err := CreatePaymentProcessor(CreditCard, details); err != nil {
+		t.Errorf("card on its last valid day: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrVelocityExceeded is returned when an account makes too many payments in too short a time
+var ErrVelocityExceeded = errors.New("too many payments for this account, try again later")
+
+// accountKeys are the detail keys that identify an account, most specific first
+var accountKeys = []string{"accountNumber", "email", "cardLast4"}
+
+// VelocityProcessor limits how many payments an account may make within a
+// sliding window, a common fraud rule: a stolen card is typically tried many
+// times in quick succession. The account is identified from the inner
+// processor's details (account number, email or card), so processors for
+// different accounts are counted separately.
+//
+// Every attempt counts, including ones the inner processor rejects, since
+// repeated declines are exactly what the rule is meant to catch.
+type VelocityProcessor struct {
+	inner   PaymentProcessor
+	maxTxns int
+	window  time.Duration
+	clock   Clock
+
+	mu       sync.Mutex
+	accounts map[string]*attempts
+}
+
+// attempts is a ring of the most recent attempt times for one account.
+// It holds maxTxns entries, so the oldest entry is the one that decides
+// whether another attempt fits in the window.
+type attempts struct {
+	times []time.Time
+	next  int
+	count int
+}
+
+// NewVelocityProcessor wraps inner, allowing at most maxTxns payments per
+// account within any window-long period. A nil clock means the real one.
+func NewVelocityProcessor(inner PaymentProcessor, maxTxns int, window time.Duration, clock Clock) *VelocityProcessor {
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &VelocityProcessor{
+		inner:    inner,
+		maxTxns:  maxTxns,
+		window:   window,
+		clock:    clock,
+		accounts: make(map[string]*attempts),
+	}
+}
+
+func (v *VelocityProcessor) Process(amount float64) error {
+	_, err := v.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+// ProcessCtx is Process with a context, returning the inner processor's receipt
+func (v *VelocityProcessor) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	if err := v.allow(v.account()); err != nil {
+		return nil, err
+	}
+	return ProcessCtx(ctx, v.inner, amount)
+}
+
+// allow records an attempt for account, or returns ErrVelocityExceeded if
+// the account already made maxTxns attempts within the window
+func (v *VelocityProcessor) allow(account string) error {
+	if v.maxTxns < 1 {
+		return ErrVelocityExceeded
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	a, ok := v.accounts[account]
+	if !ok {
+		a = &attempts{times: make([]time.Time, v.maxTxns)}
+		v.accounts[account] = a
+	}
+
+	now := v.clock.Now()
+	if a.count == len(a.times) {
+		// Full: a.next is the oldest attempt, and the one we'd overwrite
+		if now.Sub(a.times[a.next]) < v.window {
+			return ErrVelocityExceeded
+		}
+	} else {
+		a.count++
+	}
+	a.times[a.next] = now
+	a.next = (a.next + 1) % len(a.times)
+	return nil
+}
+
+// account identifies the account behind the inner processor
+func (v *VelocityProcessor) account() string {
+	details := detailsOf(v.inner)
+	for _, key := range accountKeys {
+		if id := details[key]; id != "" {
+			return key + ":" + id
+		}
+	}
+	return "processor:" + v.inner.GetName()
+}
+
+func (v *VelocityProcessor) GetName() string {
+	return v.inner.GetName()
+}
+
+func (v *VelocityProcessor) Details() map[string]string {
+	return detailsOf(v.inner)
+}
+
+func (v *VelocityProcessor) Capabilities() ProcessorCapabilities {
+	return capabilitiesOf(v.inner)
+}
+
+func (v *VelocityProcessor) Preflight(ctx context.Context) error {
+	return preflightOf(ctx, v.inner)
+}
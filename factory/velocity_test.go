@@ -0,0 +1,81 @@
+package factory
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// detailedProcessor is a recordingProcessor that reports details
+type detailedProcessor struct {
+	recordingProcessor
+	details map[string]string
+}
+
+func (d *detailedProcessor) Details() map[string]string { return d.details }
+
+func TestVelocityProcessorSlidingWindow(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	inner := &detailedProcessor{details: map[string]string{"email": "user@example.com"}}
+	p := NewVelocityProcessor(inner, 2, time.Minute, clock)
+
+	steps := []struct {
+		advance time.Duration
+		wantErr error
+	}{
+		{0, nil},
+		{30 * time.Second, nil},
+		{10 * time.Second, ErrVelocityExceeded}, // 2 in the last 40s
+		{21 * time.Second, nil},                 // the first has slid out
+		{9 * time.Second, ErrVelocityExceeded},  // 0:30 and 1:01 still inside
+		{21 * time.Second, nil},
+	}
+	for i, step := range steps {
+		clock.Advance(step.advance)
+		if err := p.Process(10); !errors.Is(err, step.wantErr) {
+			t.Errorf("step %d: Process = %v, want %v", i, err, step.wantErr)
+		}
+	}
+	if got := len(inner.charged()); got != 4 {
+		t.Errorf("inner charged %d times, want 4", got)
+	}
+}
+
+func TestVelocityProcessorCountsDeclines(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	declined := errors.New("declined")
+	p := NewVelocityProcessor(&recordingProcessor{err: declined}, 2, time.Hour, clock)
+
+	for range 2 {
+		if err := p.Process(1); !errors.Is(err, declined) {
+			t.Fatalf("Process = %v, want the decline", err)
+		}
+	}
+	if err := p.Process(1); !errors.Is(err, ErrVelocityExceeded) {
+		t.Errorf("third attempt = %v, want ErrVelocityExceeded", err)
+	}
+}
+
+func TestVelocityProcessorAccountKey(t *testing.T) {
+	tests := []struct {
+		details map[string]string
+		want    string
+	}{
+		{map[string]string{"accountNumber": "123", "email": "a@b.co"}, "accountNumber:123"},
+		{map[string]string{"email": "a@b.co"}, "email:a@b.co"},
+		{map[string]string{"cardLast4": "1111"}, "cardLast4:1111"},
+		{nil, "processor:Recording"},
+	}
+	for _, tt := range tests {
+		p := NewVelocityProcessor(&detailedProcessor{details: tt.details}, 1, time.Minute, nil)
+		if got := p.account(); got != tt.want {
+			t.Errorf("account(%v) = %q, want %q", tt.details, got, tt.want)
+		}
+	}
+}
+
+func TestVelocityProcessorZeroLimit(t *testing.T) {
+	if err := NewVelocityProcessor(&recordingProcessor{}, 0, time.Minute, nil).Process(1); !errors.Is(err, ErrVelocityExceeded) {
+		t.Errorf("Process = %v, want ErrVelocityExceeded", err)
+	}
+}
@@ -0,0 +1,87 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// WeightedProcessor is one entry in a WeightedRouter
+type WeightedProcessor struct {
+	Processor PaymentProcessor
+	Weight    int
+}
+
+// WeightedRouter spreads payments across processors at random, in proportion
+// to their weights. It is meant for A/B testing providers: weights 90 and 10
+// send roughly one payment in ten to the second processor.
+type WeightedRouter struct {
+	mu      sync.Mutex
+	entries []WeightedProcessor
+	total   int
+	rng     *rand.Rand
+}
+
+// NewWeightedRouter returns a router over entries. Weights must not be
+// negative and must sum to more than zero; zero-weight entries are never picked.
+func NewWeightedRouter(entries []WeightedProcessor) (*WeightedRouter, error) {
+	total := 0
+	for _, e := range entries {
+		if e.Weight < 0 {
+			return nil, errors.New("weights must not be negative")
+		}
+		if e.Weight > 0 && e.Processor == nil {
+			return nil, errors.New("weighted entry has no processor")
+		}
+		total += e.Weight
+	}
+	if total <= 0 {
+		return nil, errors.New("weights must sum to more than zero")
+	}
+	return &WeightedRouter{
+		entries: append([]WeightedProcessor(nil), entries...),
+		total:   total,
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// SetSource replaces the random source, so tests can seed it and get a
+// repeatable sequence of picks.
+func (r *WeightedRouter) SetSource(src rand.Source) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rng = rand.New(src)
+}
+
+// Select picks a processor at random, proportional to weight
+func (r *WeightedRouter) Select() PaymentProcessor {
+	r.mu.Lock()
+	n := r.rng.Intn(r.total)
+	r.mu.Unlock()
+
+	for _, e := range r.entries {
+		if n < e.Weight {
+			return e.Processor
+		}
+		n -= e.Weight
+	}
+	// Unreachable: n is always below the sum of the weights
+	return nil
+}
+
+// Process charges amount through a randomly selected processor
+func (r *WeightedRouter) Process(amount float64) error {
+	_, err := r.ProcessCtx(context.Background(), amount)
+	return err
+}
+
+// ProcessCtx is Process with a context, returning the selected processor's receipt
+func (r *WeightedRouter) ProcessCtx(ctx context.Context, amount float64) (*Receipt, error) {
+	return ProcessCtx(ctx, r.Select(), amount)
+}
+
+func (r *WeightedRouter) GetName() string {
+	return "Weighted Router"
+}
@@ -0,0 +1,76 @@
+package factory
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestWeightedRouterDistribution(t *testing.T) {
+	a, b, c := &recordingProcessor{name: "A"}, &recordingProcessor{name: "B"}, &recordingProcessor{name: "C"}
+	r, err := NewWeightedRouter([]WeightedProcessor{
+		{Processor: a, Weight: 70},
+		{Processor: b, Weight: 20},
+		{Processor: c, Weight: 10},
+		{Processor: nil, Weight: 0}, // never picked
+	})
+	if err != nil {
+		t.Fatalf("NewWeightedRouter: %v", err)
+	}
+	r.SetSource(rand.NewSource(42))
+
+	const draws = 100000
+	counts := map[string]int{}
+	for range draws {
+		counts[r.Select().GetName()]++
+	}
+	for name, weight := range map[string]float64{"A": 0.7, "B": 0.2, "C": 0.1} {
+		share := float64(counts[name]) / draws
+		if math.Abs(share-weight) > 0.01 {
+			t.Errorf("%s picked %.3f of the time, want about %.2f", name, share, weight)
+		}
+	}
+	if len(counts) != 3 {
+		t.Errorf("picked %v, want only A, B and C", counts)
+	}
+}
+
+func TestWeightedRouterSeededIsRepeatable(t *testing.T) {
+	entries := []WeightedProcessor{
+		{Processor: &recordingProcessor{name: "A"}, Weight: 1},
+		{Processor: &recordingProcessor{name: "B"}, Weight: 1},
+	}
+	picks := func() []string {
+		r, err := NewWeightedRouter(entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.SetSource(rand.NewSource(7))
+		var names []string
+		for range 20 {
+			names = append(names, r.Select().GetName())
+		}
+		return names
+	}
+	first, second := picks(), picks()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("same seed gave different picks: %v vs %v", first, second)
+		}
+	}
+}
+
+func TestNewWeightedRouterRejects(t *testing.T) {
+	p := &recordingProcessor{}
+	tests := map[string][]WeightedProcessor{
+		"empty":        nil,
+		"all zero":     {{Processor: p, Weight: 0}},
+		"negative":     {{Processor: p, Weight: 5}, {Processor: p, Weight: -1}},
+		"no processor": {{Processor: nil, Weight: 1}},
+	}
+	for name, entries := range tests {
+		if _, err := NewWeightedRouter(entries); err == nil {
+			t.Errorf("%s: NewWeightedRouter succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Package pool provides a generic Object Pool.
+//
+// Creating some objects is expensive (database connections, processors that
+// validate their credentials), so instead of building one per use, a pool
+// keeps finished objects around and hands them out again. It also caps how
+// many exist at once: when every object is in use, Get waits for a Put.
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Pool hands out reusable objects of type T, creating at most max of them
+type Pool[T any] struct {
+	newFn func() (T, error)
+
+	// slots holds one token per object that may still be created or is idle,
+	// so its capacity is the pool's max size
+	slots chan struct{}
+
+	mu      sync.Mutex
+	idle    []T
+	created int
+	live    int // objects that exist now, in use or idle
+	out     int // objects handed out by Get and not yet Put back
+}
+
+// New returns a pool that creates objects with newFn, at most max at a time.
+// A max below 1 is treated as 1. When newFn fails, nothing is added to the
+// pool and the error is passed on to the caller that needed the object.
+func New[T any](newFn func() (T, error), max int) *Pool[T] {
+	if max < 1 {
+		max = 1
+	}
+	p := &Pool[T]{newFn: newFn, slots: make(chan struct{}, max)}
+	for i := 0; i < max; i++ {
+		p.slots <- struct{}{}
+	}
+	return p
+}
+
+// Get returns an idle object, or a new one if none is idle and the pool is
+// below its max. Otherwise it waits until another caller Puts one back.
+// It fails only if a new object was needed and newFn failed.
+func (p *Pool[T]) Get() (T, error) {
+	return p.GetCtx(context.Background())
+}
+
+// GetCtx is Get, but gives up with ctx's error if ctx ends while waiting
+func (p *Pool[T]) GetCtx(ctx context.Context) (T, error) {
+	select {
+	case <-p.slots:
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+
+	p.mu.Lock()
+	if n := len(p.idle); n > 0 {
+		v := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.out++
+		p.mu.Unlock()
+		return v, nil
+	}
+	p.created++
+	p.live++
+	p.mu.Unlock()
+
+	v, err := p.newFn()
+	p.mu.Lock()
+	if err != nil {
+		// Nothing was created, so give back the count and the slot
+		p.created--
+		p.live--
+		p.mu.Unlock()
+		p.slots <- struct{}{}
+		var zero T
+		return zero, err
+	}
+	p.out++
+	p.mu.Unlock()
+	return v, nil
+}
+
+// ErrPoolFull is what Put panics with when it has no matching Get
+var ErrPoolFull = errors.New("pool: put without a matching get")
+
+// Put returns v to the pool for reuse. Every Put must match an earlier Get;
+// an extra Put panics, before v is added, rather than let the pool grow
+// past its max.
+func (p *Pool[T]) Put(v T) {
+	p.mu.Lock()
+	if p.out == 0 {
+		p.mu.Unlock()
+		panic(ErrPoolFull)
+	}
+	p.out--
+	p.idle = append(p.idle, v)
+	p.mu.Unlock()
+
+	// The matching Get took a slot, so there is room for this one
+	p.slots <- struct{}{}
+}
+
+// Fill creates objects until at least n are idle, so the next n Gets don't
+// wait on newFn. It never creates more than the pool's max and returns how
+// many objects it created. It stops at the first newFn error and returns it.
+func (p *Pool[T]) Fill(n int) (int, error) {
+	filled := 0
+	for {
+		// Hold a token while creating, so a concurrent Get can't create
+		// an object of its own in the space this one is going into
+		select {
+		case <-p.slots:
+		default:
+			return filled, nil
+		}
+		p.mu.Lock()
+		if len(p.idle) >= n || p.live >= cap(p.slots) {
+			p.mu.Unlock()
+			p.slots <- struct{}{}
+			return filled, nil
+		}
+		p.created++
+		p.live++
+		p.mu.Unlock()
+
+		v, err := p.newFn()
+		p.mu.Lock()
+		if err != nil {
+			p.created--
+			p.live--
+			p.mu.Unlock()
+			p.slots <- struct{}{}
+			return filled, err
+		}
+		p.idle = append(p.idle, v)
+		p.mu.Unlock()
+		p.slots <- struct{}{}
+		filled++
+	}
+}
+
+// Evict removes idle objects for which expired returns true, longest idle
+// first, but leaves at least keep objects idle. The removed objects no
+// longer count toward max and are returned so the caller can release them.
+func (p *Pool[T]) Evict(keep int, expired func(T) bool) []T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// idle is used as a stack, so the front has waited longest
+	var evicted []T
+	kept := p.idle[:0]
+	remaining := len(p.idle)
+	for _, v := range p.idle {
+		if remaining > keep && expired(v) {
+			evicted = append(evicted, v)
+			remaining--
+			continue
+		}
+		kept = append(kept, v)
+	}
+	clear(p.idle[len(kept):])
+	p.idle = kept
+	p.live -= len(evicted)
+	return evicted
+}
+
+// Created returns how many objects the pool has created so far, including
+// ones since evicted
+func (p *Pool[T]) Created() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.created
+}
+
+// Idle returns how many objects are waiting to be reused
+func (p *Pool[T]) Idle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.idle)
+}
+
+// Max returns the most objects the pool will create
+func (p *Pool[T]) Max() int {
+	return cap(p.slots)
+}
@@ -0,0 +1,312 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counter hands out consecutive ints, counting how many it made
+type counter struct{ n atomic.Int32 }
+
+func (c *counter) next() (*int, error) {
+	v := int(c.n.Add(1))
+	return &v, nil
+}
+
+// get takes an object from p, failing the test if that errors
+func get[T any](t *testing.T, p *Pool[T]) T {
+	t.Helper()
+	v, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	return v
+}
+
+// fill fills p, failing the test if that errors, and returns how many objects it created
+func fill[T any](t *testing.T, p *Pool[T], n int) int {
+	t.Helper()
+	filled, err := p.Fill(n)
+	if err != nil {
+		t.Fatalf("Fill(%d): %v", n, err)
+	}
+	return filled
+}
+
+func TestPoolReusesObjects(t *testing.T) {
+	var c counter
+	p := New(c.next, 2)
+
+	a := get(t, p)
+	p.Put(a)
+	if b := get(t, p); b != a {
+		t.Errorf("Get after Put = %d, want the returned object %d back", *b, *a)
+	}
+	if got := p.Created(); got != 1 {
+		t.Errorf("Created = %d, want 1", got)
+	}
+}
+
+func TestPoolConcurrentNeverExceedsMax(t *testing.T) {
+	const max = 4
+	var c counter
+	p := New(c.next, max)
+
+	var inUse, peak atomic.Int32
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				v, err := p.Get()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				n := inUse.Add(1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				inUse.Add(-1)
+				p.Put(v)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := p.Created(); got > max {
+		t.Errorf("Created = %d, want at most %d", got, max)
+	}
+	if got := peak.Load(); got > max {
+		t.Errorf("%d objects were in use at once, want at most %d", got, max)
+	}
+	if got := p.Idle(); got != p.Created() {
+		t.Errorf("Idle = %d after everything was returned, want %d", got, p.Created())
+	}
+}
+
+func TestPoolGetWaitsForPut(t *testing.T) {
+	var c counter
+	p := New(c.next, 1)
+	held := get(t, p)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.GetCtx(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetCtx on an exhausted pool = %v, want context.DeadlineExceeded", err)
+	}
+
+	got := make(chan *int)
+	go func() {
+		v, _ := p.Get()
+		got <- v
+	}()
+	p.Put(held)
+	if v := <-got; v != held {
+		t.Errorf("waiting Get received %d, want %d", *v, *held)
+	}
+}
+
+func TestPoolExtraPutPanics(t *testing.T) {
+	var c counter
+	p := New(c.next, 1)
+	defer func() {
+		if r := recover(); r != ErrPoolFull {
+			t.Errorf("recovered %v, want ErrPoolFull", r)
+		}
+	}()
+	v, _ := c.next()
+	p.Put(v)
+}
+
+func TestPoolMinimumSize(t *testing.T) {
+	if got := New(func() (int, error) { return 0, nil }, 0).Max(); got != 1 {
+		t.Errorf("Max = %d, want 1", got)
+	}
+}
+
+func TestPoolFill(t *testing.T) {
+	var c counter
+	p := New(c.next, 5)
+
+	if got := fill(t, p, 3); got != 3 {
+		t.Errorf("Fill(3) created %d, want 3", got)
+	}
+	if p.Idle() != 3 || p.Created() != 3 {
+		t.Errorf("Idle = %d, Created = %d, want 3 and 3", p.Idle(), p.Created())
+	}
+	if got := fill(t, p, 2); got != 0 {
+		t.Errorf("Fill(2) with 3 idle created %d, want 0", got)
+	}
+	if got := fill(t, p, 10); got != 2 {
+		t.Errorf("Fill(10) created %d, want the 2 left under max", got)
+	}
+
+	// The filled objects are handed out before anything new is made
+	for range 5 {
+		get(t, p)
+	}
+	if got := p.Created(); got != 5 {
+		t.Errorf("Created = %d after using the filled objects, want 5", got)
+	}
+}
+
+func TestPoolFillCountsObjectsInUse(t *testing.T) {
+	var c counter
+	p := New(c.next, 2)
+	get(t, p) // held for the rest of the test
+	p.Put(get(t, p))
+
+	// One in use and one idle is already the max, so there's no room
+	if got := fill(t, p, 2); got != 0 {
+		t.Errorf("Fill(2) created %d, want 0", got)
+	}
+	if got := p.Created(); got != 2 {
+		t.Errorf("Created = %d, want 2", got)
+	}
+}
+
+func TestPoolFillConcurrentWithGet(t *testing.T) {
+	const max = 3
+	var c counter
+	p := New(c.next, max)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if _, err := p.Fill(max); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				v, err := p.Get()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				p.Put(v)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := p.Created(); got > max {
+		t.Errorf("Created = %d, want at most %d", got, max)
+	}
+}
+
+func TestPoolEvict(t *testing.T) {
+	var c counter
+	p := New(c.next, 4)
+	objs := []*int{get(t, p), get(t, p), get(t, p), get(t, p)}
+	for _, v := range objs {
+		p.Put(v)
+	}
+
+	// objs[0] was returned first, so it has waited longest
+	evicted := p.Evict(1, func(*int) bool { return true })
+	if len(evicted) != 3 || evicted[0] != objs[0] || evicted[2] != objs[2] {
+		t.Fatalf("evicted %v, want the three longest idle", evicted)
+	}
+	if p.Idle() != 1 {
+		t.Errorf("Idle = %d, want the 1 kept", p.Idle())
+	}
+	if v := get(t, p); v != objs[3] {
+		t.Errorf("Get = %d, want the kept object %d", *v, *objs[3])
+	}
+}
+
+func TestPoolEvictOnlyExpired(t *testing.T) {
+	var c counter
+	p := New(c.next, 4)
+	fill(t, p, 4)
+
+	evicted := p.Evict(0, func(v *int) bool { return *v%2 == 0 })
+	if len(evicted) != 2 {
+		t.Fatalf("evicted %d objects, want the 2 even ones", len(evicted))
+	}
+	for _, v := range evicted {
+		if *v%2 != 0 {
+			t.Errorf("evicted %d, which didn't expire", *v)
+		}
+	}
+	if p.Idle() != 2 {
+		t.Errorf("Idle = %d, want 2", p.Idle())
+	}
+}
+
+func TestPoolEvictFreesCapacity(t *testing.T) {
+	var c counter
+	p := New(c.next, 2)
+	fill(t, p, 2)
+	p.Evict(0, func(*int) bool { return true })
+
+	if got := fill(t, p, 2); got != 2 {
+		t.Errorf("Fill after evicting everything created %d, want 2", got)
+	}
+	if got := p.Created(); got != 4 {
+		t.Errorf("Created = %d, want 4 including the evicted ones", got)
+	}
+}
+
+func TestPoolNewFnErrorFreesSlot(t *testing.T) {
+	boom := errors.New("boom")
+	var fail atomic.Bool
+	var c counter
+	p := New(func() (*int, error) {
+		if fail.Load() {
+			return nil, boom
+		}
+		return c.next()
+	}, 1)
+
+	fail.Store(true)
+	if _, err := p.Get(); !errors.Is(err, boom) {
+		t.Fatalf("Get = %v, want the newFn error", err)
+	}
+	if filled, err := p.Fill(1); filled != 0 || !errors.Is(err, boom) {
+		t.Fatalf("Fill = %d, %v, want 0 and the newFn error", filled, err)
+	}
+	if got := p.Created(); got != 0 {
+		t.Errorf("Created = %d after failures, want 0", got)
+	}
+
+	// The failed attempts gave their slot back, so the only slot is usable
+	fail.Store(false)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	v, err := p.GetCtx(ctx)
+	if err != nil {
+		t.Fatalf("GetCtx after failures: %v", err)
+	}
+	p.Put(v)
+}
+
+func TestPoolExtraPutLeavesIdleAlone(t *testing.T) {
+	var c counter
+	p := New(c.next, 1)
+	p.Put(get(t, p))
+
+	func() {
+		defer func() { recover() }()
+		v, _ := c.next()
+		p.Put(v)
+	}()
+	if got := p.Idle(); got != 1 {
+		t.Errorf("Idle = %d after a rejected Put, want 1", got)
+	}
+}
@@ -0,0 +1,57 @@
+package redact_test
+
+import (
+	"testing"
+
+	"go-design-patterns/builder"
+	"go-design-patterns/factory"
+	"go-design-patterns/redact"
+	"go-design-patterns/singleton"
+)
+
+func TestRedactServerConfig(t *testing.T) {
+	cfg, err := builder.NewServerConfigBuilder().
+		Host("localhost").
+		DatabaseURL("postgresql://app:s3cret@db:5432/app").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := redact.Redact(cfg).(map[string]any)
+	if got["DatabaseURL"] != "postgresql://app:xxxxx@db:5432/app" {
+		t.Errorf("DatabaseURL = %v, want the password masked", got["DatabaseURL"])
+	}
+	if got["Host"] != "localhost" || got["Port"] != 8080 {
+		t.Errorf("untagged fields changed: Host %v, Port %v", got["Host"], got["Port"])
+	}
+}
+
+func TestRedactCreditCardProcessor(t *testing.T) {
+	p, err := factory.CreatePaymentProcessor(factory.CreditCard, map[string]string{
+		"cardNumber": "4111 1111 1111 1111",
+		"cvv":        "123",
+		"expMonth":   "12",
+		"expYear":    "2099",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := redact.Redact(p).(map[string]any)
+	if got["cardNumber"] != redact.Mask || got["cvv"] != redact.Mask {
+		t.Errorf("card secrets not masked: %v", got)
+	}
+	if got["expMonth"] != int64(12) || got["expYear"] != int64(2099) {
+		t.Errorf("expiry changed: %v", got)
+	}
+}
+
+func TestRedactConnConfig(t *testing.T) {
+	cfg := singleton.ConnConfig{Host: "db", Database: "app", User: "svc", Password: "p@ss"}
+	got := redact.Redact(cfg).(map[string]any)
+	if got["Password"] != redact.Mask {
+		t.Errorf("Password = %v, want masked", got["Password"])
+	}
+	if got["User"] != "svc" || got["Host"] != "db" {
+		t.Errorf("untagged fields changed: %v", got)
+	}
+}
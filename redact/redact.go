@@ -0,0 +1,169 @@
+// Package redact masks secrets in values before they are logged or displayed.
+//
+// It walks any value with reflection - a Visitor over the value's structure -
+// and returns a copy in which every struct field tagged `secret:"true"` is
+// replaced by Mask. Fields tagged `secret:"url"` hold URLs whose password is
+// masked while the rest of the URL stays readable.
+//
+// Types that know better how to hide their secrets (for example a map of
+// details where only some keys are sensitive) implement Redactable, and
+// Redact defers to them.
+package redact
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+)
+
+// Mask replaces every redacted value
+const Mask = "[REDACTED]"
+
+// Cycle replaces a pointer back to a value that is already being visited,
+// e.g. a prepared statement pointing at the connection that holds it
+const Cycle = "[CYCLE]"
+
+// Redactable is implemented by types that redact themselves
+type Redactable interface {
+	Redacted() any
+}
+
+var redactableType = reflect.TypeOf((*Redactable)(nil)).Elem()
+
+// Redact returns a redacted copy of v, leaving v untouched.
+//
+// Structs come back as map[string]any keyed by field name (unexported fields
+// included, so processors with private secrets are covered too), maps as
+// map[string]any, slices and arrays as []any, and other values as themselves.
+func Redact(v any) any {
+	if v == nil {
+		return nil
+	}
+	vis := visitor{path: make(map[visitKey]bool)}
+	return vis.visit(reflect.ValueOf(v))
+}
+
+// visitKey identifies a pointer target. The type is part of the key because
+// a struct and its first field share an address.
+type visitKey struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// visitor remembers the pointers on the path from the root to the value
+// being visited, so a cycle ends in Cycle instead of recursing forever.
+// Pointers shared without forming a cycle are visited each time.
+type visitor struct {
+	path map[visitKey]bool
+}
+
+func (vis visitor) visit(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	// Only exported values can be turned back into interfaces; for those,
+	// give Redactable implementations the first say.
+	if v.CanInterface() && v.Type().Implements(redactableType) {
+		if v.Kind() != reflect.Pointer || !v.IsNil() {
+			return v.Interface().(Redactable).Redacted()
+		}
+	}
+
+	// Pointers, maps and slices are the only ways back to an enclosing value
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		// An empty slice holds nothing, and its address may be shared
+		if !v.IsNil() && (v.Kind() != reflect.Slice || v.Len() > 0) {
+			key := visitKey{addr: v.Pointer(), typ: v.Type()}
+			if vis.path[key] {
+				return Cycle
+			}
+			vis.path[key] = true
+			defer delete(vis.path, key)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return vis.visit(v.Elem())
+
+	case reflect.Struct:
+		out := make(map[string]any, v.NumField())
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			value := v.Field(i)
+			switch field.Tag.Get("secret") {
+			case "true":
+				out[field.Name] = Mask
+			case "url":
+				out[field.Name] = maskURL(value.String())
+			default:
+				out[field.Name] = vis.visit(value)
+			}
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[vis.scalarString(iter.Key())] = vis.visit(iter.Value())
+		}
+		return out
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = vis.visit(v.Index(i))
+		}
+		return out
+
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.CanInterface() {
+			return v.Interface() // keeps named types like time.Duration
+		}
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		// Funcs, channels and the like carry no data worth showing
+		return nil
+	}
+}
+
+// scalarString renders a map key as a string
+func (vis visitor) scalarString(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return fmt.Sprint(vis.visit(v))
+}
+
+// maskURL hides the password in a URL the way url.URL.Redacted does. Values
+// that don't parse are masked entirely, since we can't tell where the secret is.
+func maskURL(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Mask
+	}
+	return u.Redacted()
+}
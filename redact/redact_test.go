@@ -0,0 +1,104 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type credentials struct {
+	User     string
+	Password string `secret:"true"`
+	DSN      string `secret:"url"`
+	apiKey   string `secret:"true"`
+	Timeout  time.Duration
+	Tags     []string
+	Limits   map[string]int
+}
+
+func TestRedactTaggedFields(t *testing.T) {
+	in := &credentials{
+		User:     "svc",
+		Password: "hunter2",
+		DSN:      "postgresql://svc:hunter2@db:5432/app",
+		apiKey:   "sk_live_123",
+		Timeout:  5 * time.Second,
+		Tags:     []string{"a", "b"},
+		Limits:   map[string]int{"rps": 10},
+	}
+	want := map[string]any{
+		"User":     "svc",
+		"Password": Mask,
+		"DSN":      "postgresql://svc:xxxxx@db:5432/app",
+		"apiKey":   Mask,
+		"Timeout":  5 * time.Second,
+		"Tags":     []any{"a", "b"},
+		"Limits":   map[string]any{"rps": 10},
+	}
+	if got := Redact(in); !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact =\n%#v\nwant\n%#v", got, want)
+	}
+	if in.Password != "hunter2" || in.apiKey != "sk_live_123" {
+		t.Error("Redact modified its input")
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := map[string]string{
+		"":                         "",
+		"redis://cache:6379":       "redis://cache:6379",
+		"mysql://root:pw@db/app":   "mysql://root:xxxxx@db/app",
+		"postgresql://%zz@db/oops": Mask, // unparseable, so masked whole
+	}
+	for raw, want := range tests {
+		got := Redact(credentials{DSN: raw}).(map[string]any)["DSN"]
+		if got != want {
+			t.Errorf("DSN %q redacted to %q, want %q", raw, got, want)
+		}
+	}
+}
+
+type selfRedacting struct{ secret string }
+
+func (s selfRedacting) Redacted() any { return "custom" }
+
+func TestRedactRedactable(t *testing.T) {
+	got := Redact(map[string]any{"item": selfRedacting{"x"}, "nested": []any{&selfRedacting{"y"}}})
+	want := map[string]any{"item": "custom", "nested": []any{"custom"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact = %#v, want %#v", got, want)
+	}
+	if Redact(nil) != nil || Redact((*selfRedacting)(nil)) != nil {
+		t.Error("nil values should redact to nil")
+	}
+}
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestRedactCycles(t *testing.T) {
+	a := &node{Name: "a"}
+	a.Next = &node{Name: "b", Next: a}
+	want := map[string]any{"Name": "a", "Next": map[string]any{"Name": "b", "Next": Cycle}}
+	if got := Redact(a); !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact(a -> b -> a) = %#v, want %#v", got, want)
+	}
+
+	m := map[string]any{"k": "v"}
+	m["self"] = m
+	if got := Redact(m).(map[string]any)["self"]; got != Cycle {
+		t.Errorf("self-referencing map redacted to %#v, want Cycle", got)
+	}
+}
+
+func TestRedactSharedPointers(t *testing.T) {
+	// The same pointer twice without a cycle is shown both times
+	shared := &node{Name: "shared"}
+	got := Redact([]*node{shared, shared})
+	want := []any{map[string]any{"Name": "shared", "Next": nil}, map[string]any{"Name": "shared", "Next": nil}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package singleton
+
+import (
+	"errors"
+	"sync"
+
+	"go-design-patterns/builder"
+)
+
+// ErrConfigLoaded is returned by SetConfigLoader once the config has been loaded
+var ErrConfigLoaded = errors.New("server config already loaded")
+
+// The same lesson as the database connection, applied to configuration:
+// one *builder.ServerConfig, loaded lazily on first use and shared by the
+// whole app. Unlike the connection it can be replaced at runtime.
+var (
+	serverConfigMu     sync.RWMutex
+	serverConfig       *builder.ServerConfig
+	serverConfigErr    error
+	serverConfigLoaded bool
+	serverConfigLoader = defaultConfigLoader
+)
+
+// defaultConfigLoader builds a localhost config, overridden by any APP_*
+// environment variables (see builder.LoadEnv)
+func defaultConfigLoader() (*builder.ServerConfig, error) {
+	b, err := builder.NewServerConfigBuilder().Host("localhost").LoadEnv("APP")
+	if err != nil {
+		return nil, err
+	}
+	return b.Build()
+}
+
+// SetConfigLoader replaces the function GetConfig uses to load the config
+// on first use. It must be called before that; afterwards it returns
+// ErrConfigLoaded.
+func SetConfigLoader(load func() (*builder.ServerConfig, error)) error {
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+	if serverConfigLoaded {
+		return ErrConfigLoaded
+	}
+	serverConfigLoader = load
+	return nil
+}
+
+// GetConfig returns the app-wide server config, loading it on the first
+// call. Every caller gets the same instance until ReloadConfig replaces it.
+// If loading fails, the error is returned now and on every later call.
+func GetConfig() (*builder.ServerConfig, error) {
+	serverConfigMu.RLock()
+	if serverConfigLoaded {
+		defer serverConfigMu.RUnlock()
+		return serverConfig, serverConfigErr
+	}
+	serverConfigMu.RUnlock()
+
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+	// Someone else may have loaded it while we waited for the write lock
+	if !serverConfigLoaded {
+		serverConfig, serverConfigErr = serverConfigLoader()
+		serverConfigLoaded = true
+		if serverConfigErr == nil {
+			logger.Printf("Server config loaded (%s)\n", serverConfig.ListenAddr())
+		}
+	}
+	return serverConfig, serverConfigErr
+}
+
+// ReloadConfig swaps in a new config for hot updates. Callers of GetConfig
+// see either the old config or the new one, never a mix; anyone holding the
+// old one keeps it unchanged. next is validated with the Build() rules
+// first and rejected if it fails them. Reloading before the first GetConfig
+// skips the loader entirely.
+func ReloadConfig(next *builder.ServerConfig) error {
+	if next == nil {
+		return errors.New("config must not be nil")
+	}
+	if _, err := builder.Resolve(next); err != nil {
+		return err
+	}
+
+	serverConfigMu.Lock()
+	defer serverConfigMu.Unlock()
+	serverConfig, serverConfigErr = next, nil
+	serverConfigLoaded = true
+	logger.Printf("Server config reloaded (%s)\n", next.ListenAddr())
+	return nil
+}
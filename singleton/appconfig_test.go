@@ -0,0 +1,183 @@
+package singleton
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go-design-patterns/builder"
+)
+
+// resetServerConfig starts the test with no config loaded and puts the
+// previous state back afterwards
+func resetServerConfig(t *testing.T) {
+	t.Helper()
+	serverConfigMu.Lock()
+	config, err, loaded, loader := serverConfig, serverConfigErr, serverConfigLoaded, serverConfigLoader
+	serverConfig, serverConfigErr, serverConfigLoaded, serverConfigLoader = nil, nil, false, defaultConfigLoader
+	serverConfigMu.Unlock()
+	t.Cleanup(func() {
+		serverConfigMu.Lock()
+		defer serverConfigMu.Unlock()
+		serverConfig, serverConfigErr, serverConfigLoaded, serverConfigLoader = config, err, loaded, loader
+	})
+}
+
+// configOnPort builds a valid localhost config on port
+func configOnPort(t *testing.T, port int) *builder.ServerConfig {
+	t.Helper()
+	config, err := builder.NewServerConfigBuilder().Host("localhost").Port(port).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func TestGetConfigLoadsOnce(t *testing.T) {
+	resetServerConfig(t)
+	var loads atomic.Int32
+	want := configOnPort(t, 9000)
+	if err := SetConfigLoader(func() (*builder.ServerConfig, error) {
+		loads.Add(1)
+		return want, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			config, err := GetConfig()
+			if err != nil || config != want {
+				t.Errorf("GetConfig = %p, %v, want the loaded instance %p", config, err, want)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loader ran %d times, want 1", n)
+	}
+	if err := SetConfigLoader(defaultConfigLoader); !errors.Is(err, ErrConfigLoaded) {
+		t.Errorf("SetConfigLoader after loading = %v, want ErrConfigLoaded", err)
+	}
+}
+
+func TestGetConfigLoadErrorSticks(t *testing.T) {
+	resetServerConfig(t)
+	errBroken := errors.New("config file unreadable")
+	var loads int
+	SetConfigLoader(func() (*builder.ServerConfig, error) {
+		loads++
+		return nil, errBroken
+	})
+
+	for range 2 {
+		if config, err := GetConfig(); !errors.Is(err, errBroken) || config != nil {
+			t.Errorf("GetConfig = %v, %v, want the load error", config, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("loader ran %d times, want 1", loads)
+	}
+
+	// A reload recovers from a failed load
+	if err := ReloadConfig(configOnPort(t, 9000)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetConfig(); err != nil {
+		t.Errorf("GetConfig after reload = %v", err)
+	}
+}
+
+func TestGetConfigDefaultLoader(t *testing.T) {
+	resetServerConfig(t)
+	t.Setenv("APP_PORT", "9123")
+
+	config, err := GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "localhost" || config.Port != 9123 {
+		t.Errorf("config = %s:%d, want localhost:9123 from the environment", config.Host, config.Port)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	resetServerConfig(t)
+	first := configOnPort(t, 9000)
+	SetConfigLoader(func() (*builder.ServerConfig, error) { return first, nil })
+	held, _ := GetConfig()
+
+	next := configOnPort(t, 9001)
+	if err := ReloadConfig(next); err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	if got, _ := GetConfig(); got != next {
+		t.Errorf("GetConfig after reload = %p, want the new instance %p", got, next)
+	}
+	if held.Port != 9000 {
+		t.Errorf("held config changed to port %d", held.Port)
+	}
+
+	invalid := *next
+	invalid.Port = 70000
+	if err := ReloadConfig(&invalid); err == nil {
+		t.Error("ReloadConfig accepted an invalid config")
+	}
+	if err := ReloadConfig(nil); err == nil {
+		t.Error("ReloadConfig accepted nil")
+	}
+	if got, _ := GetConfig(); got != next {
+		t.Error("a rejected reload replaced the config")
+	}
+}
+
+func TestReloadConfigBeforeLoad(t *testing.T) {
+	resetServerConfig(t)
+	SetConfigLoader(func() (*builder.ServerConfig, error) {
+		t.Error("loader ran after ReloadConfig")
+		return nil, nil
+	})
+	next := configOnPort(t, 9000)
+	if err := ReloadConfig(next); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := GetConfig(); got != next {
+		t.Errorf("GetConfig = %p, want the reloaded %p", got, next)
+	}
+}
+
+func TestReloadConfigConcurrent(t *testing.T) {
+	resetServerConfig(t)
+	configs := []*builder.ServerConfig{configOnPort(t, 9000), configOnPort(t, 9001), configOnPort(t, 9002)}
+	if err := ReloadConfig(configs[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				if err := ReloadConfig(configs[(i+j)%len(configs)]); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				config, err := GetConfig()
+				if err != nil || (config != configs[0] && config != configs[1] && config != configs[2]) {
+					t.Errorf("GetConfig = %p, %v, want one of the reloaded instances", config, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,124 @@
+package singleton
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DB is the query surface shared by DatabaseConnection and anything wrapping it.
+// Depending on DB instead of *DatabaseConnection lets proxies like CachingDB
+// slot in transparently.
+type DB interface {
+	QueryArgs(sql string, args ...any) ([]map[string]any, error)
+}
+
+// CachingDB is a caching proxy in front of a DB.
+// SELECT results are memoized by SQL and arguments for a TTL; any other
+// statement (INSERT, UPDATE, ...) may change data, so it empties the cache.
+type CachingDB struct {
+	inner      DB
+	ttl        time.Duration
+	maxEntries int
+	clock      Clock
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	rows    []map[string]any
+	expires time.Time
+}
+
+// NewCachingDB wraps inner with a result cache.
+// maxEntries <= 0 means the cache size is unbounded.
+func NewCachingDB(inner DB, ttl time.Duration, maxEntries int) *CachingDB {
+	return &CachingDB{
+		inner:      inner,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		clock:      realClock{},
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+// SetClock replaces the clock used to expire entries
+func (c *CachingDB) SetClock(clock Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// QueryArgs serves SELECTs from the cache when possible and forwards everything else.
+// Cached rows are shared between callers and must not be modified.
+func (c *CachingDB) QueryArgs(sql string, args ...any) ([]map[string]any, error) {
+	if !isSelect(sql) {
+		rows, err := c.inner.QueryArgs(sql, args...)
+		c.Invalidate()
+		return rows, err
+	}
+
+	key := fmt.Sprintf("%s\x00%#v", sql, args)
+
+	c.mu.Lock()
+	now := c.clock.Now()
+	if entry, ok := c.entries[key]; ok && now.Before(entry.expires) {
+		c.mu.Unlock()
+		return entry.rows, nil
+	}
+	c.mu.Unlock()
+
+	rows, err := c.inner.QueryArgs(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evictLocked(now)
+	}
+	c.entries[key] = cacheEntry{rows: rows, expires: now.Add(c.ttl)}
+	return rows, nil
+}
+
+// Invalidate drops every cached result
+func (c *CachingDB) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cacheEntry)
+}
+
+// Len returns the number of cached results, including expired ones not yet evicted
+func (c *CachingDB) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evictLocked removes expired entries, or the one closest to expiring if none are.
+// The caller must hold c.mu.
+func (c *CachingDB) evictLocked(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// isSelect reports whether sql is a read-only SELECT statement
+func isSelect(sql string) bool {
+	fields := strings.Fields(sql)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "SELECT")
+}
@@ -0,0 +1,123 @@
+package singleton
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingDB is a DB that counts the queries it answers
+type countingDB struct {
+	calls map[string]int
+	err   error
+}
+
+func (c *countingDB) QueryArgs(sql string, args ...any) ([]map[string]any, error) {
+	if c.calls == nil {
+		c.calls = make(map[string]int)
+	}
+	c.calls[sql]++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []map[string]any{{"sql": sql, "args": args}}, nil
+}
+
+func TestCachingDBHitsCache(t *testing.T) {
+	inner := &countingDB{}
+	db := NewCachingDB(inner, time.Minute, 0)
+
+	for range 3 {
+		if _, err := db.QueryArgs("SELECT * FROM users WHERE id = ?", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := inner.calls["SELECT * FROM users WHERE id = ?"]; got != 1 {
+		t.Errorf("inner queried %d times, want 1", got)
+	}
+
+	// Different arguments are a different query
+	if _, err := db.QueryArgs("SELECT * FROM users WHERE id = ?", 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := inner.calls["SELECT * FROM users WHERE id = ?"]; got != 2 {
+		t.Errorf("inner queried %d times, want 2", got)
+	}
+}
+
+func TestCachingDBWriteInvalidates(t *testing.T) {
+	inner := &countingDB{}
+	db := NewCachingDB(inner, time.Minute, 0)
+
+	db.QueryArgs("SELECT * FROM users")
+	if _, err := db.QueryArgs("UPDATE users SET name = ?", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if db.Len() != 0 {
+		t.Errorf("Len() = %d after a write, want 0", db.Len())
+	}
+	db.QueryArgs("SELECT * FROM users")
+	if got := inner.calls["SELECT * FROM users"]; got != 2 {
+		t.Errorf("inner queried %d times, want 2", got)
+	}
+}
+
+func TestCachingDBExpires(t *testing.T) {
+	inner := &countingDB{}
+	clock := newFakeClock()
+	db := NewCachingDB(inner, time.Minute, 0)
+	db.SetClock(clock)
+
+	db.QueryArgs("SELECT 1")
+	clock.Advance(59 * time.Second)
+	db.QueryArgs("SELECT 1")
+	clock.Advance(time.Second)
+	db.QueryArgs("SELECT 1")
+	if got := inner.calls["SELECT 1"]; got != 2 {
+		t.Errorf("inner queried %d times, want 2", got)
+	}
+}
+
+func TestCachingDBMaxEntries(t *testing.T) {
+	inner := &countingDB{}
+	clock := newFakeClock()
+	db := NewCachingDB(inner, time.Minute, 2)
+	db.SetClock(clock)
+
+	db.QueryArgs("SELECT 1")
+	clock.Advance(time.Second)
+	db.QueryArgs("SELECT 2")
+	clock.Advance(time.Second)
+	db.QueryArgs("SELECT 3")
+	if db.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", db.Len())
+	}
+
+	// The oldest entry made room for the newest
+	db.QueryArgs("SELECT 3")
+	db.QueryArgs("SELECT 1")
+	if inner.calls["SELECT 3"] != 1 || inner.calls["SELECT 1"] != 2 {
+		t.Errorf("calls = %v, want SELECT 1 evicted and SELECT 3 cached", inner.calls)
+	}
+}
+
+func TestCachingDBDoesNotCacheErrors(t *testing.T) {
+	inner := &countingDB{err: errors.New("boom")}
+	db := NewCachingDB(inner, time.Minute, 0)
+
+	for range 2 {
+		if _, err := db.QueryArgs("SELECT 1"); err == nil {
+			t.Fatal("QueryArgs succeeded, want the inner error")
+		}
+	}
+	if inner.calls["SELECT 1"] != 2 || db.Len() != 0 {
+		t.Errorf("failed queries were cached: %d calls, Len() = %d", inner.calls["SELECT 1"], db.Len())
+	}
+}
+
+func TestCachingDBOverConnection(t *testing.T) {
+	var db DB = NewCachingDB(connected(t), time.Minute, 0)
+	if _, err := db.QueryArgs("SELECT * FROM users"); err != nil {
+		t.Errorf("QueryArgs through the proxy: %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package singleton
+
+import "time"
+
+// Clock tells the package what time it is.
+// Production code uses the real clock; tests can substitute a fake one
+// to make TTLs and timeouts deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the default Clock backed by time.Now
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
@@ -0,0 +1,162 @@
+package singleton
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultConnectionString is used when Init is never called
+const DefaultConnectionString = "postgresql://localhost:5432/mydb"
+
+// ErrAlreadyInitialized is returned by Init once the singleton has been created
+var ErrAlreadyInitialized = errors.New("database connection already initialized")
+
+// defaultPorts maps a scheme to the port used when the string doesn't name one
+var defaultPorts = map[string]int{
+	"postgresql": 5432,
+	"postgres":   5432,
+	"mysql":      3306,
+}
+
+// ConnInfo holds the parsed, normalized parts of a connection string
+type ConnInfo struct {
+	Scheme   string
+	Host     string
+	Port     int
+	Database string
+	User     string
+	Password string `secret:"true"`
+	SSLMode  string // the "sslmode" query parameter, empty if not given
+}
+
+// String reassembles the normalized connection string
+func (c ConnInfo) String() string {
+	u := url.URL{
+		Scheme: c.Scheme,
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:   "/" + c.Database,
+	}
+	if c.Password != "" {
+		u.User = url.UserPassword(c.User, c.Password)
+	} else if c.User != "" {
+		u.User = url.User(c.User)
+	}
+	if c.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": {c.SSLMode}}.Encode()
+	}
+	return u.String()
+}
+
+// ParseConnectionString parses and normalizes a URL-style connection string
+// such as "postgresql://user@localhost/mydb". The scheme is lowercased and
+// the scheme's default port is filled in when missing. Host and database
+// name are required.
+func ParseConnectionString(conn string) (ConnInfo, error) {
+	u, err := url.Parse(strings.TrimSpace(conn))
+	if err != nil {
+		return ConnInfo{}, fmt.Errorf("invalid connection string: %w", err)
+	}
+
+	info := ConnInfo{
+		Scheme:   strings.ToLower(u.Scheme),
+		Host:     u.Hostname(),
+		Database: strings.TrimPrefix(u.Path, "/"),
+		User:     u.User.Username(),
+		SSLMode:  u.Query().Get("sslmode"),
+	}
+	info.Password, _ = u.User.Password()
+
+	if info.Scheme == "" {
+		return ConnInfo{}, errors.New("connection string is missing a scheme, e.g. postgresql://")
+	}
+	if info.Host == "" {
+		return ConnInfo{}, errors.New("connection string is missing a host")
+	}
+	if info.Database == "" {
+		return ConnInfo{}, errors.New("connection string is missing a database name")
+	}
+
+	if port := u.Port(); port != "" {
+		info.Port, err = strconv.Atoi(port)
+		if err != nil || info.Port <= 0 || info.Port > 65535 {
+			return ConnInfo{}, fmt.Errorf("invalid port %q", port)
+		}
+	} else if p, ok := defaultPorts[info.Scheme]; ok {
+		info.Port = p
+	} else {
+		return ConnInfo{}, fmt.Errorf("no port given and no default known for scheme %q", info.Scheme)
+	}
+	return info, nil
+}
+
+var defaultConnInfo = mustParseConnectionString(DefaultConnectionString)
+
+var (
+	configMu   sync.Mutex
+	connInfo   = defaultConnInfo
+	connClaims bool // set once a lazy singleton has been built from connInfo
+)
+
+// Init configures the connection used when the singleton is first created.
+// It must be called before the first GetInstance; afterwards it returns
+// ErrAlreadyInitialized, since the existing instance can't be reconfigured.
+// ${VAR} references in the config's fields are expanded first, as they are
+// for InitFromString; see ResolveSecrets.
+func Init(cfg ConnConfig) error {
+	cfg, err := cfg.resolveSecrets(os.LookupEnv)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	return initConnInfo(cfg.connInfo())
+}
+
+// InitFromString is Init for a URL-style connection string.
+// ${VAR} references are expanded first; see ResolveSecrets.
+func InitFromString(conn string) error {
+	conn, err := ResolveSecrets(conn)
+	if err != nil {
+		return err
+	}
+	info, err := ParseConnectionString(conn)
+	if err != nil {
+		return err
+	}
+	return initConnInfo(info)
+}
+
+func initConnInfo(info ConnInfo) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	if connClaims {
+		return ErrAlreadyInitialized
+	}
+	connInfo = info
+	return nil
+}
+
+// claimConnInfo returns the configured connection info and freezes it,
+// so a later Init can't silently disagree with the instance that exists.
+func claimConnInfo() ConnInfo {
+	configMu.Lock()
+	defer configMu.Unlock()
+	connClaims = true
+	return connInfo
+}
+
+func mustParseConnectionString(conn string) ConnInfo {
+	info, err := ParseConnectionString(conn)
+	if err != nil {
+		panic(err)
+	}
+	return info
+}
@@ -0,0 +1,77 @@
+package singleton
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseConnectionString(t *testing.T) {
+	tests := []struct {
+		conn string
+		want ConnInfo
+	}{
+		{"postgresql://localhost/mydb", ConnInfo{Scheme: "postgresql", Host: "localhost", Port: 5432, Database: "mydb"}},
+		{"POSTGRES://db.internal/app", ConnInfo{Scheme: "postgres", Host: "db.internal", Port: 5432, Database: "app"}},
+		{"mysql://root@db/shop", ConnInfo{Scheme: "mysql", Host: "db", Port: 3306, Database: "shop", User: "root"}},
+		{"postgresql://svc:p%40ss@db:6543/app?sslmode=require", ConnInfo{Scheme: "postgresql", Host: "db", Port: 6543, Database: "app", User: "svc", Password: "p@ss", SSLMode: "require"}},
+		{"  postgresql://[::1]/mydb  ", ConnInfo{Scheme: "postgresql", Host: "::1", Port: 5432, Database: "mydb"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.conn, func(t *testing.T) {
+			got, err := ParseConnectionString(tt.conn)
+			if err != nil {
+				t.Fatalf("ParseConnectionString: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConnectionStringRejects(t *testing.T) {
+	tests := map[string]string{
+		"missing database": "postgresql://localhost",
+		"empty database":   "postgresql://localhost:5432/",
+		"missing scheme":   "localhost/mydb",
+		"missing host":     "postgresql:///mydb",
+		"bad port":         "postgresql://localhost:99999/mydb",
+		"unknown scheme":   "oracle://localhost/mydb",
+		"unparsable":       "postgresql://local host/%zz",
+	}
+	for name, conn := range tests {
+		if info, err := ParseConnectionString(conn); err == nil {
+			t.Errorf("%s: ParseConnectionString(%q) = %+v, want an error", name, conn, info)
+		}
+	}
+}
+
+func TestConnInfoStringRoundTrip(t *testing.T) {
+	for _, conn := range []string{
+		"postgresql://localhost:5432/mydb",
+		"postgresql://svc:p%40ss@db:5432/app?sslmode=require",
+		"postgresql://[::1]:5432/mydb",
+	} {
+		info, err := ParseConnectionString(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.String(); got != conn {
+			t.Errorf("String() = %q, want %q", got, conn)
+		}
+		again, err := ParseConnectionString(info.String())
+		if err != nil || again != info {
+			t.Errorf("reparsing %q gave %+v, %v", info.String(), again, err)
+		}
+	}
+}
+
+func TestInitFromStringAfterGetInstance(t *testing.T) {
+	GetInstance()
+	if err := InitFromString("postgresql://localhost"); err == nil || errors.Is(err, ErrAlreadyInitialized) {
+		t.Errorf("InitFromString with no database = %v, want a parse error", err)
+	}
+	if err := InitFromString("postgresql://localhost/other"); !errors.Is(err, ErrAlreadyInitialized) {
+		t.Errorf("InitFromString after GetInstance = %v, want ErrAlreadyInitialized", err)
+	}
+}
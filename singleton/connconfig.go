@@ -0,0 +1,145 @@
+package singleton
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// sslModes are the sslmode values PostgreSQL accepts
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// ConnConfig describes a PostgreSQL connection field by field, so callers
+// don't assemble (and mis-escape) connection strings by hand
+type ConnConfig struct {
+	Host     string
+	Port     int // 0 means the PostgreSQL default, 5432
+	Database string
+	User     string
+	Password string `secret:"true"`
+	SSLMode  string // one of sslModes; empty leaves it to the server
+}
+
+// Validate checks that the required fields are present and the rest are in range
+func (c ConnConfig) Validate() error {
+	switch {
+	case c.Host == "":
+		return errors.New("connection config is missing a host")
+	case c.Database == "":
+		return errors.New("connection config is missing a database name")
+	case c.Port < 0 || c.Port > 65535:
+		return fmt.Errorf("invalid port %d", c.Port)
+	case c.SSLMode != "" && !slices.Contains(sslModes, c.SSLMode):
+		return fmt.Errorf("invalid sslmode %q, want one of: %s", c.SSLMode, strings.Join(sslModes, ", "))
+	}
+	return nil
+}
+
+// DSN assembles the connection string, escaping the user and password:
+//
+//	ConnConfig{Host: "db", Database: "app", User: "svc", Password: "p@ss", SSLMode: "require"}.DSN()
+//	// "postgresql://svc:p%40ss@db:5432/app?sslmode=require"
+func (c ConnConfig) DSN() string {
+	return c.connInfo().String()
+}
+
+// connInfo converts the config to the normalized form the singleton uses
+func (c ConnConfig) connInfo() ConnInfo {
+	info := ConnInfo{
+		Scheme:   "postgresql",
+		Host:     c.Host,
+		Port:     c.Port,
+		Database: c.Database,
+		User:     c.User,
+		Password: c.Password,
+		SSLMode:  c.SSLMode,
+	}
+	if info.Port == 0 {
+		info.Port = defaultPorts[info.Scheme]
+	}
+	return info
+}
+
+// ErrConfigMismatch is reported when the singleton already exists with a
+// different configuration than the one asked for
+var ErrConfigMismatch = errors.New("database connection already initialized with a different config")
+
+// GetInstanceWithConfig returns the singleton, creating it from cfg if it
+// doesn't exist yet. The first caller's config wins; later callers get the
+// same instance whatever config they pass, and a warning is logged when it
+// differs. Use GetInstanceWithConfigChecked to get that warning as an error.
+func GetInstanceWithConfig(cfg ConnConfig) *DatabaseConnection {
+	db, err := GetInstanceWithConfigChecked(cfg)
+	if err != nil {
+		logger.Printf("Warning: %v\n", err)
+	}
+	return db
+}
+
+// GetInstanceWithConfigChecked is GetInstanceWithConfig, but reports a
+// config that differs from the existing instance's as an error wrapping
+// ErrConfigMismatch. The instance is returned either way.
+func GetInstanceWithConfigChecked(cfg ConnConfig) (*DatabaseConnection, error) {
+	want := cfg.connInfo()
+
+	// Claiming under configMu makes "first caller wins" atomic: no other
+	// caller can swap connInfo between our check and the instance creation
+	configMu.Lock()
+	if !connClaims {
+		connInfo = want
+		connClaims = true
+	}
+	got := connInfo
+	configMu.Unlock()
+
+	db := GetInstance()
+	if got != want {
+		return db, fmt.Errorf("%w: have %s, asked for %s", ErrConfigMismatch, got.redacted(), want.redacted())
+	}
+	return db, nil
+}
+
+// redacted is the connection string with the password masked, safe for logs
+func (c ConnInfo) redacted() string {
+	u, err := url.Parse(c.String())
+	if err != nil {
+		return c.Scheme + "://" + c.Host
+	}
+	return u.Redacted()
+}
+
+// Redacted describes the connection with its password masked, for
+// redact.Redact. Walking the struct itself would race with queries and loop
+// through the statements, which point back at the connection, so the
+// summary is taken under db.mu instead.
+func (db *DatabaseConnection) Redacted() any {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	summary := map[string]any{
+		"ConnectionID":     db.connectionID,
+		"ConnectionString": db.connInfo.redacted(),
+		"State":            db.state.String(),
+		"Statements":       len(db.stmts),
+		"QueryCount":       db.queryCount,
+	}
+	if db.replica != nil {
+		summary["Replica"] = db.replica.info.redacted()
+	}
+	return summary
+}
+
+// Redacted describes the statement for redact.Redact. Its connection is
+// unexported, so the walker couldn't ask it to redact itself and would read
+// its fields without holding db.mu.
+func (s *Stmt) Redacted() any {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return map[string]any{
+		"SQL":          s.sql,
+		"Placeholders": s.placeholders,
+		"Closed":       s.closed,
+		"ConnectionID": s.db.connectionID,
+	}
+}
@@ -0,0 +1,124 @@
+package singleton
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetInstanceWithConfigCompeting(t *testing.T) {
+	configs := []ConnConfig{
+		{Host: "localhost", Database: "mydb"}, // DefaultConnectionString
+		{Host: "db-a", Database: "app", User: "svc", Password: "secret-a"},
+		{Host: "db-b", Database: "app", User: "svc", Password: "secret-b"},
+	}
+
+	type outcome struct {
+		cfg ConnConfig
+		db  *DatabaseConnection
+		err error
+	}
+	outcomes := make([]outcome, 30)
+	var wg sync.WaitGroup
+	for i := range outcomes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cfg := configs[i%len(configs)]
+			db, err := GetInstanceWithConfigChecked(cfg)
+			outcomes[i] = outcome{cfg, db, err}
+		}()
+	}
+	wg.Wait()
+
+	// Whichever config got there first wins; every caller shares its instance
+	winners := map[ConnConfig]bool{}
+	for _, o := range outcomes {
+		if o.db != outcomes[0].db {
+			t.Fatal("callers got different instances")
+		}
+		switch {
+		case o.err == nil:
+			winners[o.cfg] = true
+		case !errors.Is(o.err, ErrConfigMismatch):
+			t.Errorf("unexpected error: %v", o.err)
+		case strings.Contains(o.err.Error(), "secret-"):
+			t.Errorf("mismatch error leaks a password: %v", o.err)
+		}
+	}
+	if len(winners) != 1 {
+		t.Fatalf("%d configs were accepted, want exactly one", len(winners))
+	}
+	for cfg := range winners {
+		if got := outcomes[0].db.connInfo; got != cfg.connInfo() {
+			t.Errorf("instance uses %s, want the winning config", got.redacted())
+		}
+	}
+}
+
+func TestGetInstanceWithConfigLogsMismatch(t *testing.T) {
+	log := captureLog(t)
+	db := GetInstanceWithConfig(ConnConfig{Host: "elsewhere", Database: "other"})
+	if db != GetInstance() {
+		t.Error("GetInstanceWithConfig returned a different instance")
+	}
+	if !strings.Contains(log.String(), "Warning: "+ErrConfigMismatch.Error()) {
+		t.Errorf("log = %q, want a mismatch warning", log.String())
+	}
+}
+
+func TestConnConfigValidate(t *testing.T) {
+	tests := map[string]ConnConfig{
+		"no host":     {Database: "app"},
+		"no database": {Host: "db"},
+		"bad port":    {Host: "db", Database: "app", Port: 70000},
+		"bad sslmode": {Host: "db", Database: "app", SSLMode: "always"},
+	}
+	for name, cfg := range tests {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: Validate accepted %+v", name, cfg)
+		}
+	}
+	cfg := ConnConfig{Host: "db", Database: "app", User: "svc", Password: "p@ss", SSLMode: "require"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate = %v", err)
+	}
+	if got, want := cfg.DSN(), "postgresql://svc:p%40ss@db:5432/app?sslmode=require"; got != want {
+		t.Errorf("DSN = %q, want %q", got, want)
+	}
+}
+
+func TestConnConfigDSN(t *testing.T) {
+	tests := []struct {
+		cfg  ConnConfig
+		want string
+	}{
+		{ConnConfig{Host: "db", Database: "app"}, "postgresql://db:5432/app"},
+		{ConnConfig{Host: "db", Port: 6543, Database: "app", User: "svc"}, "postgresql://svc@db:6543/app"},
+		{ConnConfig{Host: "db", Database: "app", User: "svc", Password: "p@ss:w/rd"}, "postgresql://svc:p%40ss%3Aw%2Frd@db:5432/app"},
+		{ConnConfig{Host: "db", Database: "app", SSLMode: "verify-full"}, "postgresql://db:5432/app?sslmode=verify-full"},
+		{ConnConfig{Host: "::1", Database: "app"}, "postgresql://[::1]:5432/app"},
+	}
+	for _, tt := range tests {
+		got := tt.cfg.DSN()
+		if got != tt.want {
+			t.Errorf("DSN() = %q, want %q", got, tt.want)
+		}
+		// The DSN parses back to the same fields
+		info, err := ParseConnectionString(got)
+		if err != nil || info != tt.cfg.connInfo() {
+			t.Errorf("ParseConnectionString(%q) = %+v, %v, want %+v", got, info, err, tt.cfg.connInfo())
+		}
+	}
+}
+
+func TestInitValidatesFirst(t *testing.T) {
+	GetInstance()
+	if err := Init(ConnConfig{Host: "db"}); err == nil || errors.Is(err, ErrAlreadyInitialized) {
+		t.Errorf("Init without a database = %v, want a validation error", err)
+	}
+	if err := Init(ConnConfig{Host: "db", Database: "app"}); !errors.Is(err, ErrAlreadyInitialized) {
+		t.Errorf("Init after GetInstance = %v, want ErrAlreadyInitialized", err)
+	}
+}
@@ -0,0 +1,54 @@
+package singleton
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Double-Checked Locking (DCL)
+//
+// This is the "classic" way to build a lazy singleton, shown here for comparison
+// with the sync.Once version in GetInstance. The idea:
+//
+//  1. Check if the instance exists without locking (cheap).
+//  2. If not, take the lock and check again, because another goroutine may
+//     have created it while we were waiting for the lock.
+//  3. Only then create and publish the instance.
+//
+// Why naive DCL is broken elsewhere: in Java (before volatile), C++ (before
+// std::atomic) and similar, the first unlocked check reads a plain pointer.
+// The compiler or CPU may reorder the writes that build the object with the
+// write that publishes the pointer, so another thread can see a non-nil
+// pointer to an object whose fields are not initialized yet.
+//
+// Why it's correct here: the pointer is an atomic.Pointer. Store publishes the
+// connection only after it is fully built, and any Load that observes it is
+// guaranteed to observe all writes made before the Store. Plain reads of a
+// shared pointer would be a data race in Go too - the atomic is what makes it safe.
+
+var (
+	dclInstance atomic.Pointer[DatabaseConnection]
+	dclMu       sync.Mutex
+)
+
+// GetInstanceDCL returns a lazily created DatabaseConnection using double-checked locking.
+// It behaves like GetInstance but manages its own instance.
+func GetInstanceDCL() *DatabaseConnection {
+	// First check: lock-free fast path once the instance exists
+	if db := dclInstance.Load(); db != nil {
+		return db
+	}
+
+	dclMu.Lock()
+	defer dclMu.Unlock()
+
+	// Second check: someone may have won the race while we waited for the lock
+	if db := dclInstance.Load(); db != nil {
+		return db
+	}
+
+	db := newConnection(claimConnInfo())
+	dclInstance.Store(db)
+	logger.Printf("Database connection instance created (ID: %d)\n", db.connectionID)
+	return db
+}
@@ -0,0 +1,36 @@
+package singleton
+
+import (
+	"sync"
+	"testing"
+)
+
+// Run with -race: the unlocked first check must not race with publication
+func TestGetInstanceDCLConcurrent(t *testing.T) {
+	const goroutines = 64
+	got := make([]*DatabaseConnection, goroutines)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			got[i] = GetInstanceDCL()
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for i, db := range got {
+		if db == nil || db != got[0] {
+			t.Fatalf("goroutine %d got %p, want %p", i, db, got[0])
+		}
+	}
+	if got[0] == GetInstance() {
+		t.Error("GetInstanceDCL shares GetInstance's instance, want its own")
+	}
+	if got := got[0].State(); got != Disconnected {
+		t.Errorf("new DCL instance is %v, want Disconnected", got)
+	}
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func main() {
-	fmt.Println("=== Singleton Pattern Demo ===\n")
+	fmt.Print("=== Singleton Pattern Demo ===\n\n")
 
 	// Demonstrate that multiple calls to GetInstance() return the same instance
 	fmt.Println("1. Getting multiple instances:")
@@ -22,7 +22,7 @@ func main() {
 	fmt.Printf("   db3 connection ID: %d\n", db3.GetConnectionID())
 
 	if db1 == db2 && db2 == db3 {
-		fmt.Println("   ✓ All references point to the same instance!\n")
+		fmt.Print("   ✓ All references point to the same instance!\n\n")
 	}
 
 	// Demonstrate usage
@@ -63,7 +63,7 @@ func main() {
 
 	if allSame {
 		fmt.Printf("   ✓ All %d goroutines received the same instance (ID: %d)\n", len(instances), firstID)
-		fmt.Println("   ✓ Thread-safety verified!\n")
+		fmt.Print("   ✓ Thread-safety verified!\n\n")
 	}
 
 	// Demonstrate that the instance persists across function calls
@@ -0,0 +1,43 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDraining is returned for new queries while the connection is draining
+var ErrDraining = errors.New("connection is draining")
+
+// Drain shuts the connection down gracefully: new queries are rejected with
+// ErrDraining right away, queries already running are allowed to finish, and
+// then the connection is closed. If ctx ends first, the connection is closed
+// anyway and ctx's error is returned.
+func (db *DatabaseConnection) Drain(ctx context.Context) error {
+	db.mu.Lock()
+	if db.state == Closed {
+		db.mu.Unlock()
+		return nil
+	}
+	db.draining = true
+	db.mu.Unlock()
+	logger.Printf("Draining database connection (ID: %d)\n", db.connectionID)
+
+	// No new in-flight queries can start once draining is set, so Wait is safe here
+	done := make(chan struct{})
+	go func() {
+		db.inFlight.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if closeErr := db.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowQuery starts a QueryTimeout that stays in flight until release is
+// closed. It returns once the query is running; the query's error arrives on
+// the returned channel.
+func slowQuery(t *testing.T, db *DatabaseConnection, release <-chan struct{}) <-chan error {
+	t.Helper()
+	started := make(chan struct{})
+	db.SetQueryLatency(func(string) time.Duration {
+		close(started)
+		<-release
+		return 0
+	})
+	result := make(chan error, 1)
+	go func() {
+		_, err := db.QueryTimeout(context.Background(), "SELECT slow", time.Hour)
+		result <- err
+	}()
+	<-started
+	return result
+}
+
+// waitDraining waits until new queries are rejected with ErrDraining
+func waitDraining(t *testing.T, db *DatabaseConnection) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := db.QueryArgs("SELECT 1")
+		if errors.Is(err, ErrDraining) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("QueryArgs = %v, want ErrDraining", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDrainWaitsForInFlight(t *testing.T) {
+	db := connected(t)
+	release := make(chan struct{})
+	slow := slowQuery(t, db, release)
+
+	drained := make(chan error, 1)
+	go func() { drained <- db.Drain(context.Background()) }()
+	waitDraining(t, db)
+
+	if _, err := db.Exec("DELETE FROM users"); !errors.Is(err, ErrDraining) {
+		t.Errorf("Exec while draining = %v, want ErrDraining", err)
+	}
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain returned %v with a query still in flight", err)
+	default:
+	}
+	if got := db.State(); got != Connected {
+		t.Errorf("state while draining = %s, want Connected", got)
+	}
+
+	close(release)
+	if err := <-slow; err != nil {
+		t.Errorf("in-flight query: %v", err)
+	}
+	if err := <-drained; err != nil {
+		t.Errorf("Drain: %v", err)
+	}
+	if got := db.State(); got != Closed {
+		t.Errorf("state after Drain = %s, want Closed", got)
+	}
+}
+
+func TestDrainContextExpires(t *testing.T) {
+	db := connected(t)
+	release := make(chan struct{})
+	slow := slowQuery(t, db, release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := db.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain = %v, want context.DeadlineExceeded", err)
+	}
+	if got := db.State(); got != Closed {
+		t.Errorf("state after Drain = %s, want Closed", got)
+	}
+
+	// The abandoned query finds the connection closed
+	close(release)
+	if err := <-slow; err == nil {
+		t.Error("query finished on a closed connection")
+	}
+}
+
+func TestDrainIdle(t *testing.T) {
+	db := connected(t)
+	if err := db.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if err := db.Drain(context.Background()); err != nil {
+		t.Errorf("Drain of a closed connection: %v", err)
+	}
+}
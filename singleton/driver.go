@@ -0,0 +1,214 @@
+package singleton
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Result is what a driver returns for an executed statement.
+// SELECTs fill in Rows; INSERT, UPDATE and DELETE report how many rows they
+// touched, see NewExecResult.
+type Result struct {
+	Rows []map[string]any
+
+	rowsAffected int64
+	lastInsertID int64
+}
+
+// NewExecResult is how drivers report the outcome of a statement that changes rows
+func NewExecResult(rowsAffected, lastInsertID int64) Result {
+	return Result{rowsAffected: rowsAffected, lastInsertID: lastInsertID}
+}
+
+// RowsAffected returns the number of rows an INSERT, UPDATE or DELETE
+// changed. It is zero for SELECTs.
+func (r Result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// LastInsertID returns the ID given to the last row an INSERT added,
+// or zero if the statement inserted nothing.
+func (r Result) LastInsertID() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+// Driver is the backend a DatabaseConnection delegates to.
+// The singleton handles lifecycle, state and bookkeeping; the driver does the
+// actual talking to a database. Each connection has its own Driver; register
+// a DriverFactory with RegisterDriver to make a backend selectable by name.
+type Driver interface {
+	Open(conn string) error
+	Exec(sql string, args ...any) (Result, error)
+	Close() error
+}
+
+// DriverFactory creates a fresh Driver. Drivers hold per-connection state
+// (whether they're open, what they've executed), so the registry keeps
+// factories and every connection gets a driver of its own - the same split
+// as database/sql's Driver and Conn.
+type DriverFactory func() Driver
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]DriverFactory{"memory": func() Driver { return NewMemoryDriver() }}
+)
+
+// RegisterDriver makes a driver available under name for UseDriver
+func RegisterDriver(name string, factory DriverFactory) error {
+	if name == "" {
+		return errors.New("driver name must not be empty")
+	}
+	if factory == nil {
+		return errors.New("driver factory must not be nil")
+	}
+
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if _, exists := drivers[name]; exists {
+		return fmt.Errorf("driver %q is already registered", name)
+	}
+	drivers[name] = factory
+	return nil
+}
+
+// UseDriver switches the connection to a new driver from the factory
+// registered under name. It can only be changed while the connection is
+// disconnected.
+func (db *DatabaseConnection) UseDriver(name string) error {
+	driversMu.RLock()
+	factory, ok := drivers[name]
+	driversMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown driver %q", name)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.state != Disconnected {
+		return fmt.Errorf("can't change driver while %s", db.state)
+	}
+	db.driver = factory()
+	return nil
+}
+
+// MemoryDriver is the default driver. It keeps no data: every statement
+// succeeds and SELECTs return an empty result set. It does remember what
+// was executed, which is handy for demos, and counts rows per table so
+// INSERT, UPDATE and DELETE report plausible results:
+//
+//   - INSERT adds one row per VALUES tuple, numbering them from 1 up
+//   - UPDATE and DELETE with a WHERE clause match one row, if the table has any
+//   - UPDATE and DELETE without one match every row in the table
+type MemoryDriver struct {
+	mu       sync.Mutex
+	open     bool
+	executed []string
+	rows     map[string]int64 // row count by lowercased table name
+	lastID   int64
+}
+
+// NewMemoryDriver returns a new in-memory driver
+func NewMemoryDriver() *MemoryDriver {
+	return &MemoryDriver{}
+}
+
+func (m *MemoryDriver) Open(conn string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.open = true
+	return nil
+}
+
+func (m *MemoryDriver) Exec(sql string, args ...any) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.open {
+		return Result{}, errors.New("memory driver: not open")
+	}
+	if strings.TrimSpace(sql) == "" {
+		return Result{}, errors.New("memory driver: empty statement")
+	}
+	m.executed = append(m.executed, sql)
+	if isSelect(sql) {
+		return Result{Rows: []map[string]any{}}, nil
+	}
+	return m.applyLocked(sql), nil
+}
+
+// applyLocked updates the row counts for a statement that changes rows.
+// Anything it doesn't recognise affects nothing. The caller must hold m.mu.
+func (m *MemoryDriver) applyLocked(sql string) Result {
+	if m.rows == nil {
+		m.rows = make(map[string]int64)
+	}
+	if match := insertPattern.FindStringSubmatch(sql); match != nil {
+		n := countValueTuples(sql)
+		m.rows[strings.ToLower(match[1])] += n
+		m.lastID += n
+		return NewExecResult(n, m.lastID)
+	}
+	if match := updatePattern.FindStringSubmatch(sql); match != nil {
+		return NewExecResult(m.matchedLocked(match[1], match[2]), 0)
+	}
+	if match := deletePattern.FindStringSubmatch(sql); match != nil {
+		table := strings.ToLower(match[1])
+		n := m.matchedLocked(table, match[2])
+		m.rows[table] -= n
+		return NewExecResult(n, 0)
+	}
+	return Result{}
+}
+
+// matchedLocked returns how many rows of table a WHERE clause matches:
+// one if there is a clause, all of them if there isn't
+func (m *MemoryDriver) matchedLocked(table, where string) int64 {
+	n := m.rows[strings.ToLower(table)]
+	if where != "" {
+		return min(n, 1)
+	}
+	return n
+}
+
+// countValueTuples counts the parenthesised tuples after VALUES in an INSERT,
+// ignoring parentheses inside quoted strings. An INSERT without VALUES
+// (e.g. INSERT ... SELECT) counts as one row.
+func countValueTuples(sql string) int64 {
+	idx := strings.Index(strings.ToUpper(sql), "VALUES")
+	if idx < 0 {
+		return 1
+	}
+	var count int64
+	depth := 0
+	inQuote := false
+	for _, r := range sql[idx:] {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '(':
+			if depth == 0 {
+				count++
+			}
+			depth++
+		case r == ')':
+			depth--
+		}
+	}
+	return max(count, 1)
+}
+
+func (m *MemoryDriver) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.open = false
+	return nil
+}
+
+// Executed returns the statements run so far, oldest first
+func (m *MemoryDriver) Executed() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.executed...)
+}
@@ -0,0 +1,114 @@
+package singleton
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+// driverSeq keeps test driver names unique, since registrations can't be undone
+var driverSeq atomic.Int64
+
+func uniqueDriverName(t *testing.T) string {
+	return fmt.Sprintf("%s-%d", t.Name(), driverSeq.Add(1))
+}
+
+func TestQueryRunsThroughDriver(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	driver := NewMemoryDriver()
+	db.driver = driver
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	db.Query("SELECT * FROM users")
+	if _, err := db.QueryArgs("DELETE FROM users WHERE id = ?", 1); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"SELECT * FROM users", "DELETE FROM users WHERE id = ?"}
+	if got := driver.Executed(); !slices.Equal(got, want) {
+		t.Errorf("driver executed %v, want %v", got, want)
+	}
+}
+
+func TestMemoryDriverMustBeOpen(t *testing.T) {
+	d := NewMemoryDriver()
+	if _, err := d.Exec("SELECT 1"); err == nil {
+		t.Error("Exec on a driver that was never opened succeeded")
+	}
+	d.Open(DefaultConnectionString)
+	if _, err := d.Exec("   "); err == nil {
+		t.Error("Exec of an empty statement succeeded")
+	}
+	d.Close()
+	if _, err := d.Exec("SELECT 1"); err == nil {
+		t.Error("Exec after Close succeeded")
+	}
+}
+
+func TestRegisterDriverGivesEachConnectionItsOwn(t *testing.T) {
+	name := uniqueDriverName(t)
+	var made []*countingDriver
+	err := RegisterDriver(name, func() Driver {
+		d := newCountingDriver()
+		made = append(made, d)
+		return d
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := newConnection(defaultConnInfo), newConnection(defaultConnInfo)
+	for _, db := range []*DatabaseConnection{a, b} {
+		if err := db.UseDriver(name); err != nil {
+			t.Fatalf("UseDriver: %v", err)
+		}
+		if err := db.Connect(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(made) != 2 || made[0] == made[1] {
+		t.Fatalf("factory made %d drivers, want one per connection", len(made))
+	}
+
+	// Closing one connection must not close the other's driver
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.QueryArgs("SELECT 1"); err != nil {
+		t.Errorf("query on the second connection after closing the first: %v", err)
+	}
+	if _, closes := made[1].counts(); closes != 0 {
+		t.Errorf("second driver closed %d times, want 0", closes)
+	}
+}
+
+func TestRegisterDriverErrors(t *testing.T) {
+	factory := func() Driver { return NewMemoryDriver() }
+	if err := RegisterDriver("", factory); err == nil {
+		t.Error("registering an empty name succeeded")
+	}
+	if err := RegisterDriver(uniqueDriverName(t), nil); err == nil {
+		t.Error("registering a nil factory succeeded")
+	}
+	if err := RegisterDriver("memory", factory); err == nil {
+		t.Error("registering over the memory driver succeeded")
+	}
+}
+
+func TestUseDriver(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	if err := db.UseDriver("no-such-driver"); err == nil {
+		t.Error("UseDriver with an unknown name succeeded")
+	}
+	if err := db.UseDriver("memory"); err != nil {
+		t.Errorf("UseDriver(memory): %v", err)
+	}
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UseDriver("memory"); err == nil {
+		t.Error("UseDriver while connected succeeded")
+	}
+}
@@ -0,0 +1,31 @@
+package singleton
+
+// Eager Initialization
+//
+// GetInstance creates its connection lazily, on first use. The eager version
+// below creates it while the package is initialized, before main() even runs.
+//
+// Tradeoffs:
+//   - No first-call cost and no synchronization at all: init() runs once,
+//     single-threaded, before any goroutine can call GetEagerInstance.
+//   - The connection is always constructed, even if the program never uses it.
+//   - Construction can't depend on anything configured at runtime (flags,
+//     config files, Init), and it can't report an error back to the caller.
+//
+// Use eager initialization when the object is cheap and always needed.
+
+// eagerConnectionID is reserved for the eager instance. init() runs before
+// anything else, so taking the next ID from the shared counter would make
+// the lazy singleton report ID 2 in every program.
+const eagerConnectionID = 0
+
+var eagerInstance *DatabaseConnection
+
+func init() {
+	eagerInstance = newConnectionWithID(defaultConnInfo, eagerConnectionID)
+}
+
+// GetEagerInstance returns the connection created at package initialization
+func GetEagerInstance() *DatabaseConnection {
+	return eagerInstance
+}
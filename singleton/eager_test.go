@@ -0,0 +1,29 @@
+package singleton
+
+import "testing"
+
+func TestEagerInstanceExistsBeforeFirstCall(t *testing.T) {
+	// Read the variable directly: init() must have built it already
+	if eagerInstance == nil {
+		t.Fatal("eagerInstance is nil before GetEagerInstance was called")
+	}
+	if got := GetEagerInstance(); got != eagerInstance {
+		t.Errorf("GetEagerInstance() = %p, want %p", got, eagerInstance)
+	}
+	if GetEagerInstance() != GetEagerInstance() {
+		t.Error("GetEagerInstance returned different instances")
+	}
+	if got := eagerInstance.GetConnectionString(); got != DefaultConnectionString {
+		t.Errorf("eager connection string = %q, want the default", got)
+	}
+}
+
+func TestEagerInstanceHasItsOwnID(t *testing.T) {
+	if got := eagerInstance.GetConnectionID(); got != eagerConnectionID {
+		t.Errorf("eager connection ID = %d, want %d", got, eagerConnectionID)
+	}
+	// The shared counter starts at 1, so no other connection can collide
+	if got := newConnection(defaultConnInfo).GetConnectionID(); got == eagerConnectionID {
+		t.Errorf("newConnection handed out the eager instance's ID %d", got)
+	}
+}
@@ -0,0 +1,115 @@
+package singleton
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventKind says what happened to a connection
+type EventKind int
+
+const (
+	EventConnect EventKind = iota
+	EventDisconnect
+	EventQuery
+	EventError
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventConnect:
+		return "connect"
+	case EventDisconnect:
+		return "disconnect"
+	case EventQuery:
+		return "query"
+	case EventError:
+		return "error"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event is one entry in a connection's event log
+type Event struct {
+	Kind   EventKind
+	Time   time.Time
+	Detail string // the SQL for queries, the message for errors
+}
+
+// DefaultEventBufferSize is how many events a connection keeps unless
+// SetEventBufferSize says otherwise
+const DefaultEventBufferSize = 64
+
+// eventLog is a fixed-capacity ring buffer: once full, each new event
+// overwrites the oldest one, so memory use never grows.
+type eventLog struct {
+	buf   []Event
+	next  int // where the next event goes
+	count int
+	size  int // capacity; 0 means DefaultEventBufferSize, < 0 disables the log
+}
+
+func (l *eventLog) add(e Event) {
+	if l.size < 0 {
+		return
+	}
+	if l.buf == nil {
+		size := l.size
+		if size == 0 {
+			size = DefaultEventBufferSize
+		}
+		l.buf = make([]Event, size)
+	}
+	l.buf[l.next] = e
+	l.next = (l.next + 1) % len(l.buf)
+	if l.count < len(l.buf) {
+		l.count++
+	}
+}
+
+// events returns the buffered events, oldest first
+func (l *eventLog) events() []Event {
+	out := make([]Event, 0, l.count)
+	start := l.next - l.count
+	if start < 0 {
+		start += len(l.buf)
+	}
+	for i := 0; i < l.count; i++ {
+		out = append(out, l.buf[(start+i)%len(l.buf)])
+	}
+	return out
+}
+
+// SetEventBufferSize sets how many recent events are kept. The most recent
+// events that still fit are preserved. Zero or less turns the log off.
+func (db *DatabaseConnection) SetEventBufferSize(n int) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	kept := db.events.events()
+	if n <= 0 {
+		db.events = eventLog{size: -1}
+		return
+	}
+	if len(kept) > n {
+		kept = kept[len(kept)-n:]
+	}
+	db.events = eventLog{size: n}
+	for _, e := range kept {
+		db.events.add(e)
+	}
+}
+
+// RecentEvents returns the last events on the connection, oldest first.
+// It's meant for post-mortem debugging when no external logging was set up.
+func (db *DatabaseConnection) RecentEvents() []Event {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.events.events()
+}
+
+// recordLocked appends an event to the log. The caller must hold db.mu.
+func (db *DatabaseConnection) recordLocked(kind EventKind, detail string) {
+	db.events.add(Event{Kind: kind, Time: db.clock.Now(), Detail: detail})
+}
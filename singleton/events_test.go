@@ -0,0 +1,84 @@
+package singleton
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// details returns the Detail of each event
+func details(events []Event) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = e.Detail
+	}
+	return out
+}
+
+func TestEventLogWraps(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	clock := newFakeClock()
+	db.SetClock(clock)
+	db.SetEventBufferSize(3)
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 5; i++ {
+		clock.Advance(time.Second)
+		db.Query(fmt.Sprintf("SELECT %d", i))
+	}
+
+	events := db.RecentEvents()
+	if got, want := fmt.Sprint(details(events)), "[SELECT 3 SELECT 4 SELECT 5]"; got != want {
+		t.Fatalf("RecentEvents = %s, want %s", got, want)
+	}
+	for i, e := range events {
+		if e.Kind != EventQuery {
+			t.Errorf("event %d kind = %s, want query", i, e.Kind)
+		}
+		if i > 0 && !e.Time.After(events[i-1].Time) {
+			t.Errorf("events out of order: %v then %v", events[i-1].Time, e.Time)
+		}
+	}
+}
+
+func TestEventLogKinds(t *testing.T) {
+	db := connected(t)
+	db.Query("SELECT 1")
+	db.Query("")
+	db.Disconnect()
+
+	var kinds []EventKind
+	for _, e := range db.RecentEvents() {
+		kinds = append(kinds, e.Kind)
+	}
+	if got, want := fmt.Sprint(kinds), fmt.Sprint([]EventKind{EventConnect, EventQuery, EventQuery, EventError, EventDisconnect}); got != want {
+		t.Errorf("kinds = %s, want %s", got, want)
+	}
+}
+
+func TestSetEventBufferSize(t *testing.T) {
+	db := connected(t)
+	for i := 1; i <= 4; i++ {
+		db.Query(fmt.Sprintf("SELECT %d", i))
+	}
+
+	// Shrinking keeps the newest events
+	db.SetEventBufferSize(2)
+	if got := fmt.Sprint(details(db.RecentEvents())); got != "[SELECT 3 SELECT 4]" {
+		t.Errorf("after shrinking: %s", got)
+	}
+
+	// Growing keeps them all and makes room for more
+	db.SetEventBufferSize(10)
+	db.Query("SELECT 5")
+	if got := fmt.Sprint(details(db.RecentEvents())); got != "[SELECT 3 SELECT 4 SELECT 5]" {
+		t.Errorf("after growing: %s", got)
+	}
+
+	db.SetEventBufferSize(0)
+	db.Query("SELECT 6")
+	if got := db.RecentEvents(); len(got) != 0 {
+		t.Errorf("disabled log still has %d events", len(got))
+	}
+}
@@ -1,63 +1,131 @@
 package singleton
 
 import (
-	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // DatabaseConnection represents a singleton database connection
 type DatabaseConnection struct {
-	connectionString string
-	isConnected      bool
+	connectionString string `secret:"url"`
+	connInfo         ConnInfo
 	connectionID     int
+
+	// mu guards the mutable connection state below
+	mu       sync.Mutex
+	state    State
+	lastSQL  string
+	lastArgs []any
+	stmts    map[*Stmt]struct{}
+	dial     DialFunc
+	driver   Driver
+	replica  *replica
+
+	middleware []QueryMiddleware
+
+	observers observers
+	events    eventLog
+
+	draining bool
+	inFlight sync.WaitGroup
+
+	clock        Clock
+	maxIdle      time.Duration
+	lastActivity time.Time
+	reconnects   int
+	latency      func(sql string) time.Duration
+
+	queryCount     int
+	primaryQueries int
+	replicaQueries int
+	connectCount   int
+	connectedAt    time.Time
 }
 
 var (
-	instance *DatabaseConnection
+	instance atomic.Pointer[DatabaseConnection]
 	once     sync.Once
-	connID   int
+	connID   atomic.Int64
 )
 
 // GetInstance returns the singleton instance of DatabaseConnection
 // This is thread-safe and will only create the instance once
+//
+// After initialization the hot path is a single atomic load with no locking.
+// The Store inside once.Do happens after the connection is fully built, and an
+// atomic Load that observes the pointer also observes every write made before
+// the Store (Go memory model: the Store is synchronized before the Load).
+// So a reader either sees nil and falls through to once.Do, or sees a
+// completely initialized connection - never a half-built one.
 func GetInstance() *DatabaseConnection {
+	if db := instance.Load(); db != nil {
+		return db
+	}
 	once.Do(func() {
-		connID++
-		instance = &DatabaseConnection{
-			connectionString: "postgresql://localhost:5432/mydb",
-			isConnected:      false,
-			connectionID:     connID,
-		}
-		fmt.Printf("Database connection instance created (ID: %d)\n", connID)
+		db := newConnection(claimConnInfo())
+		instance.Store(db)
+		logger.Printf("Database connection instance created (ID: %d)\n", db.connectionID)
 	})
-	return instance
+	return instance.Load()
 }
 
-// Connect simulates connecting to the database
-func (db *DatabaseConnection) Connect() {
-	if !db.isConnected {
-		db.isConnected = true
-		fmt.Printf("Connected to database (ID: %d)\n", db.connectionID)
-	} else {
-		fmt.Printf("Already connected to database (ID: %d)\n", db.connectionID)
+// newConnection builds a fresh, disconnected DatabaseConnection with the next ID.
+func newConnection(info ConnInfo) *DatabaseConnection {
+	return newConnectionWithID(info, int(connID.Add(1)))
+}
+
+// newConnectionWithID builds a fresh, disconnected DatabaseConnection with the given ID.
+// Every way of obtaining a singleton (lazy, DCL, eager) goes through here.
+func newConnectionWithID(info ConnInfo, id int) *DatabaseConnection {
+	return &DatabaseConnection{
+		connectionString: info.String(),
+		connInfo:         info,
+		state:            Disconnected,
+		connectionID:     id,
+		clock:            realClock{},
+		driver:           NewMemoryDriver(),
 	}
 }
 
+// Connect simulates connecting to the database
+// Connecting an already connected database is a no-op; connecting a closed one is an error.
+func (db *DatabaseConnection) Connect() error {
+	return db.transition(Connected)
+}
+
 // Disconnect simulates disconnecting from the database
-func (db *DatabaseConnection) Disconnect() {
-	if db.isConnected {
-		db.isConnected = false
-		fmt.Printf("Disconnected from database (ID: %d)\n", db.connectionID)
-	}
+func (db *DatabaseConnection) Disconnect() error {
+	return db.transition(Disconnected)
 }
 
 // Query simulates executing a database query
 func (db *DatabaseConnection) Query(sql string) {
-	if !db.isConnected {
-		fmt.Println("Error: Not connected to database. Call Connect() first.")
+	if err := db.reconnectIfIdle(); err != nil {
+		logger.Printf("Error: %v\n", err)
+		return
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.state != Connected {
+		logger.Printf("Error: Not connected to database. Call Connect() first.\n")
 		return
 	}
-	fmt.Printf("Executing query: %s (Connection ID: %d)\n", sql, db.connectionID)
+	if db.draining {
+		logger.Printf("Error: %v\n", ErrDraining)
+		return
+	}
+	logger.Printf("Executing query: %s (Connection ID: %d)\n", sql, db.connectionID)
+	db.recordLocked(EventQuery, sql)
+	if _, err := db.execLocked(sql); err != nil {
+		db.recordLocked(EventError, err.Error())
+		logger.Printf("Error: %v\n", err)
+		return
+	}
+	db.queryCount++
+	db.lastActivity = db.clock.Now()
 }
 
 // GetConnectionID returns the unique connection ID
@@ -65,6 +133,11 @@ func (db *DatabaseConnection) GetConnectionID() int {
 	return db.connectionID
 }
 
+// GetConnInfo returns the parsed components of the connection string
+func (db *DatabaseConnection) GetConnInfo() ConnInfo {
+	return db.connInfo
+}
+
 // GetConnectionString returns the connection string
 func (db *DatabaseConnection) GetConnectionString() string {
 	return db.connectionString
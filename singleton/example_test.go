@@ -0,0 +1,87 @@
+package singleton
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetInstanceConcurrent(t *testing.T) {
+	const goroutines = 64
+	got := make([]*DatabaseConnection, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = GetInstance()
+		}()
+	}
+	wg.Wait()
+
+	for i, db := range got {
+		if db == nil || db != got[0] {
+			t.Fatalf("goroutine %d got %p, want %p", i, db, got[0])
+		}
+	}
+}
+
+// Once the instance exists, GetInstance must not wait on the connection's
+// lock, or every caller would queue behind a slow query
+func TestGetInstanceLockFree(t *testing.T) {
+	db := GetInstance()
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	done := make(chan *DatabaseConnection)
+	go func() { done <- GetInstance() }()
+	select {
+	case got := <-done:
+		if got != db {
+			t.Errorf("GetInstance() = %p, want %p", got, db)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetInstance blocked on the connection lock")
+	}
+}
+
+func TestConnectDisconnect(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	if err := db.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if err := db.Connect(); err != nil {
+		t.Errorf("second Connect: %v", err)
+	}
+	if got := db.State(); got != Connected {
+		t.Errorf("State() = %v, want Connected", got)
+	}
+	if err := db.Disconnect(); err != nil {
+		t.Fatalf("Disconnect: %v", err)
+	}
+	if got := db.State(); got != Disconnected {
+		t.Errorf("State() = %v, want Disconnected", got)
+	}
+}
+
+func TestNewConnectionIDsAreUnique(t *testing.T) {
+	a, b := newConnection(defaultConnInfo), newConnection(defaultConnInfo)
+	if a.GetConnectionID() == b.GetConnectionID() {
+		t.Errorf("two connections share ID %d", a.GetConnectionID())
+	}
+	if got := a.GetConnectionString(); got != DefaultConnectionString {
+		t.Errorf("GetConnectionString() = %q, want %q", got, DefaultConnectionString)
+	}
+}
+
+func BenchmarkGetInstanceParallel(b *testing.B) {
+	GetInstance()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if GetInstance() == nil {
+				b.Fatal("GetInstance returned nil")
+			}
+		}
+	})
+}
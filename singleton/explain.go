@@ -0,0 +1,111 @@
+package singleton
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// planNode is one step of a query plan. Plans are trees: a Limit reads from a
+// Sort, which reads from a scan.
+type planNode struct {
+	op      string
+	details []string
+	child   *planNode
+}
+
+// render formats the plan the way PostgreSQL's EXPLAIN does, children
+// indented under their parent with an arrow
+func (n *planNode) render(b *strings.Builder, depth int) {
+	indent := strings.Repeat("      ", depth)
+	if depth == 0 {
+		b.WriteString(n.op + "\n")
+	} else {
+		b.WriteString(indent[:len(indent)-6] + "  ->  " + n.op + "\n")
+	}
+	for _, d := range n.details {
+		b.WriteString(indent + "  " + d + "\n")
+	}
+	if n.child != nil {
+		n.child.render(b, depth+1)
+	}
+}
+
+var (
+	selectPattern = regexp.MustCompile(`(?is)^\s*select\s+.+?\s+from\s+(\w+)(?:\s+where\s+(.+?))?(?:\s+order\s+by\s+(.+?))?(?:\s+limit\s+(\d+))?\s*;?\s*$`)
+	insertPattern = regexp.MustCompile(`(?is)^\s*insert\s+into\s+(\w+)`)
+	updatePattern = regexp.MustCompile(`(?is)^\s*update\s+(\w+)\s+set\s+.+?(?:\s+where\s+(.+?))?\s*;?\s*$`)
+	deletePattern = regexp.MustCompile(`(?is)^\s*delete\s+from\s+(\w+)(?:\s+where\s+(.+?))?\s*;?\s*$`)
+	// A single equality on id or a *_id column is assumed to be indexed
+	indexedPattern = regexp.MustCompile(`(?i)^(id|\w+_id)\s*=\s*\S+$`)
+)
+
+// Explain returns a simulated query plan for sql, in the style of
+// PostgreSQL's EXPLAIN:
+//
+//	Limit
+//	  Rows: 10
+//	  ->  Sort
+//	        Sort Key: name
+//	        ->  Seq Scan on users
+//	              Filter: (active = true)
+//
+// The plan is derived from the SQL text alone. Equality on an id column uses
+// an index scan; anything else scans the table. Statements it can't parse
+// are an error, as is explaining while disconnected.
+func (db *DatabaseConnection) Explain(sql string) (string, error) {
+	db.mu.Lock()
+	connected := db.state == Connected
+	db.mu.Unlock()
+	if !connected {
+		return "", ErrNotConnected
+	}
+
+	plan, err := planFor(sql)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	plan.render(&b, 0)
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+func planFor(sql string) (*planNode, error) {
+	if m := selectPattern.FindStringSubmatch(sql); m != nil {
+		table, where, orderBy, limit := m[1], m[2], m[3], m[4]
+		plan := scan(table, where)
+		if orderBy != "" {
+			plan = &planNode{op: "Sort", details: []string{"Sort Key: " + orderBy}, child: plan}
+		}
+		if limit != "" {
+			plan = &planNode{op: "Limit", details: []string{"Rows: " + limit}, child: plan}
+		}
+		return plan, nil
+	}
+	if m := insertPattern.FindStringSubmatch(sql); m != nil {
+		return &planNode{op: "Insert on " + m[1], child: &planNode{op: "Result"}}, nil
+	}
+	if m := updatePattern.FindStringSubmatch(sql); m != nil {
+		return &planNode{op: "Update on " + m[1], child: scan(m[1], m[2])}, nil
+	}
+	if m := deletePattern.FindStringSubmatch(sql); m != nil {
+		return &planNode{op: "Delete on " + m[1], child: scan(m[1], m[2])}, nil
+	}
+	return nil, fmt.Errorf("can't explain %q: only SELECT, INSERT, UPDATE and DELETE are supported", sql)
+}
+
+// scan picks how a table is read for the given WHERE clause
+func scan(table, where string) *planNode {
+	where = strings.TrimSpace(where)
+	if m := indexedPattern.FindStringSubmatch(where); m != nil {
+		return &planNode{
+			op:      fmt.Sprintf("Index Scan using %s_%s_idx on %s", table, strings.ToLower(m[1]), table),
+			details: []string{"Index Cond: (" + where + ")"},
+		}
+	}
+	node := &planNode{op: "Seq Scan on " + table}
+	if where != "" {
+		node.details = []string{"Filter: (" + where + ")"}
+	}
+	return node
+}
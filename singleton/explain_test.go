@@ -0,0 +1,52 @@
+package singleton
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"SELECT * FROM users", "Seq Scan on users"},
+		{"select name from users where id = 7", "Index Scan using users_id_idx on users\n  Index Cond: (id = 7)"},
+		{"SELECT * FROM orders WHERE user_id = ?", "Index Scan using orders_user_id_idx on orders\n  Index Cond: (user_id = ?)"},
+		{
+			"SELECT * FROM users WHERE active = true ORDER BY name LIMIT 10;",
+			"Limit\n" +
+				"  Rows: 10\n" +
+				"  ->  Sort\n" +
+				"        Sort Key: name\n" +
+				"        ->  Seq Scan on users\n" +
+				"              Filter: (active = true)",
+		},
+		{"INSERT INTO users (name) VALUES ('ann')", "Insert on users\n  ->  Result"},
+		{"UPDATE users SET name = 'bo' WHERE id = 1", "Update on users\n  ->  Index Scan using users_id_idx on users\n        Index Cond: (id = 1)"},
+		{"DELETE FROM sessions", "Delete on sessions\n  ->  Seq Scan on sessions"},
+	}
+	db := connected(t)
+	for _, tt := range tests {
+		got, err := db.Explain(tt.sql)
+		if err != nil {
+			t.Errorf("Explain(%q): %v", tt.sql, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Explain(%q) =\n%s\nwant\n%s", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	if _, err := db.Explain("SELECT * FROM users"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Explain while disconnected = %v, want ErrNotConnected", err)
+	}
+
+	db = connected(t)
+	if _, err := db.Explain("VACUUM users"); err == nil {
+		t.Error("Explain accepted a statement it can't plan")
+	}
+}
@@ -0,0 +1,84 @@
+package singleton
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetClock replaces the clock used for idle tracking
+func (db *DatabaseConnection) SetClock(clock Clock) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.clock = clock
+}
+
+// SetMaxIdle sets how long the connection may sit unused before it is
+// considered stale. The next query after that transparently reconnects.
+// Zero disables the check.
+func (db *DatabaseConnection) SetMaxIdle(d time.Duration) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.maxIdle = d
+}
+
+// ReconnectCount returns how many times a stale connection was re-established
+func (db *DatabaseConnection) ReconnectCount() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.reconnects
+}
+
+// reconnectIfIdle re-establishes the connection if it has been idle longer
+// than maxIdle. Real servers drop idle connections, so reconnecting up front
+// beats failing the query. Every query method calls it before taking db.mu
+// for the query itself, so observers can be notified without the lock held.
+//
+// A reconnect is a real disconnect and connect: the driver is closed and
+// reopened, both show up in the event log and metrics, and the OnDisconnect
+// and OnConnect observers run. If reopening fails the connection is left
+// disconnected and the error is returned.
+func (db *DatabaseConnection) reconnectIfIdle() error {
+	db.mu.Lock()
+	dropped, err := db.reconnectIfIdleLocked()
+	db.mu.Unlock()
+
+	if dropped {
+		db.notifyDisconnect()
+		if err == nil {
+			db.notifyConnect()
+		}
+	}
+	return err
+}
+
+// reconnectIfIdleLocked does the work of reconnectIfIdle. dropped reports
+// whether the stale connection was closed. The caller must hold db.mu.
+func (db *DatabaseConnection) reconnectIfIdleLocked() (dropped bool, err error) {
+	if db.state != Connected || db.maxIdle <= 0 || db.lastActivity.IsZero() {
+		return false, nil
+	}
+	if db.clock.Now().Sub(db.lastActivity) <= db.maxIdle {
+		return false, nil
+	}
+
+	db.recordLocked(EventDisconnect, "idle")
+	if err := db.driver.Close(); err != nil {
+		logger.Printf("Error closing idle connection: %v\n", err)
+	}
+	if err := db.driver.Open(db.connectionString); err != nil {
+		db.setStateLocked(Disconnected)
+		db.recordLocked(EventError, err.Error())
+		return true, fmt.Errorf("reconnect after idle: %w", err)
+	}
+	db.reconnects++
+	db.markConnectedLocked()
+	logger.Printf("Connection idle for more than %v, reconnected (ID: %d)\n", db.maxIdle, db.connectionID)
+	return true, nil
+}
+
+// idleFor returns how long the connection has gone without activity as of now
+func (db *DatabaseConnection) idleFor(now time.Time) time.Duration {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return now.Sub(db.lastActivity)
+}
@@ -0,0 +1,99 @@
+package singleton
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// idleConn returns a connected connection on a fake clock and counting
+// driver, with the given idle limit
+func idleConn(t *testing.T, maxIdle time.Duration) (*DatabaseConnection, *fakeClock, *countingDriver) {
+	t.Helper()
+	db := newConnection(defaultConnInfo)
+	clock := newFakeClock()
+	driver := newCountingDriver()
+	db.driver = driver
+	db.SetClock(clock)
+	db.SetMaxIdle(maxIdle)
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	return db, clock, driver
+}
+
+func TestReconnectAfterIdle(t *testing.T) {
+	db, clock, driver := idleConn(t, time.Minute)
+	var connects, disconnects int
+	db.OnConnect(func(*DatabaseConnection) { connects++ })
+	db.OnDisconnect(func(*DatabaseConnection) { disconnects++ })
+
+	clock.Advance(2 * time.Minute)
+	if _, err := db.QueryArgs("SELECT 1"); err != nil {
+		t.Fatalf("QueryArgs after idling: %v", err)
+	}
+
+	if got := db.ReconnectCount(); got != 1 {
+		t.Errorf("ReconnectCount() = %d, want 1", got)
+	}
+	if opens, closes := driver.counts(); opens != 2 || closes != 1 {
+		t.Errorf("driver opened %d and closed %d times, want 2 and 1", opens, closes)
+	}
+	if connects != 1 || disconnects != 1 {
+		t.Errorf("observers saw %d connects and %d disconnects, want 1 and 1", connects, disconnects)
+	}
+
+	var kinds []EventKind
+	for _, e := range db.RecentEvents() {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []EventKind{EventConnect, EventDisconnect, EventConnect, EventQuery}
+	if len(kinds) != len(want) {
+		t.Fatalf("event kinds = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("event kinds = %v, want %v", kinds, want)
+		}
+	}
+}
+
+func TestNoReconnectWithinIdleWindow(t *testing.T) {
+	db, clock, driver := idleConn(t, time.Minute)
+
+	for range 3 {
+		clock.Advance(50 * time.Second)
+		db.Query("SELECT 1")
+	}
+	if got := db.ReconnectCount(); got != 0 {
+		t.Errorf("ReconnectCount() = %d, want 0: each query keeps the connection fresh", got)
+	}
+	if opens, _ := driver.counts(); opens != 1 {
+		t.Errorf("driver opened %d times, want 1", opens)
+	}
+}
+
+func TestIdleCheckDisabled(t *testing.T) {
+	db, clock, _ := idleConn(t, 0)
+	clock.Advance(24 * time.Hour)
+	if _, err := db.Exec("SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.ReconnectCount(); got != 0 {
+		t.Errorf("ReconnectCount() = %d with the check disabled, want 0", got)
+	}
+}
+
+func TestReconnectAfterIdleFails(t *testing.T) {
+	db, clock, driver := idleConn(t, time.Minute)
+	errDown := errors.New("server down")
+	driver.failOpen = errDown
+
+	clock.Advance(2 * time.Minute)
+	if _, err := db.QueryArgs("SELECT 1"); !errors.Is(err, errDown) {
+		t.Errorf("QueryArgs = %v, want %v", err, errDown)
+	}
+	if got := db.State(); got != Disconnected {
+		t.Errorf("State() = %v, want Disconnected", got)
+	}
+}
@@ -0,0 +1,49 @@
+package singleton
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// bufferLogger is a Logger that keeps everything it is given
+type bufferLogger struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (l *bufferLogger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(&l.buf, format, args...)
+}
+
+func (l *bufferLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
+
+// captureLog sends the package's output to a fresh bufferLogger for the
+// rest of the test
+func captureLog(t *testing.T) *bufferLogger {
+	t.Helper()
+	l := &bufferLogger{}
+	SetLogger(l)
+	t.Cleanup(func() { SetLogger(nil) })
+	return l
+}
+
+func TestSetLoggerCapturesQueries(t *testing.T) {
+	log := captureLog(t)
+	db := newConnection(defaultConnInfo)
+	if err := db.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	db.Query("SELECT 1")
+	if got := log.String(); !strings.Contains(got, "Executing query: SELECT 1") {
+		t.Errorf("log = %q, want the executed query", got)
+	}
+}
@@ -0,0 +1,85 @@
+package singleton
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	SetLogger(nil)
+	os.Exit(m.Run())
+}
+
+// connected returns a fresh connection, independent of the package
+// singletons, that is already connected
+func connected(t *testing.T) *DatabaseConnection {
+	t.Helper()
+	db := newConnection(defaultConnInfo)
+	if err := db.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	return db
+}
+
+// fakeClock is a Clock that only moves when told to
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// countingDriver is a MemoryDriver that counts opens and closes and can be
+// told to fail the next Open
+type countingDriver struct {
+	*MemoryDriver
+
+	mu       sync.Mutex
+	opens    int
+	closes   int
+	failOpen error
+}
+
+func newCountingDriver() *countingDriver {
+	return &countingDriver{MemoryDriver: NewMemoryDriver()}
+}
+
+func (d *countingDriver) Open(conn string) error {
+	d.mu.Lock()
+	d.opens++
+	err := d.failOpen
+	d.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return d.MemoryDriver.Open(conn)
+}
+
+func (d *countingDriver) Close() error {
+	d.mu.Lock()
+	d.closes++
+	d.mu.Unlock()
+	return d.MemoryDriver.Close()
+}
+
+func (d *countingDriver) counts() (opens, closes int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.opens, d.closes
+}
@@ -0,0 +1,61 @@
+package singleton
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markConnectedLocked records a successful connect for idle tracking, metrics
+// and the event log.
+// The caller must hold db.mu.
+func (db *DatabaseConnection) markConnectedLocked() {
+	now := db.clock.Now()
+	db.lastActivity = now
+	db.connectedAt = now
+	db.connectCount++
+	db.recordLocked(EventConnect, "")
+}
+
+// Metrics renders the connection's runtime metrics in the Prometheus text
+// exposition format, ready to be served from a /metrics endpoint.
+// It's written by hand to keep the package free of dependencies.
+func (db *DatabaseConnection) Metrics() string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	id := fmt.Sprintf(`connection_id="%d"`, db.connectionID)
+
+	uptime := 0.0
+	if db.state == Connected {
+		uptime = db.clock.Now().Sub(db.connectedAt).Seconds()
+	}
+
+	var b strings.Builder
+	writeMetric(&b, "db_queries_total", "counter", "Total number of queries executed.")
+	fmt.Fprintf(&b, "db_queries_total{%s} %d\n", id, db.queryCount)
+
+	writeMetric(&b, "db_queries_by_target_total", "counter", "Queries sent to the primary and to the read replica.")
+	fmt.Fprintf(&b, "db_queries_by_target_total{%s,target=\"primary\"} %d\n", id, db.primaryQueries)
+	fmt.Fprintf(&b, "db_queries_by_target_total{%s,target=\"replica\"} %d\n", id, db.replicaQueries)
+
+	writeMetric(&b, "db_connects_total", "counter", "Total number of successful connects.")
+	fmt.Fprintf(&b, "db_connects_total{%s} %d\n", id, db.connectCount)
+
+	writeMetric(&b, "db_state", "gauge", "Current connection state (1 for the active state).")
+	for _, s := range []State{Disconnected, Connecting, Connected, Closed} {
+		value := 0
+		if s == db.state {
+			value = 1
+		}
+		fmt.Fprintf(&b, "db_state{%s,state=\"%s\"} %d\n", id, s, value)
+	}
+
+	writeMetric(&b, "db_uptime_seconds", "gauge", "Seconds since the connection was established.")
+	fmt.Fprintf(&b, "db_uptime_seconds{%s} %g\n", id, uptime)
+
+	return b.String()
+}
+
+func writeMetric(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
@@ -0,0 +1,56 @@
+package singleton
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	clock := newFakeClock()
+	db.SetClock(clock)
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	db.Query("SELECT 1")
+	db.Query("SELECT 2")
+	db.QueryArgs("SELECT ?", 3)
+	clock.Advance(90 * time.Second)
+
+	id := fmt.Sprintf(`connection_id="%d"`, db.GetConnectionID())
+	out := db.Metrics()
+	for _, line := range []string{
+		"# TYPE db_queries_total counter",
+		"db_queries_total{" + id + "} 3",
+		"db_connects_total{" + id + "} 1",
+		"# TYPE db_state gauge",
+		"db_state{" + id + `,state="connected"} 1`,
+		"db_state{" + id + `,state="disconnected"} 0`,
+		"db_uptime_seconds{" + id + "} 90",
+		"db_queries_by_target_total{" + id + `,target="primary"} 3`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("metrics missing %q:\n%s", line, out)
+		}
+	}
+}
+
+func TestMetricsDisconnected(t *testing.T) {
+	db := connected(t)
+	if err := db.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	out := db.Metrics()
+	id := fmt.Sprintf(`connection_id="%d"`, db.GetConnectionID())
+	for _, line := range []string{
+		"db_state{" + id + `,state="disconnected"} 1`,
+		"db_state{" + id + `,state="connected"} 0`,
+		"db_uptime_seconds{" + id + "} 0",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("metrics missing %q:\n%s", line, out)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package singleton
+
+import "time"
+
+// QueryFunc executes one query against the backend
+type QueryFunc func(sql string, args []any) (Result, error)
+
+// QueryMiddleware wraps a QueryFunc with extra behaviour, such as timing,
+// logging or retries, without changing the connection itself
+type QueryMiddleware func(next QueryFunc) QueryFunc
+
+// Use adds middleware around every query the connection runs, whichever
+// method it comes through. The first middleware is the outermost: it sees
+// the query first and the result last. Later calls to Use wrap inside the
+// middleware added earlier.
+//
+// Middleware runs with the connection locked, so it must not call back into
+// the connection.
+func (db *DatabaseConnection) Use(middleware ...QueryMiddleware) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.middleware = append(db.middleware, middleware...)
+}
+
+// wrapQueryLocked applies the connection's middleware around exec.
+// The caller must hold db.mu.
+func (db *DatabaseConnection) wrapQueryLocked(exec QueryFunc) QueryFunc {
+	for i := len(db.middleware) - 1; i >= 0; i-- {
+		exec = db.middleware[i](exec)
+	}
+	return exec
+}
+
+// QueryTiming returns middleware that reports how long each query took,
+// failed ones included. A nil clock means the real one.
+func QueryTiming(clock Clock, observe func(sql string, d time.Duration)) QueryMiddleware {
+	if clock == nil {
+		clock = realClock{}
+	}
+	return func(next QueryFunc) QueryFunc {
+		return func(sql string, args []any) (Result, error) {
+			start := clock.Now()
+			result, err := next(sql, args)
+			observe(sql, clock.Now().Sub(start))
+			return result, err
+		}
+	}
+}
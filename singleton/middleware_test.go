@@ -0,0 +1,107 @@
+package singleton
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// tagMiddleware records its name on the way in and on the way out
+func tagMiddleware(name string, trace *[]string) QueryMiddleware {
+	return func(next QueryFunc) QueryFunc {
+		return func(sql string, args []any) (Result, error) {
+			*trace = append(*trace, name+">")
+			result, err := next(sql, args)
+			*trace = append(*trace, "<"+name)
+			return result, err
+		}
+	}
+}
+
+func TestQueryMiddlewareOrder(t *testing.T) {
+	db := connected(t)
+	var trace []string
+	db.Use(tagMiddleware("outer", &trace), tagMiddleware("middle", &trace))
+	db.Use(tagMiddleware("inner", &trace))
+
+	if _, err := db.QueryArgs("SELECT * FROM users WHERE id = ?", 1); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"outer>", "middle>", "inner>", "<inner", "<middle", "<outer"}
+	if !slices.Equal(trace, want) {
+		t.Errorf("trace = %v, want %v", trace, want)
+	}
+}
+
+func TestQueryMiddlewareWrapsEveryMethod(t *testing.T) {
+	db := connected(t)
+	var seen []string
+	db.Use(func(next QueryFunc) QueryFunc {
+		return func(sql string, args []any) (Result, error) {
+			seen = append(seen, sql)
+			return next(sql, args)
+		}
+	})
+
+	db.Query("SELECT 1")
+	if _, err := db.QueryArgs("SELECT ?", 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO users (name) VALUES (?)", "ann"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"SELECT 1", "SELECT ?", "INSERT INTO users (name) VALUES (?)"}
+	if !slices.Equal(seen, want) {
+		t.Errorf("middleware saw %v, want %v", seen, want)
+	}
+}
+
+func TestQueryTiming(t *testing.T) {
+	db := connected(t)
+	clock := newFakeClock()
+	durations := map[string]time.Duration{
+		"SELECT 1": 5 * time.Millisecond,
+		"SELECT 2": 40 * time.Millisecond,
+	}
+	observed := make(map[string]time.Duration)
+
+	// The inner middleware stands in for a slow backend by moving the clock
+	slowBackend := func(next QueryFunc) QueryFunc {
+		return func(sql string, args []any) (Result, error) {
+			clock.Advance(durations[sql])
+			return next(sql, args)
+		}
+	}
+	db.Use(QueryTiming(clock, func(sql string, d time.Duration) { observed[sql] = d }), slowBackend)
+
+	for sql := range durations {
+		if _, err := db.QueryArgs(sql); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for sql, want := range durations {
+		if observed[sql] != want {
+			t.Errorf("observed %s took %v, want %v", sql, observed[sql], want)
+		}
+	}
+}
+
+func TestQueryTimingObservesFailures(t *testing.T) {
+	db := connected(t)
+	errBoom := errors.New("boom")
+	var observed []string
+	db.Use(
+		QueryTiming(nil, func(sql string, d time.Duration) { observed = append(observed, sql) }),
+		func(QueryFunc) QueryFunc {
+			return func(string, []any) (Result, error) { return Result{}, errBoom }
+		},
+	)
+
+	if _, err := db.QueryArgs("SELECT 1"); !errors.Is(err, errBoom) {
+		t.Fatalf("QueryArgs = %v, want the middleware's error", err)
+	}
+	if len(observed) != 1 || observed[0] != "SELECT 1" {
+		t.Errorf("observed = %v, want the failed query timed", observed)
+	}
+}
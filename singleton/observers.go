@@ -0,0 +1,50 @@
+package singleton
+
+import "sync"
+
+// ConnectionObserver is called when a connection changes lifecycle state
+type ConnectionObserver func(db *DatabaseConnection)
+
+// observers holds lifecycle callbacks. It has its own lock, separate from the
+// connection state lock, so callbacks can safely call back into the connection.
+type observers struct {
+	mu           sync.Mutex
+	onConnect    []ConnectionObserver
+	onDisconnect []ConnectionObserver
+}
+
+// OnConnect registers fn to run after every successful connect.
+// Safe to call from any goroutine, including from inside another observer.
+func (db *DatabaseConnection) OnConnect(fn ConnectionObserver) {
+	db.observers.mu.Lock()
+	defer db.observers.mu.Unlock()
+	db.observers.onConnect = append(db.observers.onConnect, fn)
+}
+
+// OnDisconnect registers fn to run after the connection disconnects or closes
+func (db *DatabaseConnection) OnDisconnect(fn ConnectionObserver) {
+	db.observers.mu.Lock()
+	defer db.observers.mu.Unlock()
+	db.observers.onDisconnect = append(db.observers.onDisconnect, fn)
+}
+
+func (db *DatabaseConnection) notifyConnect() {
+	db.notify(&db.observers.onConnect)
+}
+
+func (db *DatabaseConnection) notifyDisconnect() {
+	db.notify(&db.observers.onDisconnect)
+}
+
+// notify copies the observer list under the lock and calls the copy without it.
+// Holding the lock during callbacks would deadlock any observer that registers
+// another observer, and would stall registration while slow callbacks run.
+func (db *DatabaseConnection) notify(list *[]ConnectionObserver) {
+	db.observers.mu.Lock()
+	snapshot := append([]ConnectionObserver(nil), *list...)
+	db.observers.mu.Unlock()
+
+	for _, fn := range snapshot {
+		fn(db)
+	}
+}
@@ -0,0 +1,83 @@
+package singleton
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Run with -race: registration and firing happen at the same time
+func TestObserversConcurrentRegistration(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+
+	const observers = 50
+	var connects, disconnects [observers]atomic.Int32
+	stop := make(chan struct{})
+	cycled := make(chan struct{})
+	go func() {
+		defer close(cycled)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			db.Connect()
+			db.Disconnect()
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := range observers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db.OnConnect(func(*DatabaseConnection) { connects[i].Add(1) })
+			db.OnDisconnect(func(*DatabaseConnection) { disconnects[i].Add(1) })
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	<-cycled
+
+	// One more cycle after every registration finished reaches them all
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range observers {
+		if connects[i].Load() == 0 || disconnects[i].Load() == 0 {
+			t.Errorf("observer %d fired %d connect and %d disconnect times, want at least 1 each",
+				i, connects[i].Load(), disconnects[i].Load())
+		}
+	}
+}
+
+// Observers run without any lock held, so they can call back into the connection
+func TestObserverReentry(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	var later atomic.Int32
+	var seen State
+	db.OnConnect(func(db *DatabaseConnection) {
+		seen = db.State()
+		db.OnConnect(func(*DatabaseConnection) { later.Add(1) })
+	})
+
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if seen != Connected {
+		t.Errorf("observer saw state %v, want Connected", seen)
+	}
+	if later.Load() != 0 {
+		t.Error("an observer registered during notification fired in the same round")
+	}
+
+	db.Disconnect()
+	db.Connect()
+	if later.Load() != 1 {
+		t.Errorf("observer registered from a callback fired %d times on the next connect, want 1", later.Load())
+	}
+}
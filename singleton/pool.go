@@ -0,0 +1,168 @@
+package singleton
+
+import (
+	"sync"
+	"time"
+
+	"go-design-patterns/pool"
+)
+
+// ConnectionPool is the alternative to the singleton when one shared
+// connection becomes a bottleneck: a fixed maximum of connections to the same
+// database, each used by one caller at a time and then returned for reuse.
+type ConnectionPool struct {
+	pool *pool.Pool[*DatabaseConnection]
+
+	mu    sync.Mutex
+	clock Clock
+}
+
+// NewConnectionPool returns a pool of up to max connections to conn.
+// Connections are created and connected on demand.
+func NewConnectionPool(conn string, max int) (*ConnectionPool, error) {
+	info, err := ParseConnectionString(conn)
+	if err != nil {
+		return nil, err
+	}
+	p := &ConnectionPool{clock: realClock{}}
+	p.pool = pool.New(func() (*DatabaseConnection, error) { return p.open(info), nil }, max)
+	return p, nil
+}
+
+// open creates a connection on the pool's clock and connects it. A failed
+// connect is only logged: Get tries again before handing it out.
+func (p *ConnectionPool) open(info ConnInfo) *DatabaseConnection {
+	db := newConnection(info)
+	db.SetClock(p.getClock())
+	if err := db.Connect(); err != nil {
+		logger.Printf("Pool: connect failed: %v\n", err)
+	}
+	return db
+}
+
+// SetClock replaces the clock that connections created from now on use for
+// idle tracking, and that the maintainer reads. Set it before first use.
+func (p *ConnectionPool) SetClock(clock Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = clock
+}
+
+func (p *ConnectionPool) getClock() Clock {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.clock
+}
+
+// Get returns a connected connection, waiting if all of them are in use.
+// Give it back with Put when done.
+func (p *ConnectionPool) Get() (*DatabaseConnection, error) {
+	db, err := p.pool.Get()
+	if err != nil {
+		return nil, err
+	}
+	if db.State() != Connected {
+		if err := db.Connect(); err != nil {
+			p.pool.Put(db)
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// Put returns a connection obtained from Get
+func (p *ConnectionPool) Put(db *DatabaseConnection) {
+	p.pool.Put(db)
+}
+
+// Size returns how many connections have been opened so far
+func (p *ConnectionPool) Size() int {
+	return p.pool.Created()
+}
+
+// Idle returns how many connections are waiting to be reused
+func (p *ConnectionPool) Idle() int {
+	return p.pool.Idle()
+}
+
+// PoolMaintainer keeps a ConnectionPool in shape in the background; see
+// StartMaintainer
+type PoolMaintainer struct {
+	pool        *ConnectionPool
+	minIdle     int
+	maxIdleTime time.Duration
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// StartMaintainer starts a goroutine that, every interval, opens connections
+// until at least minIdle are idle and closes idle connections that have seen
+// no activity for longer than maxIdleTime, beyond the minIdle it keeps.
+// A maxIdleTime of zero never closes anything. Idle time is measured on the
+// pool's clock; the interval always uses real time, so tests with a fake
+// clock call RunOnce instead of waiting for it.
+//
+// Call Stop to shut the maintainer down.
+func (p *ConnectionPool) StartMaintainer(minIdle int, maxIdleTime, interval time.Duration) *PoolMaintainer {
+	m := &PoolMaintainer{
+		pool:        p,
+		minIdle:     minIdle,
+		maxIdleTime: maxIdleTime,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go m.run(interval)
+	return m
+}
+
+func (m *PoolMaintainer) run(interval time.Duration) {
+	defer close(m.done)
+	m.RunOnce()
+	if interval <= 0 {
+		<-m.stop
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.RunOnce()
+		}
+	}
+}
+
+// RunOnce does one maintenance pass right away: it closes connections idle
+// too long, then tops the pool up to minIdle. It returns how many
+// connections it opened and closed.
+func (m *PoolMaintainer) RunOnce() (opened, closed int) {
+	if m.maxIdleTime > 0 {
+		now := m.pool.getClock().Now()
+		stale := m.pool.pool.Evict(m.minIdle, func(db *DatabaseConnection) bool {
+			return db.idleFor(now) > m.maxIdleTime
+		})
+		for _, db := range stale {
+			if err := db.Close(); err != nil {
+				logger.Printf("Pool: close idle connection: %v\n", err)
+			}
+		}
+		closed = len(stale)
+	}
+	opened, err := m.pool.pool.Fill(m.minIdle)
+	if err != nil {
+		logger.Printf("Pool: open idle connection: %v\n", err)
+	}
+	return opened, closed
+}
+
+// Stop shuts the maintainer down and waits for its goroutine to exit.
+// Calling it more than once is fine.
+func (m *PoolMaintainer) Stop() {
+	m.stopOnce.Do(func() { close(m.stop) })
+	<-m.done
+}
@@ -0,0 +1,191 @@
+package singleton
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConnectionPoolReuse(t *testing.T) {
+	p, err := NewConnectionPool(DefaultConnectionString, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.State() != Connected {
+		t.Errorf("pooled connection is %s, want Connected", a.State())
+	}
+	p.Put(a)
+	b, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b != a {
+		t.Error("Get after Put opened a new connection instead of reusing one")
+	}
+	p.Put(b)
+}
+
+func TestConnectionPoolConcurrent(t *testing.T) {
+	p, err := NewConnectionPool(DefaultConnectionString, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				db, err := p.Get()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := db.QueryArgs("SELECT 1"); err != nil {
+					t.Error(err)
+				}
+				p.Put(db)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := p.Size(); got > 3 {
+		t.Errorf("Size = %d, want at most 3", got)
+	}
+	if p.Idle() != p.Size() {
+		t.Errorf("Idle = %d, want all %d connections back", p.Idle(), p.Size())
+	}
+}
+
+func TestConnectionPoolReconnects(t *testing.T) {
+	p, err := NewConnectionPool(DefaultConnectionString, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := p.Get()
+	db.Disconnect()
+	p.Put(db)
+
+	again, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != db || again.State() != Connected {
+		t.Errorf("Get returned a %s connection, want the same one reconnected", again.State())
+	}
+	p.Put(again)
+}
+
+func TestNewConnectionPoolInvalid(t *testing.T) {
+	if _, err := NewConnectionPool("not a url", 1); err == nil {
+		t.Error("NewConnectionPool accepted an invalid connection string")
+	}
+}
+
+// fakeClockPool returns a pool of up to max connections whose idle time is
+// measured on a fake clock
+func fakeClockPool(t *testing.T, max int) (*ConnectionPool, *fakeClock) {
+	t.Helper()
+	p, err := NewConnectionPool(DefaultConnectionString, max)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := newFakeClock()
+	p.SetClock(clock)
+	return p, clock
+}
+
+func TestPoolMaintainerReapsIdleConnections(t *testing.T) {
+	p, clock := fakeClockPool(t, 4)
+	m := &PoolMaintainer{pool: p, minIdle: 1, maxIdleTime: time.Minute}
+
+	var conns []*DatabaseConnection
+	for range 3 {
+		conns = append(conns, mustGet(t, p))
+	}
+	clock.Advance(30 * time.Second)
+	if _, err := conns[2].QueryArgs("SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, db := range conns {
+		p.Put(db)
+	}
+
+	// Within the idle window nothing goes
+	if opened, closed := m.RunOnce(); opened != 0 || closed != 0 {
+		t.Fatalf("RunOnce = %d opened, %d closed, want nothing to do", opened, closed)
+	}
+
+	// Past it, the two untouched connections go and the recently used one stays
+	clock.Advance(45 * time.Second)
+	if opened, closed := m.RunOnce(); opened != 0 || closed != 2 {
+		t.Fatalf("RunOnce = %d opened, %d closed, want 2 closed", opened, closed)
+	}
+	if conns[0].State() != Closed || conns[1].State() != Closed || conns[2].State() != Connected {
+		t.Errorf("states = %v %v %v, want the stale two closed", conns[0].State(), conns[1].State(), conns[2].State())
+	}
+	if p.Idle() != 1 {
+		t.Errorf("Idle = %d, want 1", p.Idle())
+	}
+}
+
+func TestPoolMaintainerKeepsMinIdle(t *testing.T) {
+	p, clock := fakeClockPool(t, 4)
+	m := &PoolMaintainer{pool: p, minIdle: 2, maxIdleTime: time.Minute}
+
+	if opened, closed := m.RunOnce(); opened != 2 || closed != 0 {
+		t.Fatalf("RunOnce on an empty pool = %d opened, %d closed, want 2 opened", opened, closed)
+	}
+
+	// Even when every connection is stale, minIdle of them stay
+	clock.Advance(time.Hour)
+	if opened, closed := m.RunOnce(); opened != 0 || closed != 0 {
+		t.Errorf("RunOnce = %d opened, %d closed, want the minimum kept", opened, closed)
+	}
+	db := mustGet(t, p)
+	if db.State() != Connected {
+		t.Errorf("warm connection is %s, want Connected", db.State())
+	}
+	p.Put(db)
+}
+
+func TestPoolMaintainerNoMaxIdleTime(t *testing.T) {
+	p, clock := fakeClockPool(t, 4)
+	m := &PoolMaintainer{pool: p, minIdle: 0}
+	p.Put(mustGet(t, p))
+
+	clock.Advance(24 * time.Hour)
+	if _, closed := m.RunOnce(); closed != 0 || p.Idle() != 1 {
+		t.Errorf("closed %d with no maxIdleTime, want 0", closed)
+	}
+}
+
+func TestPoolMaintainerStop(t *testing.T) {
+	p, _ := fakeClockPool(t, 4)
+	for _, interval := range []time.Duration{0, time.Millisecond} {
+		m := p.StartMaintainer(3, time.Minute, interval)
+		m.Stop()
+		m.Stop()
+	}
+	// The first pass runs as soon as the maintainer starts
+	if p.Idle() != 3 {
+		t.Errorf("Idle = %d after the maintainer ran, want 3", p.Idle())
+	}
+}
+
+// mustGet takes a connection from p, failing the test on error
+func mustGet(t *testing.T, p *ConnectionPool) *DatabaseConnection {
+	t.Helper()
+	db, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
@@ -0,0 +1,93 @@
+package singleton
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotConnected is returned when a query is run before Connect()
+var ErrNotConnected = errors.New("not connected to database")
+
+// QueryArgs simulates executing a parameterized query.
+// Each "?" placeholder in sql is bound to the next value in args; a mismatch
+// between placeholders and arguments is rejected before anything runs.
+// The bound arguments are recorded and can be inspected with LastQuery.
+func (db *DatabaseConnection) QueryArgs(sql string, args ...any) ([]map[string]any, error) {
+	placeholders := countPlaceholders(sql)
+	if placeholders != len(args) {
+		return nil, fmt.Errorf("query has %d placeholders but %d args were given", placeholders, len(args))
+	}
+	if err := db.reconnectIfIdle(); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.draining {
+		return nil, ErrDraining
+	}
+	result, err := db.queryLocked(sql, args)
+	return result.Rows, err
+}
+
+// Exec runs a parameterized statement like QueryArgs, but returns the full
+// Result, so INSERT, UPDATE and DELETE can report the rows they affected
+func (db *DatabaseConnection) Exec(sql string, args ...any) (Result, error) {
+	placeholders := countPlaceholders(sql)
+	if placeholders != len(args) {
+		return Result{}, fmt.Errorf("statement has %d placeholders but %d args were given", placeholders, len(args))
+	}
+	if err := db.reconnectIfIdle(); err != nil {
+		return Result{}, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.draining {
+		return Result{}, ErrDraining
+	}
+	return db.queryLocked(sql, args)
+}
+
+// queryLocked runs an already validated query. Callers check for a stale
+// connection with reconnectIfIdle first. The caller must hold db.mu.
+func (db *DatabaseConnection) queryLocked(sql string, args []any) (Result, error) {
+	if db.state != Connected {
+		return Result{}, ErrNotConnected
+	}
+
+	db.lastSQL = sql
+	db.lastArgs = append([]any(nil), args...)
+	logger.Printf("Executing query: %s %v (Connection ID: %d)\n", sql, args, db.connectionID)
+	db.recordLocked(EventQuery, sql)
+	result, err := db.execLocked(sql, args...)
+	if err != nil {
+		db.recordLocked(EventError, err.Error())
+		return Result{}, err
+	}
+	db.queryCount++
+	db.lastActivity = db.clock.Now()
+	return result, nil
+}
+
+// LastQuery returns the SQL and bound arguments of the last QueryArgs call
+func (db *DatabaseConnection) LastQuery() (string, []any) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.lastSQL, append([]any(nil), db.lastArgs...)
+}
+
+// countPlaceholders counts "?" placeholders, ignoring any inside quoted string literals
+func countPlaceholders(sql string) int {
+	count := 0
+	inQuote := false
+	for _, r := range sql {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+		case r == '?' && !inQuote:
+			count++
+		}
+	}
+	return count
+}
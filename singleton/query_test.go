@@ -0,0 +1,66 @@
+package singleton
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestQueryArgs(t *testing.T) {
+	db := connected(t)
+
+	rows, err := db.QueryArgs("SELECT * FROM users WHERE id = ? AND name = ?", 42, "ann")
+	if err != nil {
+		t.Fatalf("QueryArgs: %v", err)
+	}
+	if rows == nil {
+		t.Error("QueryArgs returned nil rows for a SELECT, want an empty result set")
+	}
+	sql, args := db.LastQuery()
+	if sql != "SELECT * FROM users WHERE id = ? AND name = ?" || !slices.Equal(args, []any{42, "ann"}) {
+		t.Errorf("LastQuery() = %q, %v", sql, args)
+	}
+}
+
+func TestQueryArgsPlaceholderMismatch(t *testing.T) {
+	db := connected(t)
+	tests := []struct {
+		sql  string
+		args []any
+	}{
+		{"SELECT * FROM users WHERE id = ?", nil},
+		{"SELECT * FROM users WHERE id = ?", []any{1, 2}},
+		{"SELECT * FROM users", []any{1}},
+		{"SELECT * FROM users WHERE name = '?'", []any{1}},
+	}
+	for _, tt := range tests {
+		if _, err := db.QueryArgs(tt.sql, tt.args...); err == nil {
+			t.Errorf("QueryArgs(%q, %v) succeeded, want a placeholder mismatch", tt.sql, tt.args)
+		}
+	}
+	if sql, _ := db.LastQuery(); sql != "" {
+		t.Errorf("a rejected query was recorded: %q", sql)
+	}
+}
+
+func TestQueryArgsNotConnected(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	if _, err := db.QueryArgs("SELECT ?", 1); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("QueryArgs while disconnected = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestCountPlaceholders(t *testing.T) {
+	tests := map[string]int{
+		"SELECT 1":                            0,
+		"SELECT ?":                            1,
+		"INSERT INTO t VALUES (?, ?, ?)":      3,
+		"SELECT * FROM t WHERE a = '?' OR ?":  1,
+		"SELECT * FROM t WHERE a = 'it''s ?'": 0,
+	}
+	for sql, want := range tests {
+		if got := countPlaceholders(sql); got != want {
+			t.Errorf("countPlaceholders(%q) = %d, want %d", sql, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package singleton
+
+import (
+	"errors"
+	"strings"
+)
+
+// SelectBuilder builds SELECT statements for Query step by step.
+// It's the Builder pattern from the builder package applied to SQL:
+//
+//	sql, err := Select("id", "name").From("users").Where("active = true").Build()
+//	db.Query(sql)
+type SelectBuilder struct {
+	columns    []string
+	table      string
+	conditions []string
+}
+
+// Select starts a query for the given columns
+func Select(columns ...string) *SelectBuilder {
+	return &SelectBuilder{columns: append([]string(nil), columns...)}
+}
+
+// From sets the table to read from
+func (s *SelectBuilder) From(table string) *SelectBuilder {
+	s.table = table
+	return s
+}
+
+// Where adds a condition; multiple conditions are combined with AND
+func (s *SelectBuilder) Where(condition string) *SelectBuilder {
+	s.conditions = append(s.conditions, condition)
+	return s
+}
+
+// Build validates the query and renders it as SQL
+func (s *SelectBuilder) Build() (string, error) {
+	if len(s.columns) == 0 {
+		return "", errors.New("select needs at least one column")
+	}
+	if strings.TrimSpace(s.table) == "" {
+		return "", errors.New("select needs a table; call From()")
+	}
+
+	var b strings.Builder
+	b.WriteString("SELECT ")
+	b.WriteString(strings.Join(s.columns, ", "))
+	b.WriteString(" FROM ")
+	b.WriteString(s.table)
+	if len(s.conditions) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(strings.Join(s.conditions, " AND "))
+	}
+	return b.String(), nil
+}
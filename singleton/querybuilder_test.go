@@ -0,0 +1,71 @@
+package singleton
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSelectBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SelectBuilder
+		want    string
+	}{
+		{"one column", Select("id").From("users"), "SELECT id FROM users"},
+		{"star", Select("*").From("orders"), "SELECT * FROM orders"},
+		{"columns", Select("id", "name", "email").From("users"), "SELECT id, name, email FROM users"},
+		{"where", Select("id").From("users").Where("active = true"), "SELECT id FROM users WHERE active = true"},
+		{"and", Select("id").From("users").Where("active = true").Where("age > 18"), "SELECT id FROM users WHERE active = true AND age > 18"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectBuilderValidation(t *testing.T) {
+	tests := map[string]*SelectBuilder{
+		"no table":     Select("id"),
+		"blank table":  Select("id").From("  "),
+		"no columns":   Select().From("users"),
+		"only a where": Select().Where("id = 1"),
+	}
+	for name, b := range tests {
+		if sql, err := b.Build(); err == nil {
+			t.Errorf("%s: Build() = %q, want an error", name, sql)
+		}
+	}
+}
+
+func TestSelectBuilderCopiesColumns(t *testing.T) {
+	columns := []string{"id", "name"}
+	b := Select(columns...).From("users")
+	columns[0] = "password"
+	if sql, _ := b.Build(); sql != "SELECT id, name FROM users" {
+		t.Errorf("Build() = %q after changing the caller's slice", sql)
+	}
+}
+
+func TestSelectBuilderFeedsQuery(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	driver := NewMemoryDriver()
+	db.driver = driver
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	sql, err := Select("id").From("users").Where("id = 1").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Query(sql)
+	if got := driver.Executed(); !slices.Equal(got, []string{sql}) {
+		t.Errorf("driver executed %v, want %q", got, sql)
+	}
+}
@@ -0,0 +1,62 @@
+package singleton
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"go-design-patterns/redact"
+)
+
+func TestRedactConnection(t *testing.T) {
+	db := newConnection(mustParseConnectionString("postgresql://svc:hunter2@db:5432/app"))
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	stmt, err := db.Prepare("SELECT * FROM users WHERE id = ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := redact.Redact(db).(map[string]any)
+	if s := got["ConnectionString"].(string); strings.Contains(s, "hunter2") || !strings.Contains(s, "svc:xxxxx@db") {
+		t.Errorf("ConnectionString = %q, want the password masked", s)
+	}
+	if got["State"] != Connected.String() || got["Statements"] != 1 {
+		t.Errorf("Redact(db) = %v", got)
+	}
+
+	// The statement points back at the connection that holds it
+	stmtInfo := redact.Redact(stmt).(map[string]any)
+	if stmtInfo["SQL"] != "SELECT * FROM users WHERE id = ?" || stmtInfo["Closed"] != false {
+		t.Errorf("Redact(stmt) = %v", stmtInfo)
+	}
+}
+
+func TestRedactConcurrentWithQueries(t *testing.T) {
+	db := connected(t)
+	stmt, err := db.Prepare("SELECT ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				db.QueryArgs("SELECT 1")
+				stmt.Exec(i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				redact.Redact(db)
+				redact.Redact(stmt)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,77 @@
+package singleton
+
+import "fmt"
+
+// replica is a read-only copy of the database that SELECTs are sent to
+type replica struct {
+	info   ConnInfo
+	driver Driver
+	open   bool
+}
+
+// SetReplica routes read queries (SELECTs) to a replica at conn, while
+// everything else keeps going to the primary. The replica runs on its own
+// in-memory driver and is opened on the first read. An empty conn removes
+// the replica, sending all queries to the primary again.
+func (db *DatabaseConnection) SetReplica(conn string) error {
+	var info ConnInfo
+	if conn != "" {
+		parsed, err := ParseConnectionString(conn)
+		if err != nil {
+			return fmt.Errorf("replica: %w", err)
+		}
+		info = parsed
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.closeReplicaLocked()
+	db.replica = nil
+	if conn != "" {
+		db.replica = &replica{info: info, driver: NewMemoryDriver()}
+	}
+	return nil
+}
+
+// QueryCounts returns how many queries went to the primary and to the replica
+func (db *DatabaseConnection) QueryCounts() (primary, replica int) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.primaryQueries, db.replicaQueries
+}
+
+// execLocked runs sql through the query middleware, then on the replica if
+// it's a read and a replica is set, and on the primary otherwise.
+// The caller must hold db.mu.
+func (db *DatabaseConnection) execLocked(sql string, args ...any) (Result, error) {
+	return db.wrapQueryLocked(db.routeLocked)(sql, args)
+}
+
+// routeLocked sends a query to the replica or the primary; see execLocked.
+// The caller must hold db.mu.
+func (db *DatabaseConnection) routeLocked(sql string, args []any) (Result, error) {
+	if db.replica == nil || !isSelect(sql) {
+		db.primaryQueries++
+		return db.driver.Exec(sql, args...)
+	}
+
+	if !db.replica.open {
+		if err := db.replica.driver.Open(db.replica.info.String()); err != nil {
+			return Result{}, fmt.Errorf("open replica: %w", err)
+		}
+		db.replica.open = true
+	}
+	db.replicaQueries++
+	return db.replica.driver.Exec(sql, args...)
+}
+
+// closeReplicaLocked closes the replica if it was opened. The caller must hold db.mu.
+func (db *DatabaseConnection) closeReplicaLocked() {
+	if db.replica == nil || !db.replica.open {
+		return
+	}
+	if err := db.replica.driver.Close(); err != nil {
+		logger.Printf("Error closing replica: %v\n", err)
+	}
+	db.replica.open = false
+}
@@ -0,0 +1,56 @@
+package singleton
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestReplicaRouting(t *testing.T) {
+	db := connected(t)
+	if err := db.SetReplica("postgresql://replica:5432/mydb"); err != nil {
+		t.Fatal(err)
+	}
+
+	db.Query("select * from users")
+	if primary, replica := db.QueryCounts(); primary != 0 || replica != 1 {
+		t.Errorf("after a SELECT: primary %d, replica %d, want 0 and 1", primary, replica)
+	}
+	db.Query("INSERT INTO users (name) VALUES ('ann')")
+	if primary, replica := db.QueryCounts(); primary != 1 || replica != 1 {
+		t.Errorf("after an INSERT: primary %d, replica %d, want 1 and 1", primary, replica)
+	}
+
+	primaryRan := fmt.Sprint(db.driver.(*MemoryDriver).Executed())
+	replicaRan := fmt.Sprint(db.replica.driver.(*MemoryDriver).Executed())
+	if strings.Contains(primaryRan, "select") || !strings.Contains(replicaRan, "select") {
+		t.Errorf("primary ran %s, replica ran %s", primaryRan, replicaRan)
+	}
+}
+
+func TestReplicaRemoved(t *testing.T) {
+	db := connected(t)
+	if err := db.SetReplica("postgresql://replica:5432/mydb"); err != nil {
+		t.Fatal(err)
+	}
+	db.Query("SELECT 1")
+	replicaDriver := db.replica.driver.(*MemoryDriver)
+
+	if err := db.SetReplica(""); err != nil {
+		t.Fatal(err)
+	}
+	db.Query("SELECT 2")
+	if primary, replica := db.QueryCounts(); primary != 1 || replica != 1 {
+		t.Errorf("primary %d, replica %d, want the second SELECT on the primary", primary, replica)
+	}
+	if _, err := replicaDriver.Exec("SELECT 3"); err == nil {
+		t.Error("the removed replica was left open")
+	}
+}
+
+func TestSetReplicaInvalid(t *testing.T) {
+	db := connected(t)
+	if err := db.SetReplica("not a url"); err == nil || !strings.HasPrefix(err.Error(), "replica:") {
+		t.Errorf("SetReplica = %v, want a replica error", err)
+	}
+}
@@ -0,0 +1,98 @@
+package singleton
+
+import "testing"
+
+// affected returns a Result's rows affected and last insert ID, failing the
+// test on an error from either
+func affected(t *testing.T, r Result) (rows, lastID int64) {
+	t.Helper()
+	rows, err := r.RowsAffected()
+	if err != nil {
+		t.Fatalf("RowsAffected: %v", err)
+	}
+	lastID, err = r.LastInsertID()
+	if err != nil {
+		t.Fatalf("LastInsertID: %v", err)
+	}
+	return rows, lastID
+}
+
+func TestExecResults(t *testing.T) {
+	db := connected(t)
+	steps := []struct {
+		sql        string
+		args       []any
+		rows, last int64
+	}{
+		{"INSERT INTO users (name) VALUES (?)", []any{"ann"}, 1, 1},
+		{"INSERT INTO users (name) VALUES ('b(o)b'), ('cy')", nil, 2, 3},
+		{"insert into Orders (id) values (?)", []any{7}, 1, 4},
+		{"SELECT * FROM users", nil, 0, 0},
+		{"UPDATE users SET name = ? WHERE id = ?", []any{"dee", 1}, 1, 0},
+		{"UPDATE users SET active = true", nil, 3, 0},
+		{"DELETE FROM users WHERE id = ?", []any{2}, 1, 0},
+		{"DELETE FROM users", nil, 2, 0},
+		{"DELETE FROM users", nil, 0, 0},
+		{"UPDATE missing SET x = 1", nil, 0, 0},
+		{"CREATE TABLE t (id int)", nil, 0, 0},
+	}
+	for _, step := range steps {
+		result, err := db.Exec(step.sql, step.args...)
+		if err != nil {
+			t.Fatalf("Exec(%q): %v", step.sql, err)
+		}
+		rows, last := affected(t, result)
+		if rows != step.rows || last != step.last {
+			t.Errorf("Exec(%q) = %d rows, last ID %d, want %d and %d", step.sql, rows, last, step.rows, step.last)
+		}
+	}
+}
+
+func TestSelectResultHasRows(t *testing.T) {
+	db := connected(t)
+	result, err := db.Exec("SELECT * FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows == nil {
+		t.Error("SELECT returned nil rows, want an empty result set")
+	}
+	if rows, last := affected(t, result); rows != 0 || last != 0 {
+		t.Errorf("SELECT = %d rows, last ID %d, want zero", rows, last)
+	}
+}
+
+func TestStmtExecResult(t *testing.T) {
+	db := connected(t)
+	stmt, err := db.Prepare("INSERT INTO users (name) VALUES (?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for want := int64(1); want <= 3; want++ {
+		result, err := stmt.Exec("user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rows, last := affected(t, result); rows != 1 || last != want {
+			t.Errorf("insert %d = %d rows, last ID %d", want, rows, last)
+		}
+	}
+}
+
+func TestCountValueTuples(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want int64
+	}{
+		{"INSERT INTO t VALUES (1)", 1},
+		{"INSERT INTO t VALUES (1), (2), (3)", 3},
+		{"INSERT INTO t VALUES ((1 + 2)), (3)", 2},
+		{"INSERT INTO t VALUES ('a), (b'), ('c')", 2},
+		{"INSERT INTO t SELECT * FROM s", 1},
+	}
+	for _, tt := range tests {
+		if got := countValueTuples(tt.sql); got != tt.want {
+			t.Errorf("countValueTuples(%q) = %d, want %d", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DialFunc opens the underlying network connection.
+// The simulated database never fails to dial, but tests and demos can inject
+// a flaky DialFunc to exercise retry logic.
+type DialFunc func(ctx context.Context, info ConnInfo) error
+
+// SetDialFunc replaces the function ConnectWithRetry uses to dial.
+// The driver is opened once the dial succeeds. Passing nil removes the dial step.
+func (db *DatabaseConnection) SetDialFunc(dial DialFunc) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.dial = dial
+}
+
+// ConnectWithRetry dials up to attempts times, doubling the wait between
+// attempts starting from backoff. It stops early if ctx is cancelled and
+// otherwise returns the last dial error once all attempts are used up.
+//
+// Dialing and waiting happen without the connection lock, so the connection
+// stays Disconnected between attempts and other methods keep working. Once a
+// dial succeeds, the connect itself goes through transition like Connect
+// does. If another goroutine connects in the meantime, ConnectWithRetry
+// returns nil; if one closes the connection, it stops with an
+// IllegalTransitionError.
+func (db *DatabaseConnection) ConnectWithRetry(ctx context.Context, attempts int, backoff time.Duration) error {
+	if attempts < 1 {
+		return errors.New("attempts must be at least 1")
+	}
+
+	// If we give up, log why; the connection is still Disconnected
+	fail := func(err error) error {
+		db.mu.Lock()
+		defer db.mu.Unlock()
+		db.recordLocked(EventError, err.Error())
+		return err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		db.mu.Lock()
+		state, dial, info := db.state, db.dial, db.connInfo
+		db.mu.Unlock()
+		switch state {
+		case Connected:
+			return nil
+		case Closed:
+			return &IllegalTransitionError{From: Closed, To: Connecting}
+		}
+
+		lastErr = nil
+		if dial != nil {
+			lastErr = dial(ctx, info)
+		}
+		if lastErr == nil {
+			lastErr = db.transition(Connected)
+			if errors.Is(lastErr, ErrIllegalTransition) {
+				return lastErr
+			}
+			if lastErr == nil {
+				logger.Printf("Connected to database after %d attempt(s) (ID: %d)\n", attempt, db.connectionID)
+				return nil
+			}
+		}
+
+		logger.Printf("Connect attempt %d/%d failed: %v\n", attempt, attempts, lastErr)
+		if attempt == attempts {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fail(fmt.Errorf("connect cancelled after %d attempt(s): %w", attempt, ctx.Err()))
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+	return fail(fmt.Errorf("connect failed after %d attempts: %w", attempts, lastErr))
+}
@@ -0,0 +1,150 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyDial fails the first failures calls and counts every call
+func flakyDial(failures int, calls *int) DialFunc {
+	return func(ctx context.Context, info ConnInfo) error {
+		*calls++
+		if *calls <= failures {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+}
+
+func TestConnectWithRetrySucceedsOnLastAttempt(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	var calls int
+	db.SetDialFunc(flakyDial(2, &calls))
+
+	if err := db.ConnectWithRetry(context.Background(), 3, time.Millisecond); err != nil {
+		t.Fatalf("ConnectWithRetry: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("dialed %d times, want 3", calls)
+	}
+	if got := db.State(); got != Connected {
+		t.Errorf("State() = %v, want Connected", got)
+	}
+}
+
+func TestConnectWithRetryGivesUp(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	var calls int
+	db.SetDialFunc(flakyDial(100, &calls))
+
+	err := db.ConnectWithRetry(context.Background(), 3, time.Millisecond)
+	if err == nil || err.Error() != "connect failed after 3 attempts: connection refused" {
+		t.Errorf("ConnectWithRetry = %v, want the last dial error after 3 attempts", err)
+	}
+	if calls != 3 {
+		t.Errorf("dialed %d times, want 3", calls)
+	}
+	if got := db.State(); got != Disconnected {
+		t.Errorf("State() = %v, want Disconnected", got)
+	}
+	events := db.RecentEvents()
+	if len(events) == 0 || events[len(events)-1].Kind != EventError {
+		t.Errorf("events = %v, want the failure logged last", events)
+	}
+}
+
+func TestConnectWithRetryCancelled(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	db.SetDialFunc(func(context.Context, ConnInfo) error {
+		calls++
+		cancel()
+		return errors.New("connection refused")
+	})
+
+	err := db.ConnectWithRetry(ctx, 5, time.Hour)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ConnectWithRetry = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("dialed %d times after cancelling, want 1", calls)
+	}
+}
+
+func TestConnectWithRetryNoDial(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	if err := db.ConnectWithRetry(context.Background(), 1, 0); err != nil {
+		t.Fatalf("ConnectWithRetry without a dial func: %v", err)
+	}
+	if err := db.ConnectWithRetry(context.Background(), 0, 0); err == nil {
+		t.Error("ConnectWithRetry with 0 attempts succeeded")
+	}
+}
+
+func TestConnectWithRetryConnectedMeanwhile(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	driver := newCountingDriver()
+	db.driver = driver
+	// The dial runs without the lock, so another caller can connect during it
+	db.SetDialFunc(func(context.Context, ConnInfo) error {
+		return db.Connect()
+	})
+
+	if err := db.ConnectWithRetry(context.Background(), 3, time.Millisecond); err != nil {
+		t.Fatalf("ConnectWithRetry = %v, want nil once connected", err)
+	}
+	if got := db.State(); got != Connected {
+		t.Errorf("State() = %v, want Connected", got)
+	}
+	if opens, _ := driver.counts(); opens != 1 {
+		t.Errorf("driver opened %d times, want 1", opens)
+	}
+}
+
+func TestConnectWithRetryClosedMeanwhile(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	driver := newCountingDriver()
+	db.driver = driver
+	var calls int
+	db.SetDialFunc(func(context.Context, ConnInfo) error {
+		calls++
+		if err := db.Close(); err != nil {
+			t.Error(err)
+		}
+		return errors.New("connection refused")
+	})
+
+	err := db.ConnectWithRetry(context.Background(), 3, time.Millisecond)
+	if !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("ConnectWithRetry = %v, want ErrIllegalTransition", err)
+	}
+	if calls != 1 {
+		t.Errorf("dialed %d times, want to stop after the close", calls)
+	}
+	if got := db.State(); got != Closed {
+		t.Errorf("State() = %v, want Closed", got)
+	}
+	if opens, _ := driver.counts(); opens != 0 {
+		t.Errorf("driver opened %d times on a closed connection", opens)
+	}
+}
+
+func TestConnectWithRetryClosedBeforeOpen(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	driver := newCountingDriver()
+	db.driver = driver
+	// A successful dial still has to go through transition, which refuses
+	db.SetDialFunc(func(context.Context, ConnInfo) error {
+		return db.Close()
+	})
+
+	if err := db.ConnectWithRetry(context.Background(), 3, time.Millisecond); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("ConnectWithRetry = %v, want ErrIllegalTransition", err)
+	}
+	if opens, _ := driver.counts(); opens != 0 {
+		t.Errorf("driver opened %d times on a closed connection", opens)
+	}
+}
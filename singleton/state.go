@@ -0,0 +1,154 @@
+package singleton
+
+import (
+	"errors"
+	"fmt"
+)
+
+// State is where a connection is in its lifecycle
+type State int
+
+const (
+	Disconnected State = iota
+	Connecting
+	Connected
+	Closed
+)
+
+func (s State) String() string {
+	switch s {
+	case Disconnected:
+		return "disconnected"
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Closed:
+		return "closed"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// legalTransitions lists, for each state, the states it may move to.
+// Closed is terminal: nothing leaves it.
+var legalTransitions = map[State][]State{
+	Disconnected: {Connecting, Closed},
+	Connecting:   {Connected, Disconnected, Closed},
+	Connected:    {Disconnected, Closed},
+}
+
+// ErrIllegalTransition is matched (via errors.Is) by every IllegalTransitionError
+var ErrIllegalTransition = errors.New("illegal state transition")
+
+// IllegalTransitionError reports an attempt to move between two states
+// that aren't connected in the lifecycle, e.g. Connect after Close.
+type IllegalTransitionError struct {
+	From State
+	To   State
+}
+
+func (e *IllegalTransitionError) Error() string {
+	return fmt.Sprintf("illegal state transition: %s -> %s", e.From, e.To)
+}
+
+func (e *IllegalTransitionError) Is(target error) bool {
+	return target == ErrIllegalTransition
+}
+
+// State returns the connection's current lifecycle state
+func (db *DatabaseConnection) State() State {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.state
+}
+
+// transition is the single entry point for the public lifecycle methods:
+// Connect, Disconnect and Close are thin wrappers around it. It takes db.mu
+// for the whole change, including the driver call, so no other method sees
+// a half-finished transition. Asking for the state the connection is
+// already in is a no-op; anything legalTransitions doesn't allow returns an
+// IllegalTransitionError. Observers are notified after db.mu is released.
+func (db *DatabaseConnection) transition(to State) error {
+	// Deferred calls run in reverse order, so observers fire after the unlock below
+	var notify func()
+	defer func() {
+		if notify != nil {
+			notify()
+		}
+	}()
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.state == to {
+		if to == Connected {
+			logger.Printf("Already connected to database (ID: %d)\n", db.connectionID)
+		}
+		return nil
+	}
+
+	switch to {
+	case Connected:
+		if err := db.setStateLocked(Connecting); err != nil {
+			return err
+		}
+		if err := db.driver.Open(db.connectionString); err != nil {
+			db.setStateLocked(Disconnected)
+			db.recordLocked(EventError, err.Error())
+			return fmt.Errorf("open connection: %w", err)
+		}
+		if err := db.setStateLocked(Connected); err != nil {
+			return err
+		}
+		db.markConnectedLocked()
+		notify = db.notifyConnect
+		logger.Printf("Connected to database (ID: %d)\n", db.connectionID)
+		return nil
+
+	case Disconnected:
+		if err := db.setStateLocked(Disconnected); err != nil {
+			return err
+		}
+		notify = db.notifyDisconnect
+		db.recordLocked(EventDisconnect, "")
+		db.closeReplicaLocked()
+		if err := db.driver.Close(); err != nil {
+			return fmt.Errorf("close connection: %w", err)
+		}
+		logger.Printf("Disconnected from database (ID: %d)\n", db.connectionID)
+		return nil
+
+	case Closed:
+		wasConnected := db.state == Connected
+		if err := db.setStateLocked(Closed); err != nil {
+			return err
+		}
+		if wasConnected {
+			notify = db.notifyDisconnect
+			db.recordLocked(EventDisconnect, "closed")
+		}
+		db.closeStmtsLocked()
+		db.closeReplicaLocked()
+		if err := db.driver.Close(); err != nil {
+			return fmt.Errorf("close connection: %w", err)
+		}
+		logger.Printf("Closed database connection (ID: %d)\n", db.connectionID)
+		return nil
+	}
+
+	// Connecting is only ever passed through, never asked for
+	return &IllegalTransitionError{From: db.state, To: to}
+}
+
+// setStateLocked moves the connection to a new state if the transition is legal.
+// The caller must hold db.mu.
+func (db *DatabaseConnection) setStateLocked(to State) error {
+	for _, allowed := range legalTransitions[db.state] {
+		if allowed == to {
+			db.state = to
+			return nil
+		}
+	}
+	return &IllegalTransitionError{From: db.state, To: to}
+}
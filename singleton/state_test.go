@@ -0,0 +1,197 @@
+package singleton
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+var allStates = []State{Disconnected, Connecting, Connected, Closed}
+
+func TestSetStateLockedGuards(t *testing.T) {
+	for _, from := range allStates {
+		for _, to := range allStates {
+			legal := slices.Contains(legalTransitions[from], to)
+			t.Run(from.String()+"->"+to.String(), func(t *testing.T) {
+				db := newConnection(defaultConnInfo)
+				db.mu.Lock()
+				defer db.mu.Unlock()
+				db.state = from
+
+				err := db.setStateLocked(to)
+				if legal {
+					if err != nil || db.state != to {
+						t.Errorf("legal transition failed: %v, state %v", err, db.state)
+					}
+					return
+				}
+				var ite *IllegalTransitionError
+				if !errors.As(err, &ite) || ite.From != from || ite.To != to {
+					t.Fatalf("setStateLocked = %v, want IllegalTransitionError{%v, %v}", err, from, to)
+				}
+				if !errors.Is(err, ErrIllegalTransition) {
+					t.Error("error doesn't match ErrIllegalTransition")
+				}
+				if db.state != from {
+					t.Errorf("state changed to %v on an illegal transition", db.state)
+				}
+			})
+		}
+	}
+}
+
+func TestClosedIsTerminal(t *testing.T) {
+	db := connected(t)
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := db.Connect(); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("Connect after Close = %v, want ErrIllegalTransition", err)
+	}
+	if err := db.Disconnect(); !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("Disconnect after Close = %v, want ErrIllegalTransition", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Errorf("second Close = %v, want nil", err)
+	}
+	if got := db.State(); got != Closed {
+		t.Errorf("State() = %v, want Closed", got)
+	}
+}
+
+func TestStateString(t *testing.T) {
+	want := []string{"disconnected", "connecting", "connected", "closed"}
+	for i, s := range allStates {
+		if got := s.String(); got != want[i] {
+			t.Errorf("%d.String() = %q, want %q", int(s), got, want[i])
+		}
+	}
+	if got := State(42).String(); got != "State(42)" {
+		t.Errorf("unknown state String() = %q", got)
+	}
+}
+
+func TestLifecycleMethodsFromEachState(t *testing.T) {
+	type op struct {
+		name string
+		call func(*DatabaseConnection) error
+	}
+	connect := op{"Connect", (*DatabaseConnection).Connect}
+	disconnect := op{"Disconnect", (*DatabaseConnection).Disconnect}
+	closeConn := op{"Close", (*DatabaseConnection).Close}
+
+	tests := []struct {
+		from    State
+		op      op
+		want    State
+		illegal bool
+	}{
+		{Disconnected, connect, Connected, false},
+		{Disconnected, disconnect, Disconnected, false},
+		{Disconnected, closeConn, Closed, false},
+		{Connecting, connect, Connecting, true},
+		{Connecting, disconnect, Disconnected, false},
+		{Connecting, closeConn, Closed, false},
+		{Connected, connect, Connected, false},
+		{Connected, disconnect, Disconnected, false},
+		{Connected, closeConn, Closed, false},
+		{Closed, connect, Closed, true},
+		{Closed, disconnect, Closed, true},
+		{Closed, closeConn, Closed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from.String()+"/"+tt.op.name, func(t *testing.T) {
+			db := newConnection(defaultConnInfo)
+			switch tt.from {
+			case Connecting:
+				// Only ever passed through, so put the connection there by hand
+				db.mu.Lock()
+				db.state = Connecting
+				db.mu.Unlock()
+			case Connected:
+				if err := db.Connect(); err != nil {
+					t.Fatal(err)
+				}
+			case Closed:
+				if err := db.Close(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := tt.op.call(db)
+			if tt.illegal != errors.Is(err, ErrIllegalTransition) {
+				t.Errorf("%s from %v = %v, want illegal: %v", tt.op.name, tt.from, err, tt.illegal)
+			}
+			if !tt.illegal && err != nil {
+				t.Errorf("%s from %v = %v", tt.op.name, tt.from, err)
+			}
+			if got := db.State(); got != tt.want {
+				t.Errorf("State() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLifecycleConcurrentTransitions(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	driver := newCountingDriver()
+	db.driver = driver
+
+	ops := []func() error{db.Connect, db.Disconnect, db.Connect, db.Disconnect, db.Close}
+	stop := make(chan struct{})
+	var observers, workers sync.WaitGroup
+
+	// Observers only ever see settled states, and once Closed nothing else
+	for i := 0; i < 4; i++ {
+		observers.Add(1)
+		go func() {
+			defer observers.Done()
+			closed := false
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				switch s := db.State(); {
+				case s == Connecting:
+					t.Error("observed a half-finished transition")
+					return
+				case s != Disconnected && s != Connected && s != Closed:
+					t.Errorf("observed invalid state %v", s)
+					return
+				case closed && s != Closed:
+					t.Errorf("state went from Closed to %v", s)
+					return
+				case s == Closed:
+					closed = true
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 16; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for j := 0; j < 200; j++ {
+				// Only Close may fail, after someone else closed first
+				if err := ops[(i+j)%len(ops)](); err != nil && !errors.Is(err, ErrIllegalTransition) {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+		}()
+	}
+	workers.Wait()
+	close(stop)
+	observers.Wait()
+
+	if got := db.State(); got != Closed {
+		t.Errorf("final State() = %v, want Closed", got)
+	}
+	// Every open was matched by a close, so nothing leaked
+	if opens, closes := driver.counts(); closes < opens {
+		t.Errorf("driver opened %d times but closed only %d", opens, closes)
+	}
+}
@@ -0,0 +1,87 @@
+package singleton
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStmtClosed is returned when executing a statement that was closed,
+// either directly or because its connection was closed.
+var ErrStmtClosed = errors.New("statement is closed")
+
+// Stmt is a prepared statement bound to the connection that prepared it.
+// It can be executed many times with different arguments.
+type Stmt struct {
+	db           *DatabaseConnection
+	sql          string
+	placeholders int
+	closed       bool // guarded by db.mu
+}
+
+// Prepare validates sql once and returns a reusable statement.
+// The connection keeps track of its statements so Close can invalidate them.
+func (db *DatabaseConnection) Prepare(sql string) (*Stmt, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.state != Connected {
+		return nil, ErrNotConnected
+	}
+	if db.draining {
+		return nil, ErrDraining
+	}
+
+	stmt := &Stmt{db: db, sql: sql, placeholders: countPlaceholders(sql)}
+	if db.stmts == nil {
+		db.stmts = make(map[*Stmt]struct{})
+	}
+	db.stmts[stmt] = struct{}{}
+	return stmt, nil
+}
+
+// Exec runs the prepared statement with the given arguments
+func (s *Stmt) Exec(args ...any) (Result, error) {
+	if s.placeholders != len(args) {
+		return Result{}, fmt.Errorf("statement has %d placeholders but %d args were given", s.placeholders, len(args))
+	}
+	if err := s.db.reconnectIfIdle(); err != nil {
+		return Result{}, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if s.closed {
+		return Result{}, ErrStmtClosed
+	}
+	if s.db.draining {
+		return Result{}, ErrDraining
+	}
+	return s.db.queryLocked(s.sql, args)
+}
+
+// Close releases the statement. Closing twice is a no-op.
+func (s *Stmt) Close() error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.closed = true
+	delete(s.db.stmts, s)
+	return nil
+}
+
+// Close disconnects and invalidates every statement prepared on this connection.
+// Unlike Disconnect it is final: a closed connection can't be connected again.
+// Closing twice is a no-op.
+func (db *DatabaseConnection) Close() error {
+	return db.transition(Closed)
+}
+
+// closeStmtsLocked invalidates every statement prepared on this connection.
+// The caller must hold db.mu.
+func (db *DatabaseConnection) closeStmtsLocked() {
+	for stmt := range db.stmts {
+		stmt.closed = true
+	}
+	db.stmts = nil
+}
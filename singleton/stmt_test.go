@@ -0,0 +1,76 @@
+package singleton
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrepareWhileDisconnected(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	if _, err := db.Prepare("SELECT ?"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Prepare while disconnected = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestStmtExecReusable(t *testing.T) {
+	db := connected(t)
+	stmt, err := db.Prepare("INSERT INTO users (name) VALUES (?)")
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	for _, name := range []string{"ann", "bob"} {
+		if _, err := stmt.Exec(name); err != nil {
+			t.Fatalf("Exec(%q): %v", name, err)
+		}
+	}
+	if _, err := stmt.Exec(); err == nil {
+		t.Error("Exec with too few args succeeded")
+	}
+	if _, args := db.LastQuery(); len(args) != 1 || args[0] != "bob" {
+		t.Errorf("last args = %v, want [bob]", args)
+	}
+}
+
+func TestStmtExecAfterClose(t *testing.T) {
+	db := connected(t)
+	a, err := db.Prepare("SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := db.Prepare("SELECT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	for _, stmt := range []*Stmt{a, b} {
+		if _, err := stmt.Exec(); !errors.Is(err, ErrStmtClosed) {
+			t.Errorf("Exec after Close = %v, want ErrStmtClosed", err)
+		}
+	}
+	if _, err := db.Prepare("SELECT 3"); err == nil {
+		t.Error("Prepare on a closed connection succeeded")
+	}
+}
+
+func TestStmtClose(t *testing.T) {
+	db := connected(t)
+	stmt, err := db.Prepare("SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stmt.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := stmt.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if _, err := stmt.Exec(); !errors.Is(err, ErrStmtClosed) {
+		t.Errorf("Exec after stmt.Close = %v, want ErrStmtClosed", err)
+	}
+	if _, err := db.QueryArgs("SELECT 1"); err != nil {
+		t.Errorf("closing a statement broke the connection: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package singleton
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SetQueryLatency injects how long each simulated query takes to run.
+// Passing nil makes queries instant again.
+func (db *DatabaseConnection) SetQueryLatency(latency func(sql string) time.Duration) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.latency = latency
+}
+
+// QueryTimeout runs sql but gives up after d (or when ctx is done, if sooner).
+// A query that runs out of time returns an error wrapping context.DeadlineExceeded,
+// so callers can check it with errors.Is.
+func (db *DatabaseConnection) QueryTimeout(ctx context.Context, sql string, d time.Duration) ([]map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	if err := db.reconnectIfIdle(); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	if db.state != Connected {
+		db.mu.Unlock()
+		return nil, ErrNotConnected
+	}
+	if db.draining {
+		db.mu.Unlock()
+		return nil, ErrDraining
+	}
+	latency := db.latency
+	// Register as in flight so Drain waits for us
+	db.inFlight.Add(1)
+	defer db.inFlight.Done()
+	db.mu.Unlock()
+
+	// Simulate the server working on the query, without holding the lock
+	if latency != nil {
+		timer := time.NewTimer(latency(sql))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("query %q cancelled: %w", sql, ctx.Err())
+		case <-timer.C:
+		}
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	// The lock was released while the query ran, so the connection may have
+	// been closed or disconnected in the meantime. Its driver is gone then.
+	if db.state != Connected {
+		return nil, fmt.Errorf("query %q: connection %s while running: %w", sql, db.state, ErrNotConnected)
+	}
+	result, err := db.queryLocked(sql, nil)
+	return result.Rows, err
+}
@@ -0,0 +1,83 @@
+package singleton
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryTimeoutCompletes(t *testing.T) {
+	db := connected(t)
+	db.SetQueryLatency(func(string) time.Duration { return time.Millisecond })
+
+	rows, err := db.QueryTimeout(context.Background(), "SELECT 1", time.Hour)
+	if err != nil {
+		t.Fatalf("QueryTimeout: %v", err)
+	}
+	if rows == nil {
+		t.Error("QueryTimeout returned nil rows")
+	}
+	if sql, _ := db.LastQuery(); sql != "SELECT 1" {
+		t.Errorf("LastQuery() = %q, want the query recorded", sql)
+	}
+}
+
+func TestQueryTimeoutExceeded(t *testing.T) {
+	db := connected(t)
+	db.SetQueryLatency(func(sql string) time.Duration {
+		if sql == "SELECT slow" {
+			return time.Hour
+		}
+		return 0
+	})
+
+	_, err := db.QueryTimeout(context.Background(), "SELECT slow", time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("QueryTimeout = %v, want context.DeadlineExceeded", err)
+	}
+	if sql, _ := db.LastQuery(); sql != "" {
+		t.Errorf("the timed out query ran anyway: %q", sql)
+	}
+
+	// The connection is still usable afterwards
+	if _, err := db.QueryTimeout(context.Background(), "SELECT fast", time.Hour); err != nil {
+		t.Errorf("QueryTimeout after a timeout: %v", err)
+	}
+}
+
+func TestQueryTimeoutCallerCancels(t *testing.T) {
+	db := connected(t)
+	db.SetQueryLatency(func(string) time.Duration { return time.Hour })
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := db.QueryTimeout(ctx, "SELECT 1", time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("QueryTimeout = %v, want context.Canceled", err)
+	}
+}
+
+func TestQueryTimeoutNotConnected(t *testing.T) {
+	db := newConnection(defaultConnInfo)
+	if _, err := db.QueryTimeout(context.Background(), "SELECT 1", time.Hour); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("QueryTimeout = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestQueryTimeoutClosedWhileRunning(t *testing.T) {
+	db := connected(t)
+	db.SetQueryLatency(func(string) time.Duration {
+		// The latency runs without the lock, like a real round trip
+		if err := db.Close(); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+		return 0
+	})
+
+	if _, err := db.QueryTimeout(context.Background(), "SELECT 1", time.Hour); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("QueryTimeout = %v, want ErrNotConnected", err)
+	}
+	if sql, _ := db.LastQuery(); sql != "" {
+		t.Errorf("the query ran on a closed connection: %q", sql)
+	}
+}